@@ -0,0 +1,76 @@
+package main
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ipanardian/lu-hut/internal/config"
+	"github.com/ipanardian/lu-hut/internal/theme"
+	"github.com/spf13/cobra"
+)
+
+// registerCompletions adds value suggestions to flags whose choices are a
+// fixed set or come from the user's config, on top of the flag names cobra
+// already completes.
+func registerCompletions(rootCmd *cobra.Command, cfg *config.Config) {
+	fixed := map[string][]string{
+		"color":        {"always", "auto", "never"},
+		"border-style": {"single", "double", "bold", "ascii"},
+		"format":       {"table", "json", "flat"},
+		"only":         {"dirs", "files", "links", "exec"},
+		"sort":         {"name", "natural", "time", "created", "accessed", "size", "ext", "owner", "group", "inode", "links", "none"},
+		"time":         {"modified", "accessed", "created"},
+	}
+	for name, values := range fixed {
+		_ = rootCmd.RegisterFlagCompletionFunc(name, cobra.FixedCompletions(values, cobra.ShellCompDirectiveNoFileComp))
+	}
+
+	_ = rootCmd.RegisterFlagCompletionFunc("profile", func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		return profileCompletions(cfg.Profiles), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	_ = rootCmd.RegisterFlagCompletionFunc("theme", func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		// Anything that looks like a path completes theme files instead.
+		if strings.ContainsAny(toComplete, `/\`) || strings.HasPrefix(toComplete, ".") || strings.HasPrefix(toComplete, "~") {
+			return []cobra.Completion{"toml"}, cobra.ShellCompDirectiveFilterFileExt
+		}
+		return themeCompletions(cfg.Theme), cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// profileCompletions lists the configured profiles, described by the keys
+// each one sets.
+func profileCompletions(profiles config.Profiles) []cobra.Completion {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	completions := make([]cobra.Completion, 0, len(names))
+	for _, name := range names {
+		keys := make([]string, 0, len(profiles[name]))
+		for key := range profiles[name] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		completions = append(completions, cobra.CompletionWithDesc(name, "sets "+strings.Join(keys, ", ")))
+	}
+	return completions
+}
+
+// themeCompletions lists the presets plus the theme file named in the
+// config, if any.
+func themeCompletions(configured string) []cobra.Completion {
+	presets := theme.Presets()
+	completions := make([]cobra.Completion, 0, len(presets)+1)
+	for _, name := range presets {
+		completions = append(completions, cobra.CompletionWithDesc(name, "built-in theme"))
+	}
+	completions = append(completions, cobra.CompletionWithDesc(theme.Auto, "light or default, asking the terminal for its background"))
+	if configured != "" && !slices.Contains(presets, configured) {
+		completions = append(completions, cobra.CompletionWithDesc(configured, "theme from your config"))
+	}
+	return completions
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/ipanardian/lu-hut/internal/config"
+	"github.com/ipanardian/lu-hut/internal/theme"
+	"github.com/spf13/cobra"
+)
+
+func newConfigCommand() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage lu configuration",
+	}
+
+	configCmd.AddCommand(newConfigPathCommand())
+	configCmd.AddCommand(newImportDircolorsCommand())
+	configCmd.AddCommand(newConfigMigrateCommand())
+
+	configCmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		fmt.Println()
+		color.Cyan("lu config - Manage lu configuration")
+		fmt.Println()
+		fmt.Println("USAGE:")
+		fmt.Println("  lu config <command>")
+		fmt.Println()
+		fmt.Println("COMMANDS:")
+		fmt.Println("  path                      print the location of the config file")
+		fmt.Println("  import-dircolors [file]   convert a dircolors database into a lu theme")
+		fmt.Println("  migrate [file]            update a config file to the current layout")
+		fmt.Println()
+	})
+
+	return configCmd
+}
+
+func newImportDircolorsCommand() *cobra.Command {
+	var output string
+
+	importCmd := &cobra.Command{
+		Use:   "import-dircolors [file]",
+		Short: "Convert a dircolors database into a lu theme",
+		Long: `Convert a dircolors database (e.g. ~/.dircolors) into a lu theme file.
+
+The theme is written to stdout unless --output is given. Use it with:
+  lu --theme path/to/theme.toml`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var in io.Reader = os.Stdin
+			if len(args) == 1 && args[0] != "-" {
+				f, err := os.Open(args[0])
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				in = f
+			}
+
+			if output == "" {
+				return theme.ConvertDircolors(in, os.Stdout)
+			}
+
+			f, err := os.Create(output)
+			if err != nil {
+				return err
+			}
+			if err := theme.ConvertDircolors(in, f); err != nil {
+				f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+
+			color.Green("Theme written to %s", output)
+			fmt.Printf("Use it with: lu --theme %s\n", output)
+			return nil
+		},
+	}
+
+	importCmd.Flags().StringVarP(&output, "output", "o", "", "write the theme to a file instead of stdout")
+
+	importCmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		fmt.Println()
+		color.Cyan("lu config import-dircolors - Convert a dircolors database into a lu theme")
+		fmt.Println()
+		fmt.Println("USAGE:")
+		fmt.Println("  lu config import-dircolors [file] [flags]")
+		fmt.Println()
+		fmt.Println("FLAGS:")
+		fmt.Println("  -o, --output   write the theme to a file instead of stdout")
+		fmt.Println("      --help     help for import-dircolors")
+		fmt.Println()
+		fmt.Println("EXAMPLES:")
+		fmt.Println("  lu config import-dircolors ~/.dircolors -o ~/.config/lu-hut/dircolors.toml")
+		fmt.Println("  dircolors -p | lu config import-dircolors > theme.toml")
+		fmt.Println()
+	})
+
+	return importCmd
+}
+
+func newConfigPathCommand() *cobra.Command {
+	pathCmd := &cobra.Command{
+		Use:   "path",
+		Short: "Print the location of the config file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := config.Path()
+			if err != nil {
+				return err
+			}
+			fmt.Println(path)
+			return nil
+		},
+	}
+
+	pathCmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		fmt.Println()
+		color.Cyan("lu config path - Print the location of the config file")
+		fmt.Println()
+		fmt.Println("USAGE:")
+		fmt.Println("  lu config path")
+		fmt.Println()
+		fmt.Println("EXAMPLES:")
+		fmt.Println("  $EDITOR \"$(lu config path)\"")
+		fmt.Println()
+	})
+
+	return pathCmd
+}
+
+func newConfigMigrateCommand() *cobra.Command {
+	var dryRun bool
+
+	migrateCmd := &cobra.Command{
+		Use:   "migrate [file]",
+		Short: "Update a config file to the current layout",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := ""
+			if len(args) == 1 {
+				path = args[0]
+			} else {
+				var err error
+				if path, err = config.Path(); err != nil {
+					return err
+				}
+			}
+
+			renamed, changed, err := config.MigrateFile(path, dryRun)
+			if err != nil {
+				return err
+			}
+			if !changed {
+				color.Green("%s is up to date (version %d)", path, config.SchemaVersion)
+				return nil
+			}
+
+			for _, r := range renamed {
+				fmt.Printf("  renamed %s\n", r)
+			}
+			fmt.Printf("  set version = %d\n", config.SchemaVersion)
+			if dryRun {
+				color.Yellow("Dry run, %s was not changed", path)
+				return nil
+			}
+			color.Green("Migrated %s (previous version saved as %s.bak)", path, path)
+			return nil
+		},
+	}
+
+	migrateCmd.Flags().BoolVarP(&dryRun, "dry-run", "n", false, "show the changes without writing the file")
+
+	migrateCmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		fmt.Println()
+		color.Cyan("lu config migrate - Update a config file to the current layout")
+		fmt.Println()
+		fmt.Println("USAGE:")
+		fmt.Println("  lu config migrate [file] [flags]")
+		fmt.Println()
+		fmt.Println("FLAGS:")
+		fmt.Println("  -n, --dry-run   show the changes without writing the file")
+		fmt.Println("      --help      help for migrate")
+		fmt.Println()
+		fmt.Println("  Renamed keys are rewritten in place, keeping comments, and the file")
+		fmt.Println("  gets a version key. The original is kept next to it as <file>.bak.")
+		fmt.Println()
+		fmt.Println("EXAMPLES:")
+		fmt.Println("  lu config migrate --dry-run")
+		fmt.Println("  lu config migrate .lu-hut.toml")
+		fmt.Println()
+	})
+
+	return migrateCmd
+}
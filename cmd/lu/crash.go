@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/ipanardian/lu-hut/internal/constants"
+	"github.com/ipanardian/lu-hut/internal/state"
+	"github.com/ipanardian/lu-hut/internal/terminal"
+)
+
+const issuesURL = "https://github.com/ipanardian/lu-hut/issues"
+
+// recoverCrash is deferred by main. A panic anywhere in a command ends here
+// instead of dumping a stack trace over a half-drawn table: the terminal is
+// reset, the trace goes to a crash report, and lu exits with status 2 like
+// an unrecovered panic would.
+func recoverCrash() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	stack := debug.Stack()
+
+	terminal.Reset()
+	fmt.Fprintf(os.Stderr, "\nlu crashed: %v\n", r)
+	if path, err := writeCrashReport(r, stack); err == nil {
+		fmt.Fprintf(os.Stderr, "A crash report was saved to %s\nPlease attach it to a new issue at %s\n", path, issuesURL)
+	} else {
+		fmt.Fprintf(os.Stderr, "Please include the following in a new issue at %s\n\n%s", issuesURL, stack)
+	}
+	os.Exit(2)
+}
+
+// writeCrashReport saves what is needed to reproduce a crash to a new file
+// in the cache directory and returns its path.
+func writeCrashReport(r any, stack []byte) (string, error) {
+	now := time.Now()
+	path, err := state.CachePath("crashes", "crash-"+now.Format("20060102-150405")+".txt")
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "lu %s (%s, %s/%s)\n", constants.Version, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "time: %s\n", now.Format(time.RFC3339))
+	fmt.Fprintf(&b, "args: %q\n", os.Args[1:])
+	fmt.Fprintf(&b, "panic: %v\n\n%s", r, stack)
+
+	if err := state.WriteFile(path, []byte(b.String()), 0o600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
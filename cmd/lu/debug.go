@@ -0,0 +1,17 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ipanardian/lu-hut/internal/config"
+	"github.com/ipanardian/lu-hut/internal/doctor"
+)
+
+// printDebug writes the environment report for --debug to stderr, so it
+// can be pasted into a bug report without mixing into the listing.
+func printDebug(cfg config.Config) {
+	for _, fact := range doctor.Environment(cfg) {
+		fmt.Fprintf(os.Stderr, "debug: %-12s %s\n", fact.Name+":", fact.Value)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/ipanardian/lu-hut/internal/config"
+	"github.com/ipanardian/lu-hut/internal/lister"
+	"github.com/spf13/cobra"
+)
+
+func newDirtyCommand() *cobra.Command {
+	var (
+		format    string
+		colorMode string
+		exactTime bool
+		showUser  bool
+	)
+
+	dirtyCmd := &cobra.Command{
+		Use:   "dirty [path]",
+		Short: "List every changed and untracked file in the git worktree",
+		Long: `List the modified, added, deleted, renamed and untracked files of the whole
+git worktree, from anywhere inside it, as one table with paths relative to
+the repository root.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := "."
+			if len(args) == 1 {
+				dir = args[0]
+			}
+
+			// Colors, theme, sorting and filters follow the config file;
+			// options that shape a directory listing do not apply here.
+			cfg := config.NewDefaultConfig()
+			if err := loadDefaults(&cfg); err != nil {
+				return err
+			}
+			cfg.Recursive, cfg.Tree, cfg.DiskUsage, cfg.Follow = false, false, false, false
+			cfg.ShowUser = showUser
+			if cmd.Flags().Changed("exact-time") {
+				cfg.ShowExactTime = exactTime
+			}
+			if format != "" {
+				cfg.Format = format
+			}
+			if colorMode != "" {
+				cfg.ColorMode = colorMode
+			}
+			if err := cfg.Validate(); err != nil {
+				return err
+			}
+
+			l, err := lister.New(cfg)
+			if err != nil {
+				return err
+			}
+			return l.ListDirty(dir)
+		},
+	}
+
+	dirtyCmd.Flags().StringVar(&format, "format", "", "output format (table|json)")
+	dirtyCmd.Flags().StringVar(&colorMode, "color", "", "color output mode (always|auto|never)")
+	dirtyCmd.Flags().BoolVarP(&exactTime, "exact-time", "T", false, "show exact modification time instead of relative")
+	dirtyCmd.Flags().BoolVarP(&showUser, "user", "u", false, "show user and group ownership metadata")
+	dirtyCmd.Flags().Bool("help", false, "help for dirty")
+
+	dirtyCmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		fmt.Println()
+		color.Cyan("lu dirty - List every changed and untracked file in the git worktree")
+		fmt.Println()
+		fmt.Println("USAGE:")
+		fmt.Println("  lu dirty [path] [flags]")
+		fmt.Println()
+		fmt.Println("FLAGS:")
+		fmt.Println("      --format       output format: table (default) or json")
+		fmt.Println("      --color        color output mode: always, auto or never")
+		fmt.Println("  -T, --exact-time   show exact modification time instead of relative")
+		fmt.Println("  -u, --user         show user and group ownership metadata")
+		fmt.Println("      --help         help for dirty")
+		fmt.Println()
+		fmt.Println("EXAMPLES:")
+		fmt.Println("  lu dirty                 # from any directory inside the repository")
+		fmt.Println("  lu dirty --format json   # for scripts")
+		fmt.Println()
+	})
+
+	return dirtyCmd
+}
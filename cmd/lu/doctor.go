@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/ipanardian/lu-hut/internal/doctor"
+	"github.com/spf13/cobra"
+)
+
+func newDoctorCommand() *cobra.Command {
+	doctorCmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the config, theme, terminal, git and cache setup",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			results := doctor.Run(".")
+
+			var section string
+			warnings, failures := 0, 0
+			for _, r := range results {
+				if r.Section != section {
+					section = r.Section
+					fmt.Println()
+					color.New(color.FgWhite, color.Bold).Println(section)
+				}
+
+				mark := color.GreenString("✓")
+				switch r.Status {
+				case doctor.Warn:
+					mark = color.YellowString("!")
+					warnings++
+				case doctor.Fail:
+					mark = color.RedString("✗")
+					failures++
+				}
+				fmt.Printf("  %s %-13s %s\n", mark, r.Name, r.Detail)
+				if r.Hint != "" {
+					fmt.Printf("    %s %s\n", color.CyanString("→"), r.Hint)
+				}
+			}
+
+			fmt.Println()
+			if failures > 0 {
+				return fmt.Errorf("doctor found problems (failures: %d)", failures)
+			}
+			if warnings > 0 {
+				color.Yellow("No problems found (warnings: %d)", warnings)
+				return nil
+			}
+			color.Green("Everything looks good")
+			return nil
+		},
+	}
+
+	doctorCmd.Flags().Bool("help", false, "help for doctor")
+
+	doctorCmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		fmt.Println()
+		color.Cyan("lu doctor - Check the config, theme, terminal, git and cache setup")
+		fmt.Println()
+		fmt.Println("USAGE:")
+		fmt.Println("  lu doctor")
+		fmt.Println()
+		fmt.Println("CHECKS:")
+		fmt.Println("  config     config file, LU_* variables, .lu-hut.toml and the selected profile")
+		fmt.Println("  theme      the configured theme loads")
+		fmt.Println("  terminal   color, truecolor, unicode, width source and Nerd Font availability")
+		fmt.Println("  git        git is installed and runs")
+		fmt.Println("  cache      cache directory and the last update check")
+		fmt.Println()
+		fmt.Println("  Warnings come with a suggested fix; failures make the command exit non-zero.")
+		fmt.Println()
+	})
+
+	return doctorCmd
+}
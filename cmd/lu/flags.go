@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ipanardian/lu-hut/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// notNegatable are boolean flags whose --no- form would only be confusing.
+var notNegatable = map[string]bool{
+	"help": true,
+	"yes":  true,
+	"tee":  true,
+	// --no-junk is already the negative; --no-junk=false turns it off.
+	"no-junk": true,
+}
+
+// negatedValue sets the wrapped boolean flag to the opposite of its input,
+// so --no-git behaves like --git=false.
+type negatedValue struct {
+	target pflag.Value
+}
+
+func (n negatedValue) Set(s string) error {
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	return n.target.Set(strconv.FormatBool(!v))
+}
+
+func (n negatedValue) String() string { return "false" }
+func (n negatedValue) Type() string   { return "bool" }
+
+// addNegationFlags adds a hidden --no-NAME flag for every boolean flag, so
+// defaults coming from the config file can be switched off per invocation.
+func addNegationFlags(flags *pflag.FlagSet) {
+	var names []string
+	flags.VisitAll(func(f *pflag.Flag) {
+		if f.Value.Type() == "bool" && !notNegatable[f.Name] {
+			names = append(names, f.Name)
+		}
+	})
+
+	for _, name := range names {
+		target := flags.Lookup(name)
+		negated := flags.VarPF(negatedValue{target: target.Value}, "no-"+name, "", "turn off --"+name)
+		negated.NoOptDefVal = "true"
+		negated.Hidden = true
+	}
+}
+
+// flagGiven reports whether the option was set on the command line in
+// either its positive or its --no- form.
+func flagGiven(cmd *cobra.Command, name string) bool {
+	return cmd.Flags().Changed(name) || cmd.Flags().Changed("no-"+name)
+}
+
+// sortFlags are the shorthand switches for a sort order, in the order they
+// are reported when several are given.
+var sortFlags = []struct{ name, order string }{
+	{"sort-modified", "time"},
+	{"sort-size", "size"},
+	{"sort-extension", "ext"},
+	{"sort-natural", "natural"},
+}
+
+// applySortFlags lets the sort order given on the command line replace the
+// one from the config and default_flags, and rejects more than one order
+// at a time. Orders from default_flags are not counted, since they are not
+// marked as given.
+func applySortFlags(cmd *cobra.Command, cfg *config.Config) error {
+	var given []string
+	order := ""
+	if cmd.Flags().Changed("sort") {
+		given = append(given, "--sort")
+		order = cfg.Sort
+	}
+	for _, flag := range sortFlags {
+		if cmd.Flags().Changed(flag.name) && cmd.Flags().Lookup(flag.name).Value.String() == "true" {
+			given = append(given, "--"+flag.name)
+			order = flag.order
+		}
+	}
+
+	if len(given) > 1 {
+		return fmt.Errorf("%s cannot be combined; pick one sort order", strings.Join(given, ", "))
+	}
+	if order == "" {
+		return nil
+	}
+	if err := cfg.SetSort(order); err != nil {
+		return fmt.Errorf("invalid --sort: %w", err)
+	}
+	return nil
+}
+
+// applyDefaultFlags parses the configured default_flags into the root
+// command's flags as a layer of their own, unless a subcommand runs. They
+// are then marked as not given, so the command line, .lu-hut.toml and
+// profiles can still override them like any other configured default.
+func applyDefaultFlags(rootCmd *cobra.Command, defaults, args []string) error {
+	if len(defaults) == 0 {
+		return nil
+	}
+	// Shell completion requests are answered by a command cobra only adds
+	// at execution time, so Find does not know them yet.
+	if len(args) > 0 && (args[0] == cobra.ShellCompRequestCmd || args[0] == cobra.ShellCompNoDescRequestCmd) {
+		return nil
+	}
+	if cmd, _, err := rootCmd.Find(args); err == nil && cmd != rootCmd {
+		return nil
+	}
+
+	flags := rootCmd.Flags()
+	if err := flags.Parse(defaults); err != nil {
+		return fmt.Errorf("invalid default_flags: %w", err)
+	}
+	if extra := flags.Args(); len(extra) > 0 {
+		return fmt.Errorf("invalid default_flags: %q is not a flag", extra[0])
+	}
+	flags.VisitAll(func(f *pflag.Flag) {
+		f.Changed = false
+	})
+	return nil
+}
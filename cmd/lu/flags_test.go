@@ -0,0 +1,76 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ipanardian/lu-hut/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// sortCommand is a root command with just the sort flags, bound to cfg.
+func sortCommand(cfg *config.Config) *cobra.Command {
+	cmd := &cobra.Command{Use: "lu", Run: func(*cobra.Command, []string) {}}
+	cmd.Flags().BoolVarP(&cfg.SortModified, "sort-modified", "t", cfg.SortModified, "")
+	cmd.Flags().BoolVarP(&cfg.SortSize, "sort-size", "S", cfg.SortSize, "")
+	cmd.Flags().BoolVarP(&cfg.SortExtension, "sort-extension", "X", cfg.SortExtension, "")
+	cmd.Flags().BoolVar(&cfg.SortNatural, "sort-natural", cfg.SortNatural, "")
+	cmd.Flags().StringVar(&cfg.Sort, "sort", cfg.Sort, "")
+	addNegationFlags(cmd.Flags())
+	return cmd
+}
+
+func TestDefaultFlagsOverridden(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		order string
+	}{
+		{name: "default only", args: nil, order: "size"},
+		{name: "shorthand", args: []string{"-t"}, order: "time"},
+		{name: "sort flag", args: []string{"--sort", "name"}, order: "name"},
+		{name: "negated", args: []string{"--no-sort-size"}, order: "name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewDefaultConfig()
+			cmd := sortCommand(&cfg)
+			if err := applyDefaultFlags(cmd, []string{"-S"}, tt.args); err != nil {
+				t.Fatalf("applyDefaultFlags() error = %v", err)
+			}
+			if flagGiven(cmd, "sort-size") {
+				t.Error("a default flag counts as given on the command line")
+			}
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			if err := applySortFlags(cmd, &cfg); err != nil {
+				t.Fatalf("applySortFlags() error = %v", err)
+			}
+			if got := cfg.SortOrder(); got != tt.order {
+				t.Errorf("sort order = %q, want %q", got, tt.order)
+			}
+		})
+	}
+}
+
+func TestDefaultFlagsConflictOnCommandLine(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	cmd := sortCommand(&cfg)
+	if err := applyDefaultFlags(cmd, []string{"-S"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.ParseFlags([]string{"-t", "-X"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := applySortFlags(cmd, &cfg); err == nil {
+		t.Error("applySortFlags() accepted two sort orders typed together")
+	}
+}
+
+func TestDefaultFlagsRejectArguments(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	if err := applyDefaultFlags(sortCommand(&cfg), []string{"-S", "src"}, nil); err == nil {
+		t.Error("applyDefaultFlags() accepted a path in default_flags")
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ipanardian/lu-hut/internal/terminal"
+	"github.com/spf13/cobra"
+)
+
+// newHelpCommand replaces cobra's help command so lu help TOPIC can show
+// the topic pages as well as the help of a subcommand.
+func newHelpCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "help [topic|command]",
+		Short: "Show help for lu, a topic (flags, formats, themes) or a command",
+		Args:  cobra.MaximumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return terminal.HelpTopics, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			if len(args) == 0 {
+				terminal.ShowColoredHelp(root)
+				return nil
+			}
+			if terminal.ShowHelpTopic(args[0]) {
+				return nil
+			}
+
+			target, _, err := root.Find(args)
+			if err != nil || target == root {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("unknown help topic %q (topics: %s, or a command name)", args[0], strings.Join(terminal.HelpTopics, ", "))
+			}
+			return target.Help()
+		},
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/ipanardian/lu-hut/internal/linkaudit"
+	"github.com/spf13/cobra"
+)
+
+func newLinkAuditCommand() *cobra.Command {
+	linkAuditCmd := &cobra.Command{
+		Use:   "link-audit [path]",
+		Short: "Group symlinks by target and report broken or stray links",
+		Long: `Walk a directory tree and group its symlinks by the directory they point
+into. Broken links, links that leave the tree, and target directories
+reached through both relative and absolute links are reported.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := "."
+			if len(args) == 1 {
+				dir = args[0]
+			}
+
+			result, err := linkaudit.Audit(dir)
+			if err != nil {
+				return err
+			}
+			if len(result.Groups) == 0 {
+				fmt.Printf("No symlinks found in %s\n", result.Root)
+				return nil
+			}
+
+			broken, escaping, mixed, total := 0, 0, 0, 0
+			for _, g := range result.Groups {
+				fmt.Println()
+				header := color.New(color.FgWhite, color.Bold).Sprint(g.Dir)
+				if g.Mixed() {
+					mixed++
+					header += " " + color.YellowString("! mixed relative and absolute links")
+				}
+				fmt.Println(header)
+
+				for _, link := range g.Links {
+					total++
+					mark := color.GreenString("✓")
+					var note string
+					switch {
+					case link.Broken:
+						mark, note = color.RedString("✗"), color.RedString("broken")
+						broken++
+					case link.Escapes:
+						mark, note = color.YellowString("!"), color.YellowString("outside the tree")
+						escaping++
+					}
+					line := fmt.Sprintf("  %s %s → %s", mark, link.Path, link.Target)
+					if note != "" {
+						line += " " + note
+					}
+					fmt.Println(line)
+				}
+			}
+
+			fmt.Println()
+			summary := fmt.Sprintf("%d links into %d directories (broken: %d, outside the tree: %d, mixed: %d)",
+				total, len(result.Groups), broken, escaping, mixed)
+			switch {
+			case broken > 0:
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%s", summary)
+			case escaping > 0 || mixed > 0:
+				color.Yellow(summary)
+			default:
+				color.Green(summary)
+			}
+			return nil
+		},
+	}
+
+	linkAuditCmd.Flags().Bool("help", false, "help for link-audit")
+
+	linkAuditCmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		fmt.Println()
+		color.Cyan("lu link-audit - Group symlinks by target and report broken or stray links")
+		fmt.Println()
+		fmt.Println("USAGE:")
+		fmt.Println("  lu link-audit [path]")
+		fmt.Println()
+		fmt.Println("REPORTS:")
+		fmt.Println("  broken    the target does not exist")
+		fmt.Println("  outside   the target lies outside the audited tree")
+		fmt.Println("  mixed     a target directory is reached through both relative and")
+		fmt.Println("            absolute links")
+		fmt.Println()
+		fmt.Println("  Symlinked directories are not followed. Broken links make the command")
+		fmt.Println("  exit non-zero; the other findings are warnings.")
+		fmt.Println()
+		fmt.Println("EXAMPLES:")
+		fmt.Println("  lu link-audit /srv/app          # before switching a release")
+		fmt.Println()
+	})
+
+	return linkAuditCmd
+}
@@ -12,8 +12,10 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/ipanardian/lu-hut/internal/config"
 	"github.com/ipanardian/lu-hut/internal/constants"
@@ -24,6 +26,7 @@ import (
 )
 
 func main() {
+	defer recoverCrash()
 	go updater.CheckAndNotify()
 
 	if err := newRootCommand().Execute(); err != nil {
@@ -33,65 +36,202 @@ func main() {
 
 func newRootCommand() *cobra.Command {
 	cfg := config.NewDefaultConfig()
+	configErr := loadDefaults(&cfg)
 
 	rootCmd := &cobra.Command{
-		Use:   "lu [path]",
+		Use:   "lu [path...]",
 		Short: "A modern alternative to the Unix ls command with table formatting",
 		Long: `lu-hut is a powerful modern alternative to the Unix ls command with beautiful box-drawn tables or stunning tree format, intelligent colors, multiple sorting strategies, advanced filtering, and seamless git integration.
 
 GitHub: https://github.com/ipanardian/lu-hut
 Version: ` + constants.Version,
-		Args:    cobra.MaximumNArgs(1),
+		Args:    cobra.ArbitraryArgs,
 		Version: constants.Version,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			path := "."
-			if len(args) > 0 {
-				path = args[0]
+			if configErr != nil {
+				return configErr
+			}
+
+			paths := args
+			if len(paths) == 0 {
+				paths = []string{"."}
+			}
+
+			given := func(key string) bool { return flagGiven(cmd, flagForKey(key)) }
+			if path, ok := config.FindLocal(paths[0]); ok {
+				if err := cfg.LoadLocal(path, given); err != nil {
+					return err
+				}
+			}
+			if cfg.Profile != "" {
+				if err := cfg.ApplyProfile(cfg.Profile, given); err != nil {
+					return err
+				}
+			}
+			if err := applySortFlags(cmd, &cfg); err != nil {
+				return err
 			}
 
 			if err := cfg.Validate(); err != nil {
 				return err
 			}
 
-			if path != "." {
-				if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			if !flagGiven(cmd, "ascii") && !terminal.SupportsUnicode() {
+				cfg.ASCII = true
+			}
+			if cfg.Debug {
+				printDebug(cfg)
+			}
+
+			if len(paths) == 1 && paths[0] != "." {
+				if info, err := os.Stat(paths[0]); err == nil && !info.IsDir() {
 					if len(cfg.IncludePatterns) > 0 {
-						cfg.IncludePatterns = append(cfg.IncludePatterns, path)
-						path = "."
+						cfg.IncludePatterns = append(cfg.IncludePatterns, paths[0])
+						paths[0] = "."
 					}
 				}
 			}
 
-			lister := lister.New(cfg)
-			return lister.List(path)
+			lister, err := lister.New(cfg)
+			if err != nil {
+				return err
+			}
+			if err := lister.List(paths...); err != nil {
+				return err
+			}
+			if n := lister.Deviations(); cfg.Strict && n > 0 {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%d entries do not have the permissions --expect-perms asks for", n)
+			}
+			return nil
 		},
 	}
 
-	rootCmd.Flags().StringVar(&cfg.ColorMode, "color", "", "color output mode (always|auto|never)")
-	rootCmd.Flags().BoolVarP(&cfg.SortModified, "sort-modified", "t", false, "sort by modified time (newest first)")
-	rootCmd.Flags().BoolVarP(&cfg.SortSize, "sort-size", "S", false, "sort by file size (largest first)")
-	rootCmd.Flags().BoolVarP(&cfg.SortExtension, "sort-extension", "X", false, "sort by file extension")
-	rootCmd.Flags().BoolVarP(&cfg.Reverse, "reverse", "r", false, "reverse sort order")
-	rootCmd.Flags().BoolVarP(&cfg.ShowGit, "git", "g", false, "show git status inline")
-	rootCmd.Flags().BoolVarP(&cfg.ShowHidden, "hidden", "h", false, "show hidden files")
-	rootCmd.Flags().BoolVarP(&cfg.ShowUser, "user", "u", false, "show user and group ownership metadata")
-	rootCmd.Flags().BoolVarP(&cfg.ShowExactTime, "exact-time", "T", false, "show exact modification time instead of relative")
-	rootCmd.Flags().BoolVarP(&cfg.ShowOctal, "octal", "o", false, "show octal permissions instead of rwx")
-	rootCmd.Flags().BoolVarP(&cfg.Tree, "tree", "F", false, "display directory structure in a tree format")
-	rootCmd.Flags().BoolVarP(&cfg.Recursive, "recursive", "R", false, "list subdirectories recursively")
+	rootCmd.Flags().StringVar(&cfg.ColorMode, "color", cfg.ColorMode, "color output mode (always|auto|never)")
+	rootCmd.Flags().StringVar(&cfg.Profile, "profile", cfg.Profile, "apply a named profile from the config file")
+	rootCmd.Flags().StringVar(&cfg.Theme, "theme", cfg.Theme, "color theme name or path to a theme file")
+	rootCmd.Flags().StringVar(&cfg.BorderColor, "border-color", cfg.BorderColor, "table border style, e.g. 'blue' or '#5f87af' (overrides the theme)")
+	rootCmd.Flags().StringVar(&cfg.HeaderColor, "header-color", cfg.HeaderColor, "table header style, e.g. 'hi-white bold' (overrides the theme)")
+	rootCmd.Flags().StringVar(&cfg.BorderStyle, "border-style", cfg.BorderStyle, "table border characters (single|double|bold|ascii)")
+	rootCmd.Flags().BoolVarP(&cfg.SortModified, "sort-modified", "t", cfg.SortModified, "sort by modified time (newest first)")
+	rootCmd.Flags().BoolVarP(&cfg.SortSize, "sort-size", "S", cfg.SortSize, "sort by file size (largest first)")
+	rootCmd.Flags().BoolVarP(&cfg.SortExtension, "sort-extension", "X", cfg.SortExtension, "sort by file extension")
+	rootCmd.Flags().BoolVar(&cfg.SortNatural, "sort-natural", cfg.SortNatural, "sort names with numbers in numeric order (file2 before file10)")
+	rootCmd.Flags().StringVar(&cfg.Sort, "sort", cfg.Sort, "sort order (name|natural|time|created|accessed|size|ext|owner|group|inode|links|none)")
+	rootCmd.Flags().BoolVarP(&cfg.Reverse, "reverse", "r", cfg.Reverse, "reverse sort order")
+	rootCmd.Flags().BoolVar(&cfg.DirsLast, "dirs-last", cfg.DirsLast, "list directories after files")
+	rootCmd.Flags().BoolVar(&cfg.CaseSensitive, "case-sensitive", cfg.CaseSensitive, "sort names byte by byte, uppercase before lowercase (like ls in the C locale)")
+	rootCmd.Flags().BoolVar(&cfg.GroupDirs, "group-dirs", cfg.GroupDirs, "keep directories together (--no-group-dirs sorts them among the files)")
+	rootCmd.Flags().BoolVarP(&cfg.ShowGit, "git", "g", cfg.ShowGit, "show git status inline")
+	rootCmd.Flags().BoolVar(&cfg.Conflicts, "conflicts", false, "only list paths with unresolved merge conflicts (and directories containing them)")
+	rootCmd.Flags().BoolVar(&cfg.GitDirty, "git-dirty", false, "only list modified, staged or untracked paths (and directories containing them)")
+	rootCmd.Flags().BoolVar(&cfg.GitIgnore, "git-ignore", cfg.GitIgnore, "hide files ignored by git (.gitignore, info/exclude and core.excludesFile)")
+	rootCmd.Flags().BoolVar(&cfg.GitBlame, "git-blame", cfg.GitBlame, "show the author, date and hash of the last commit touching each entry")
+	rootCmd.Flags().BoolVarP(&cfg.ShowHidden, "hidden", "h", cfg.ShowHidden, "show hidden files")
+	rootCmd.Flags().BoolVar(&cfg.SkipSpecial, "skip-special", cfg.SkipSpecial, "omit device nodes, FIFOs and sockets")
+	rootCmd.Flags().BoolVarP(&cfg.ShowUser, "user", "u", cfg.ShowUser, "show user and group ownership metadata")
+	rootCmd.Flags().BoolVarP(&cfg.ShowExactTime, "exact-time", "T", cfg.ShowExactTime, "show exact modification time instead of relative")
+	rootCmd.Flags().BoolVar(&cfg.AlignUnits, "align-units", cfg.AlignUnits, "right-align sizes so their units line up")
+	rootCmd.Flags().StringVar(&cfg.TimeField, "time", cfg.TimeField, "timestamp shown in the time column (modified|accessed|created)")
+	rootCmd.Flags().BoolVarP(&cfg.ShowOctal, "octal", "o", cfg.ShowOctal, "show octal permissions instead of rwx")
+	rootCmd.Flags().BoolVar(&cfg.ShowIcons, "icons", cfg.ShowIcons, "show Nerd Font icons next to names")
+	rootCmd.Flags().StringToStringVar(&cfg.IconOverrides, "icon-map", cfg.IconOverrides, "override icons by name, extension or kind (e.g. .go=X,dir=Y)")
+	rootCmd.Flags().BoolVar(&cfg.ShowMedia, "media", cfg.ShowMedia, "show image dimensions and audio/video duration")
+	rootCmd.Flags().BoolVar(&cfg.ShowCompress, "compressibility", cfg.ShowCompress, "estimate how well each file would compress (experimental)")
+	rootCmd.Flags().BoolVar(&cfg.ShowInode, "inode", cfg.ShowInode, "show inode numbers and hard-link counts")
+	rootCmd.Flags().BoolVar(&cfg.ShowEntries, "archive-count", cfg.ShowEntries, "show the number of entries in zip and tar archives")
+	rootCmd.Flags().BoolVar(&cfg.ShowProject, "project", cfg.ShowProject, "show project name and version when a manifest (go.mod, package.json, ...) is found")
+	rootCmd.Flags().BoolVar(&cfg.ShowShortcuts, "shortcuts", cfg.ShowShortcuts, "show where .desktop, .lnk and macOS alias files point")
+	rootCmd.Flags().BoolVar(&cfg.ShowTags, "tags", cfg.ShowTags, "show Finder tags and user.xdg.tags in a Tags column")
+	rootCmd.Flags().StringSliceVar(&cfg.FilterTags, "tag", cfg.FilterTags, "only list entries carrying one of these tags")
+	rootCmd.Flags().StringSliceVar(&cfg.FilterOwner, "owner", cfg.FilterOwner, "only list entries owned by one of these users")
+	rootCmd.Flags().StringSliceVar(&cfg.FilterGroup, "group", cfg.FilterGroup, "only list entries belonging to one of these groups")
+	rootCmd.Flags().BoolVar(&cfg.ShowGenerated, "generated", cfg.ShowGenerated, "show generated files and lockfiles, dimmed (--no-generated hides them)")
+	rootCmd.Flags().BoolVar(&cfg.GeneratedHeader, "generated-headers", cfg.GeneratedHeader, "also read the start of source files to recognize generated code")
+	rootCmd.Flags().StringSliceVar(&cfg.Highlight, "highlight", cfg.Highlight, "emphasize names matching glob patterns without hiding the rest (quote the pattern)")
+	rootCmd.Flags().BoolVar(&cfg.Badges, "badges", cfg.Badges, "show text markers like [exec], [link], [big] and [M] next to names")
+	rootCmd.Flags().BoolVar(&cfg.ASCII, "ascii", cfg.ASCII, "draw tables and trees with plain ASCII characters only (auto-detected when unset)")
+	rootCmd.Flags().StringVar(&cfg.ExpectPerms, "expect-perms", cfg.ExpectPerms, "highlight entries whose mode differs, e.g. 0644:files,0755:dirs")
+	rootCmd.Flags().BoolVar(&cfg.Strict, "strict", false, "with --expect-perms, exit non-zero when any entry differs")
+	rootCmd.Flags().BoolVar(&cfg.ExplainPerms, "explain-perms", cfg.ExplainPerms, "describe unusual modes in plain words, e.g. setuid root binary")
+	rootCmd.Flags().BoolVar(&cfg.StrictNames, "strict-names", cfg.StrictNames, "warn about names with invisible, bidi control or non-NFC characters")
+	rootCmd.Flags().StringArrayVar(&cfg.ColorRules, "color-rule", cfg.ColorRules, "color names matching a pattern, e.g. '*.sql=magenta bold' (repeatable)")
+	rootCmd.Flags().StringVar(&cfg.ColorSpec, "color-spec", cfg.ColorSpec, "override name colors with dircolors syntax, e.g. 'di=34:*.go=32'")
+	rootCmd.Flags().StringVar(&cfg.Exec, "exec", "", "run a command for each listed entry ({} is replaced by the path)")
+	rootCmd.Flags().BoolVarP(&cfg.AssumeYes, "yes", "y", false, "do not ask for confirmation before destructive --exec commands")
+	rootCmd.Flags().BoolVarP(&cfg.Tree, "tree", "F", cfg.Tree, "display directory structure in a tree format")
+	rootCmd.Flags().BoolVarP(&cfg.Recursive, "recursive", "R", cfg.Recursive, "list subdirectories recursively")
+	rootCmd.Flags().BoolVar(&cfg.SinceLastRun, "changed-since-last", false, "only list entries added or modified since the previous run with this flag")
+	rootCmd.Flags().BoolVar(&cfg.DiskUsage, "du", cfg.DiskUsage, "show the total size of each directory's contents")
+	rootCmd.Flags().BoolVar(&cfg.Follow, "follow", cfg.Follow, "with --du, follow symlinks (shared content is counted once)")
+	rootCmd.Flags().BoolVar(&cfg.Pick, "pick", false, "choose entries interactively and print their paths (space marks, enter confirms)")
+	rootCmd.Flags().BoolVar(&cfg.Print0, "print0", false, "with --pick, separate the printed paths with NUL instead of newlines")
+	rootCmd.Flags().StringVar(&cfg.SessionLog, "session-log", "", "with --pick, append every directory visited and action taken to FILE as JSON lines")
 	rootCmd.Flags().IntVarP(&cfg.MaxDepth, "max-depth", "L", cfg.MaxDepth, "maximum recursion depth (0 = no limit, default: 30)")
-	rootCmd.Flags().StringSliceVarP(&cfg.IncludePatterns, "include", "i", nil, "include files matching glob patterns (quote the pattern)")
-	rootCmd.Flags().StringSliceVarP(&cfg.ExcludePatterns, "exclude", "x", nil, "exclude files matching glob patterns (quote the pattern)")
+	rootCmd.Flags().StringVar(&cfg.Format, "format", cfg.Format, "output format (table|json|flat)")
+	rootCmd.Flags().BoolVar(&cfg.JSONVerbose, "json-verbose", cfg.JSONVerbose, "with --format json, add the key each entry was sorted by")
+	rootCmd.Flags().StringVar(&cfg.Output, "output", "", "write the listing to FILE (replaced atomically when complete)")
+	rootCmd.Flags().BoolVar(&cfg.Tee, "tee", false, "with --output, also print the listing to the terminal")
+	rootCmd.Flags().IntVar(&cfg.Throttle, "throttle", cfg.Throttle, "limit output to N lines per second (0 = no limit)")
+	rootCmd.Flags().BoolVar(&cfg.Watch, "watch", false, "redraw the listing of a directory, or the row of a single file, every --interval")
+	rootCmd.Flags().StringVar(&cfg.Interval, "interval", cfg.Interval, "with --watch, time between refreshes")
+	rootCmd.Flags().StringVar(&cfg.GrowthAlert, "growth-alert", cfg.GrowthAlert, "with --watch, highlight entries growing at least this much per second (0 = off)")
+	rootCmd.Flags().IntVar(&cfg.Limit, "limit", cfg.Limit, "only show the first N entries of each directory after sorting (0 = all)")
+	rootCmd.Flags().StringVar(&cfg.Timeout, "timeout", cfg.Timeout, "give up on git, owner lookups and --du after this long (default 10s, --du unlimited; 0 = no limit)")
+	rootCmd.Flags().BoolVar(&cfg.Debug, "debug", false, "print the detected terminal, color depth, width, locale and git setup to stderr")
+	rootCmd.Flags().StringSliceVarP(&cfg.IncludePatterns, "include", "i", cfg.IncludePatterns, "include files matching glob patterns (quote the pattern)")
+	rootCmd.Flags().StringSliceVarP(&cfg.ExcludePatterns, "exclude", "x", cfg.ExcludePatterns, "exclude files matching glob patterns (quote the pattern)")
+	rootCmd.Flags().BoolVar(&cfg.NoJunk, "no-junk", cfg.NoJunk, "exclude well-known noise such as node_modules, .DS_Store and __pycache__")
+	rootCmd.Flags().StringVar(&cfg.MinSize, "min-size", cfg.MinSize, "only show files of at least this size (e.g. 10M)")
+	rootCmd.Flags().StringVar(&cfg.MaxSize, "max-size", cfg.MaxSize, "only show files of at most this size (e.g. 1G)")
+	rootCmd.Flags().StringSliceVar(&cfg.Only, "only", cfg.Only, "only list entries of these types: dirs, files, links, exec")
+	rootCmd.Flags().StringSliceVar(&cfg.Mime, "mime", cfg.Mime, "only list files whose content is of these MIME types, e.g. image/*")
+	rootCmd.Flags().StringVar(&cfg.Perm, "perm", cfg.Perm, "only list entries whose mode matches, find-style: 4000 exactly, -o+w all bits, /6000 any")
 
 	var help bool
 	rootCmd.Flags().BoolVar(&help, "help", false, "help for lu")
+	addNegationFlags(rootCmd.Flags())
+	registerCompletions(rootCmd, &cfg)
 	rootCmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
 		terminal.ShowColoredHelp(cmd)
 	})
+	rootCmd.SetHelpCommand(newHelpCommand())
 
 	rootCmd.AddCommand(newUpdateCommand())
 	rootCmd.AddCommand(newVersionCommand())
 	rootCmd.AddCommand(newRollbackCommand())
+	rootCmd.AddCommand(newConfigCommand())
+	rootCmd.AddCommand(newPromptSummaryCommand())
+	rootCmd.AddCommand(newReportCommand())
+	rootCmd.AddCommand(newDoctorCommand())
+	rootCmd.AddCommand(newDirtyCommand())
+	rootCmd.AddCommand(newLinkAuditCommand())
+	rootCmd.AddCommand(newRetentionCommand())
+
+	if configErr == nil {
+		configErr = applyDefaultFlags(rootCmd, cfg.DefaultFlags, os.Args[1:])
+	}
 
 	return rootCmd
 }
+
+// flagForKey maps a config file key to the flag that sets the same option.
+func flagForKey(key string) string {
+	if key == "color_rules" {
+		return "color-rule"
+	}
+	return strings.ReplaceAll(key, "_", "-")
+}
+
+// loadDefaults applies the user's config file and then LU_* environment
+// variables before the flags are defined, so their values become the flag
+// defaults and any flag given on the command line still wins.
+func loadDefaults(cfg *config.Config) error {
+	// Without a home directory there is no config file to read.
+	if path, err := config.Path(); err == nil {
+		if err := cfg.LoadFile(path); err != nil {
+			return err
+		}
+	}
+	return cfg.LoadEnv(os.Getenv)
+}
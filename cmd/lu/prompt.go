@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/ipanardian/lu-hut/internal/summary"
+	"github.com/spf13/cobra"
+)
+
+func newPromptSummaryCommand() *cobra.Command {
+	var (
+		asJSON     bool
+		noGit      bool
+		showHidden bool
+	)
+
+	promptCmd := &cobra.Command{
+		Use:   "prompt-summary [path]",
+		Short: "Print a one-line directory summary for shell prompts",
+		Long: `Print a tiny machine-readable summary of a directory (default: the current one)
+for embedding in shell prompts, e.g.
+
+  entries=12 dirs=3 files=9 dirty=2 largest=data.db largest_size=1048576
+
+Only the directory itself is read; heavy features are never enabled.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := "."
+			if len(args) == 1 {
+				dir = args[0]
+			}
+
+			s, err := summary.Collect(dir, showHidden, !noGit)
+			if err != nil {
+				return err
+			}
+			if s.GitError != "" {
+				fmt.Fprintf(os.Stderr, "Warning: git status unavailable: %s\n", s.GitError)
+			}
+
+			if asJSON {
+				return json.NewEncoder(os.Stdout).Encode(s)
+			}
+			fmt.Println(s)
+			return nil
+		},
+	}
+
+	promptCmd.Flags().BoolVar(&asJSON, "json", false, "print the summary as JSON")
+	promptCmd.Flags().BoolVar(&noGit, "no-git", false, "skip the git status lookup")
+	promptCmd.Flags().BoolVarP(&showHidden, "hidden", "h", false, "count hidden files")
+	promptCmd.Flags().Bool("help", false, "help for prompt-summary")
+
+	promptCmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		fmt.Println()
+		color.Cyan("lu prompt-summary - Print a one-line directory summary for shell prompts")
+		fmt.Println()
+		fmt.Println("USAGE:")
+		fmt.Println("  lu prompt-summary [path] [flags]")
+		fmt.Println()
+		fmt.Println("FLAGS:")
+		fmt.Println("      --json     print the summary as JSON")
+		fmt.Println("      --no-git   skip the git status lookup")
+		fmt.Println("  -h, --hidden   count hidden files")
+		fmt.Println("      --help     help for prompt-summary")
+		fmt.Println()
+		fmt.Println("OUTPUT:")
+		fmt.Println("  entries=12 dirs=3 files=9 dirty=2 largest=data.db largest_size=1048576")
+		fmt.Println()
+		fmt.Println("  dirty counts the changed and untracked paths under the directory. It is")
+		fmt.Println("  only printed inside a git repository, as dirty=? with a warning when git")
+		fmt.Println("  status fails. Only the directory itself is read, so the command")
+		fmt.Println("  stays fast enough to run on every prompt.")
+		fmt.Println()
+	})
+
+	return promptCmd
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/ipanardian/lu-hut/internal/lister"
+	"github.com/ipanardian/lu-hut/internal/report"
+	"github.com/spf13/cobra"
+)
+
+func newReportCommand() *cobra.Command {
+	var configPath string
+
+	reportCmd := &cobra.Command{
+		Use:   "report --config report.toml",
+		Short: "Run a set of listings from a report file",
+		Long: `Run every [[listing]] in a report file and write each result to its output
+file, e.g. from cron:
+
+  0 3 * * * lu report --config /etc/lu-hut/report.toml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if configPath == "" {
+				return fmt.Errorf("--config is required")
+			}
+
+			r, err := report.Load(configPath)
+			if err != nil {
+				return err
+			}
+
+			failed := 0
+			for _, listing := range r.Listings {
+				if listing.Output != "" {
+					if err := os.MkdirAll(filepath.Dir(listing.Output), 0o755); err != nil {
+						fmt.Fprintf(os.Stderr, "Error: %s: %v\n", listing.Name, err)
+						failed++
+						continue
+					}
+				}
+
+				l, err := lister.New(listing.Config)
+				if err == nil {
+					err = l.List(listing.Paths...)
+				}
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %s: %v\n", listing.Name, err)
+					failed++
+				}
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("%d of %d listings failed", failed, len(r.Listings))
+			}
+			return nil
+		},
+	}
+
+	reportCmd.Flags().StringVarP(&configPath, "config", "c", "", "report file describing the listings to run")
+	reportCmd.Flags().Bool("help", false, "help for report")
+
+	reportCmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		fmt.Println()
+		color.Cyan("lu report - Run a set of listings from a report file")
+		fmt.Println()
+		fmt.Println("USAGE:")
+		fmt.Println("  lu report --config report.toml")
+		fmt.Println()
+		fmt.Println("FLAGS:")
+		fmt.Println("  -c, --config   report file describing the listings to run")
+		fmt.Println("      --help     help for report")
+		fmt.Println()
+		fmt.Println("REPORT FILE:")
+		fmt.Println("  [defaults]                  # optional, applies to every listing")
+		fmt.Println("  exclude = [\"*.tmp\"]")
+		fmt.Println()
+		fmt.Println("  [[listing]]")
+		fmt.Println("  name = \"uploads\"")
+		fmt.Println("  paths = [\"/srv/uploads\"]")
+		fmt.Println("  output = \"uploads.json\"      # relative to the report file; stdout if omitted")
+		fmt.Println("  format = \"json\"              # table or json")
+		fmt.Println("  recursive = true")
+		fmt.Println("  sort_size = true")
+		fmt.Println()
+		fmt.Println("  Listings accept the same keys as config.toml. Output files are replaced")
+		fmt.Println("  atomically, and a failing listing does not stop the others.")
+		fmt.Println()
+	})
+
+	return reportCmd
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/ipanardian/lu-hut/internal/config"
+	"github.com/ipanardian/lu-hut/internal/lister"
+	"github.com/ipanardian/lu-hut/internal/theme"
+	"github.com/spf13/cobra"
+)
+
+func newRetentionCommand() *cobra.Command {
+	var (
+		keep      string
+		patterns  []string
+		excludes  []string
+		hidden    bool
+		format    string
+		colorMode string
+		exactTime bool
+		showUser  bool
+	)
+
+	retentionCmd := &cobra.Command{
+		Use:   "retention [path] --keep 30d",
+		Short: "Preview which files a retention policy would delete",
+		Long: `List every file below path that was last modified longer ago than --keep,
+optionally only those matching --pattern, with the space deleting them
+would reclaim. Nothing is deleted.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if keep == "" {
+				return fmt.Errorf("--keep is required (e.g. --keep 30d)")
+			}
+			var age theme.Duration
+			if err := age.UnmarshalText([]byte(keep)); err != nil {
+				return fmt.Errorf("invalid --keep: %w", err)
+			}
+
+			dir := "."
+			if len(args) == 1 {
+				dir = args[0]
+			}
+
+			// As with lu dirty, colors, theme and sorting follow the config
+			// file, while the selection is only what the flags ask for.
+			cfg := config.NewDefaultConfig()
+			if err := loadDefaults(&cfg); err != nil {
+				return err
+			}
+			cfg.Recursive, cfg.Tree, cfg.DiskUsage, cfg.Follow = false, false, false, false
+			cfg.IncludePatterns, cfg.ExcludePatterns, cfg.NoJunk = patterns, excludes, false
+			cfg.ShowHidden = hidden
+			cfg.ShowUser = showUser
+			if cmd.Flags().Changed("exact-time") {
+				cfg.ShowExactTime = exactTime
+			}
+			if format != "" {
+				cfg.Format = format
+			}
+			if colorMode != "" {
+				cfg.ColorMode = colorMode
+			}
+			if err := cfg.Validate(); err != nil {
+				return err
+			}
+
+			l, err := lister.New(cfg)
+			if err != nil {
+				return err
+			}
+			return l.ListRetention(dir, time.Duration(age))
+		},
+	}
+
+	retentionCmd.Flags().StringVar(&keep, "keep", "", "keep files modified within this age (e.g. 30d, 12w, 1y)")
+	retentionCmd.Flags().StringSliceVarP(&patterns, "pattern", "p", nil, "only apply the policy to names matching these patterns")
+	retentionCmd.Flags().StringSliceVarP(&excludes, "exclude", "x", nil, "never delete names matching these patterns")
+	retentionCmd.Flags().BoolVarP(&hidden, "hidden", "h", false, "include hidden files and directories")
+	retentionCmd.Flags().StringVar(&format, "format", "", "output format (table|json)")
+	retentionCmd.Flags().StringVar(&colorMode, "color", "", "color output mode (always|auto|never)")
+	retentionCmd.Flags().BoolVarP(&exactTime, "exact-time", "T", false, "show exact modification time instead of relative")
+	retentionCmd.Flags().BoolVarP(&showUser, "user", "u", false, "show user and group ownership metadata")
+	retentionCmd.Flags().Bool("help", false, "help for retention")
+
+	retentionCmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		fmt.Println()
+		color.Cyan("lu retention - Preview which files a retention policy would delete")
+		fmt.Println()
+		fmt.Println("USAGE:")
+		fmt.Println("  lu retention [path] --keep AGE [flags]")
+		fmt.Println()
+		fmt.Println("FLAGS:")
+		fmt.Println("      --keep         keep files modified within AGE: s, m, h, d, w or y (e.g. 30d)")
+		fmt.Println("  -p, --pattern      only apply the policy to names matching these patterns")
+		fmt.Println("  -x, --exclude      never delete names matching these patterns")
+		fmt.Println("  -h, --hidden       include hidden files and directories")
+		fmt.Println("      --format       output format: table (default) or json")
+		fmt.Println("      --color        color output mode: always, auto or never")
+		fmt.Println("  -T, --exact-time   show exact modification time instead of relative")
+		fmt.Println("  -u, --user         show user and group ownership metadata")
+		fmt.Println("      --help         help for retention")
+		fmt.Println()
+		fmt.Println("  The whole tree below path is checked. Nothing is deleted; pipe")
+		fmt.Println("  --format json into your cleanup script once the preview looks right.")
+		fmt.Println()
+		fmt.Println("EXAMPLES:")
+		fmt.Println("  lu retention /var/log/app --keep 30d -p '*.log' -p '*.gz'")
+		fmt.Println("  lu retention backups --keep 1y --format json")
+		fmt.Println()
+	})
+
+	return retentionCmd
+}
@@ -10,7 +10,10 @@ import (
 )
 
 func newUpdateCommand() *cobra.Command {
-	var force bool
+	var (
+		force bool
+		from  string
+	)
 
 	updateCmd := &cobra.Command{
 		Use:   "update",
@@ -23,8 +26,22 @@ This command will:
   3. Replace the current binary with the new version
   4. Verify the installation
 
-The current binary will be backed up during the update process.`,
+The current binary will be backed up during the update process. With
+--from, the binary or release archive is taken from a local file instead,
+for machines without internet access.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if from != "" {
+				fmt.Printf("Current version: %s\n", color.YellowString(updater.GetCurrentVersion()))
+				fmt.Println()
+				if err := updater.PerformUpdateFrom(from, force); err != nil {
+					return fmt.Errorf("update failed: %w", err)
+				}
+				fmt.Println()
+				color.Green("Update completed successfully!")
+				color.Cyan("Please restart your terminal or run 'hash -r' to use the new version.")
+				return nil
+			}
+
 			color.Cyan("Checking for updates...")
 
 			release, err := updater.GetLatestVersion()
@@ -51,7 +68,7 @@ The current binary will be backed up during the update process.`,
 
 			fmt.Println()
 
-			if err := updater.PerformUpdate(release); err != nil {
+			if err := updater.PerformUpdate(release, force); err != nil {
 				return fmt.Errorf("update failed: %w", err)
 			}
 
@@ -64,6 +81,7 @@ The current binary will be backed up during the update process.`,
 	}
 
 	updateCmd.Flags().BoolVarP(&force, "force", "f", false, "force reinstall even if already on latest version")
+	updateCmd.Flags().StringVar(&from, "from", "", "install from a local binary or release .tar.gz instead of GitHub")
 
 	updateCmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
 		fmt.Println()
@@ -73,8 +91,9 @@ The current binary will be backed up during the update process.`,
 		fmt.Println("  lu update [flags]")
 		fmt.Println()
 		fmt.Println("FLAGS:")
-		fmt.Println("  -f, --force    force reinstall even if already on latest version")
-		fmt.Println("      --help     help for update")
+		fmt.Println("  -f, --force        force reinstall even if already on latest version")
+		fmt.Println("      --from FILE    install from a local binary or release .tar.gz instead of GitHub")
+		fmt.Println("      --help         help for update")
 		fmt.Println()
 		fmt.Println("DESCRIPTION:")
 		fmt.Println("  This command will:")
@@ -84,6 +103,12 @@ The current binary will be backed up during the update process.`,
 		fmt.Println("    4. Verify the installation")
 		fmt.Println()
 		fmt.Println("  The current binary will be backed up during the update process.")
+		fmt.Println("  With --from, steps 1 and 2 are replaced by reading FILE, so lu can be")
+		fmt.Println("  updated on machines without internet access.")
+		fmt.Println()
+		fmt.Println("EXAMPLES:")
+		fmt.Println("  lu update")
+		fmt.Println("  lu update --from ./lu-linux-amd64.tar.gz")
 		fmt.Println()
 	})
 
@@ -100,8 +125,10 @@ func newVersionCommand() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			currentVersion := updater.GetCurrentVersion()
 
+			method := updater.DetectInstall()
 			fmt.Printf("lu-hut version %s\n", color.CyanString(currentVersion))
 			fmt.Printf("OS/Arch: %s\n", color.YellowString(updater.GetBinaryName()))
+			fmt.Printf("Installed: %s\n", color.YellowString(string(method)))
 
 			if checkUpdate {
 				fmt.Println()
@@ -119,7 +146,7 @@ func newVersionCommand() *cobra.Command {
 				if updater.IsNewerVersion(currentVersion, latestVersion) {
 					fmt.Println()
 					color.Yellow("→ New version available!")
-					color.Cyan("Run 'lu update' to upgrade")
+					color.Cyan("Run '%s' to upgrade", method.UpdateCommand())
 				} else {
 					fmt.Println()
 					color.Green("✓ You are running the latest version!")
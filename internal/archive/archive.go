@@ -0,0 +1,97 @@
+// Package archive inspects archive files without extracting them.
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxCompressedTarSize bounds how much gzip data is decompressed just to
+// count entries; plain tar and zip files only touch their headers.
+const maxCompressedTarSize = 64 << 20
+
+// IsArchive reports whether name has an extension Count understands.
+func IsArchive(name string) bool {
+	return kind(name) != ""
+}
+
+// Count returns the number of entries in the zip or tar archive at path.
+// Anything but a regular file, after following symlinks, is refused, since
+// opening a FIFO or device blocks.
+func Count(path string) (int, bool) {
+	if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+		return 0, false
+	}
+
+	switch kind(path) {
+	case "zip":
+		r, err := zip.OpenReader(path)
+		if err != nil {
+			return 0, false
+		}
+		defer r.Close()
+		return len(r.File), true
+	case "tar":
+		f, err := os.Open(path)
+		if err != nil {
+			return 0, false
+		}
+		defer f.Close()
+		return countTar(f)
+	case "tgz":
+		f, err := os.Open(path)
+		if err != nil {
+			return 0, false
+		}
+		defer f.Close()
+
+		if info, err := f.Stat(); err != nil || info.Size() > maxCompressedTarSize {
+			return 0, false
+		}
+
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return 0, false
+		}
+		defer gz.Close()
+		return countTar(gz)
+	}
+
+	return 0, false
+}
+
+func kind(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return "tgz"
+	}
+
+	switch filepath.Ext(lower) {
+	case ".zip", ".jar", ".apk", ".whl":
+		return "zip"
+	case ".tar":
+		return "tar"
+	}
+	return ""
+}
+
+func countTar(r io.Reader) (int, bool) {
+	tr := tar.NewReader(r)
+	count := 0
+	for {
+		_, err := tr.Next()
+		if err == io.EOF {
+			return count, true
+		}
+		if err != nil {
+			return 0, false
+		}
+		count++
+	}
+}
@@ -0,0 +1,18 @@
+// Package atime reads when a file was last accessed.
+package atime
+
+import (
+	"os"
+	"time"
+)
+
+// Of returns the last access time recorded in info. Where the platform does
+// not expose one, the modification time is returned instead. Filesystems
+// mounted with noatime or relatime update it rarely or never, so treat it
+// as a lower bound.
+func Of(info os.FileInfo) time.Time {
+	if t, ok := accessTime(info); ok {
+		return t
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,12 @@
+//go:build !linux && !openbsd && !dragonfly && !solaris && !illumos && !aix && !darwin && !freebsd && !netbsd && !windows
+
+package atime
+
+import (
+	"os"
+	"time"
+)
+
+func accessTime(os.FileInfo) (time.Time, bool) {
+	return time.Time{}, false
+}
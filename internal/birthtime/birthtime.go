@@ -0,0 +1,18 @@
+// Package birthtime reads when a file was created, on the platforms whose
+// filesystems record it.
+package birthtime
+
+import (
+	"os"
+	"time"
+)
+
+// Of returns the creation time of the file at path, described by info. When
+// the platform or filesystem does not keep one, the modification time is
+// returned instead, so callers can always sort by the result.
+func Of(path string, info os.FileInfo) time.Time {
+	if t, ok := birthTime(path, info); ok {
+		return t
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,17 @@
+//go:build darwin || freebsd || netbsd
+
+package birthtime
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+func birthTime(_ string, info os.FileInfo) (time.Time, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(stat.Birthtimespec.Unix()), true
+}
@@ -0,0 +1,30 @@
+package birthtime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOf(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Lstat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Either the real creation time, which touching the mtime leaves
+	// alone, or the modification time on filesystems without one.
+	created := Of(path, info)
+	if !created.Equal(info.ModTime()) && time.Since(created) > time.Minute {
+		t.Errorf("Of() = %v, want the creation time or %v", created, info.ModTime())
+	}
+}
@@ -1,29 +1,136 @@
 // Package config provides configuration management for the lu-hut application.
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/ipanardian/lu-hut/internal/filter"
+	"github.com/ipanardian/lu-hut/internal/perms"
+	"github.com/ipanardian/lu-hut/internal/theme"
+)
+
+// Config holds every listing option. The toml tags name the keys accepted
+// in the config file; options that run commands are deliberately excluded.
 type Config struct {
-	SortModified    bool
-	SortSize        bool
-	SortExtension   bool
-	Reverse         bool
-	ShowGit         bool
-	ShowHidden      bool
-	ShowUser        bool
-	ShowExactTime   bool
-	ShowOctal       bool
-	Recursive       bool
-	Tree            bool
-	MaxDepth        int
-	ColorMode       string
-	IncludePatterns []string
-	ExcludePatterns []string
+	SortModified    bool              `toml:"sort_modified"`
+	SortSize        bool              `toml:"sort_size"`
+	SortExtension   bool              `toml:"sort_extension"`
+	SortNatural     bool              `toml:"sort_natural"`
+	Sort            string            `toml:"sort"`
+	Reverse         bool              `toml:"reverse"`
+	DirsLast        bool              `toml:"dirs_last"`
+	GroupDirs       bool              `toml:"group_dirs"`
+	CaseSensitive   bool              `toml:"case_sensitive"`
+	ShowGit         bool              `toml:"git"`
+	GitIgnore       bool              `toml:"git_ignore"`
+	GitBlame        bool              `toml:"git_blame"`
+	ShowHidden      bool              `toml:"hidden"`
+	SkipSpecial     bool              `toml:"skip_special"`
+	ShowUser        bool              `toml:"user"`
+	ShowExactTime   bool              `toml:"exact_time"`
+	AlignUnits      bool              `toml:"align_units"`
+	TimeField       string            `toml:"time"`
+	ShowOctal       bool              `toml:"octal"`
+	ShowIcons       bool              `toml:"icons"`
+	ShowMedia       bool              `toml:"media"`
+	ShowCompress    bool              `toml:"compressibility"`
+	ShowInode       bool              `toml:"inode"`
+	ShowEntries     bool              `toml:"archive_count"`
+	ShowProject     bool              `toml:"project"`
+	ShowShortcuts   bool              `toml:"shortcuts"`
+	ShowGenerated   bool              `toml:"generated"`
+	GeneratedHeader bool              `toml:"generated_headers"`
+	ShowTags        bool              `toml:"tags"`
+	ASCII           bool              `toml:"ascii"`
+	Badges          bool              `toml:"badges"`
+	StrictNames     bool              `toml:"strict_names"`
+	ExpectPerms     string            `toml:"expect_perms"`
+	ExplainPerms    bool              `toml:"explain_perms"`
+	Strict          bool              `toml:"-"`
+	Recursive       bool              `toml:"recursive"`
+	Tree            bool              `toml:"tree"`
+	DiskUsage       bool              `toml:"du"`
+	Follow          bool              `toml:"-"`
+	MaxDepth        int               `toml:"max_depth"`
+	Throttle        int               `toml:"throttle"`
+	Limit           int               `toml:"-"`
+	Timeout         string            `toml:"timeout"`
+	Watch           bool              `toml:"-"`
+	Interval        string            `toml:"interval"`
+	GrowthAlert     string            `toml:"growth_alert"`
+	Debug           bool              `toml:"-"`
+	Output          string            `toml:"-"`
+	Tee             bool              `toml:"-"`
+	SinceLastRun    bool              `toml:"-"`
+	Conflicts       bool              `toml:"-"`
+	GitDirty        bool              `toml:"-"`
+	Pick            bool              `toml:"-"`
+	Print0          bool              `toml:"-"`
+	SessionLog      string            `toml:"-"`
+	ColorMode       string            `toml:"color"`
+	Format          string            `toml:"format"`
+	Exec            string            `toml:"-"`
+	AssumeYes       bool              `toml:"-"`
+	Theme           string            `toml:"theme"`
+	BorderColor     string            `toml:"border_color"`
+	HeaderColor     string            `toml:"header_color"`
+	BorderStyle     string            `toml:"border_style"`
+	ColorRules      []string          `toml:"color_rules"`
+	ColorSpec       string            `toml:"color_spec"`
+	IncludePatterns []string          `toml:"include"`
+	Highlight       []string          `toml:"highlight"`
+	FilterTags      []string          `toml:"tag"`
+	FilterOwner     []string          `toml:"owner"`
+	FilterGroup     []string          `toml:"group"`
+	ExcludePatterns []string          `toml:"exclude"`
+	NoJunk          bool              `toml:"no_junk"`
+	Junk            []string          `toml:"junk"`
+	MinSize         string            `toml:"min_size"`
+	MaxSize         string            `toml:"max_size"`
+	Only            []string          `toml:"only"`
+	Mime            []string          `toml:"mime"`
+	Perm            string            `toml:"perm"`
+	JSONVerbose     bool              `toml:"-"`
+	IconOverrides   map[string]string `toml:"icon_map"`
+	Profile         string            `toml:"profile"`
+	Profiles        Profiles          `toml:"profiles"`
+	DefaultFlags    []string          `toml:"default_flags"`
+	Version         int               `toml:"version"`
+}
+
+// Profiles maps a profile name to the config keys it sets, as written in
+// the [profiles.NAME] tables of the config file.
+type Profiles map[string]map[string]any
+
+// DefaultJunk is what --no-junk hides unless the config file sets its own
+// junk list: dependency trees, build output and OS litter that swamp
+// recursive listings without ever being interesting.
+var DefaultJunk = []string{
+	"node_modules",
+	".DS_Store",
+	"Thumbs.db",
+	"__pycache__",
+	".pytest_cache",
+	".mypy_cache",
+	".venv",
+	"target",
 }
 
+// defaultTimeout bounds git and owner lookups when --timeout is not given.
+const defaultTimeout = 10 * time.Second
+
 func NewDefaultConfig() Config {
 	return Config{
-		MaxDepth: 30,
+		Junk:          slices.Clone(DefaultJunk),
+		MaxDepth:      30,
+		ShowGenerated: true,
+		GroupDirs:     true,
+		Interval:      "2s",
+		GrowthAlert:   "1M",
 	}
 }
 
@@ -31,8 +138,191 @@ func (c Config) Validate() error {
 	if c.MaxDepth < 0 {
 		return fmt.Errorf("max depth cannot be negative")
 	}
+	if c.Throttle < 0 {
+		return fmt.Errorf("throttle cannot be negative")
+	}
+	if c.Limit < 0 {
+		return fmt.Errorf("limit cannot be negative")
+	}
+	if c.Timeout != "" {
+		if timeout, err := time.ParseDuration(c.Timeout); err != nil || timeout < 0 {
+			return fmt.Errorf("invalid timeout: %s (must be a duration such as 5s or 500ms, 0 = no limit)", c.Timeout)
+		}
+	}
+	if c.Interval != "" {
+		if interval, err := time.ParseDuration(c.Interval); err != nil || interval <= 0 {
+			return fmt.Errorf("invalid interval: %s (must be a positive duration such as 2s or 500ms)", c.Interval)
+		}
+	}
+	for _, t := range c.Only {
+		if !slices.Contains(filter.Types, t) {
+			return fmt.Errorf("invalid --only type: %s (must be dirs, files, links or exec)", t)
+		}
+	}
+	for _, pattern := range c.Mime {
+		if _, err := path.Match(pattern, ""); err != nil || !strings.Contains(pattern, "/") {
+			return fmt.Errorf("invalid --mime pattern: %s (use a type like image/png or image/*)", pattern)
+		}
+	}
+	if c.Perm != "" {
+		if _, err := perms.ParseMatch(c.Perm); err != nil {
+			return err
+		}
+	}
+	if _, err := parseSize("growth alert", c.GrowthAlert); err != nil {
+		return err
+	}
+	minSize, err := parseSize("min size", c.MinSize)
+	if err != nil {
+		return err
+	}
+	maxSize, err := parseSize("max size", c.MaxSize)
+	if err != nil {
+		return err
+	}
+	if minSize > 0 && maxSize > 0 && minSize > maxSize {
+		return fmt.Errorf("min size %s is larger than max size %s", c.MinSize, c.MaxSize)
+	}
 	if c.ColorMode != "" && c.ColorMode != "always" && c.ColorMode != "auto" && c.ColorMode != "never" {
 		return fmt.Errorf("invalid color mode: %s (must be always, auto, or never)", c.ColorMode)
 	}
+	switch c.BorderStyle {
+	case "", "single", "double", "bold", "ascii":
+	default:
+		return fmt.Errorf("invalid border style: %s (must be single, double, bold, or ascii)", c.BorderStyle)
+	}
+	if c.Sort != "" {
+		if _, err := canonicalSort(c.Sort); err != nil {
+			return fmt.Errorf("invalid sort: %w", err)
+		}
+	}
+	if c.DirsLast && !c.GroupDirs {
+		return fmt.Errorf("--dirs-last cannot be combined with --no-group-dirs")
+	}
+	switch c.TimeField {
+	case "", "modified", "accessed", "created":
+	default:
+		return fmt.Errorf("invalid time: %s (must be modified, accessed, or created)", c.TimeField)
+	}
+	switch c.Format {
+	case "", "table", "json", "flat":
+	default:
+		return fmt.Errorf("invalid format: %s (must be table, json or flat)", c.Format)
+	}
+	if c.Format == "flat" && !c.Tree {
+		return fmt.Errorf("--format flat requires --tree")
+	}
+	if c.JSONVerbose && c.Format != "json" {
+		return fmt.Errorf("--json-verbose requires --format json")
+	}
+	if c.Format == "json" && c.Tree {
+		return fmt.Errorf("--format json cannot be combined with --tree")
+	}
+	if c.SinceLastRun && c.Tree {
+		return fmt.Errorf("--changed-since-last cannot be combined with --tree")
+	}
+	if c.DiskUsage && c.Tree {
+		return fmt.Errorf("--du cannot be combined with --tree")
+	}
+	if c.Follow && !c.DiskUsage {
+		return fmt.Errorf("--follow requires --du")
+	}
+	if c.Tee && c.Output == "" {
+		return fmt.Errorf("--tee requires --output")
+	}
+	if c.Exec != "" && c.Tree {
+		return fmt.Errorf("--exec cannot be combined with --tree")
+	}
+	if c.Pick && (c.Tree || c.Format == "json" || c.Exec != "" || c.Output != "") {
+		return fmt.Errorf("--pick cannot be combined with --tree, --format json, --exec or --output")
+	}
+	if c.Print0 && !c.Pick {
+		return fmt.Errorf("--print0 requires --pick")
+	}
+	if c.ExpectPerms != "" {
+		if _, err := perms.Parse(c.ExpectPerms); err != nil {
+			return fmt.Errorf("invalid expect perms: %w", err)
+		}
+		if c.Tree {
+			return fmt.Errorf("--expect-perms cannot be combined with --tree")
+		}
+	}
+	if c.Strict && c.ExpectPerms == "" {
+		return fmt.Errorf("--strict requires --expect-perms")
+	}
+	if c.SessionLog != "" && !c.Pick {
+		return fmt.Errorf("--session-log requires --pick")
+	}
+	if c.Watch && (c.Tree || c.Recursive || c.Format == "json" || c.Pick || c.Exec != "" || c.Output != "") {
+		return fmt.Errorf("--watch cannot be combined with --tree, --recursive, --format json, --pick, --exec or --output")
+	}
 	return nil
 }
+
+// IntervalDuration returns the time between --watch refreshes.
+func (c Config) IntervalDuration() time.Duration {
+	interval, err := time.ParseDuration(c.Interval)
+	if err != nil || interval <= 0 {
+		return 2 * time.Second
+	}
+	return interval
+}
+
+// GrowthAlertRate returns the bytes per second from which --watch
+// highlights a growing entry, or 0 when --growth-alert is off.
+func (c Config) GrowthAlertRate() int64 {
+	rate, _ := parseSize("growth alert", c.GrowthAlert)
+	return rate
+}
+
+// TimeoutDuration returns how long git and owner lookups may take before
+// lu gives up on them, or zero for no limit. Without --timeout they get
+// defaultTimeout.
+func (c Config) TimeoutDuration() time.Duration {
+	if c.Timeout == "" {
+		return defaultTimeout
+	}
+	timeout, err := time.ParseDuration(c.Timeout)
+	if err != nil || timeout < 0 {
+		return 0
+	}
+	return timeout
+}
+
+// DiskUsageTimeout returns how long --du may count before the sizes still
+// missing are shown as "?", or zero for no limit. A long scan is usually
+// what --du was asked for, so it is only cut short when --timeout is given.
+func (c Config) DiskUsageTimeout() time.Duration {
+	if c.Timeout == "" {
+		return 0
+	}
+	return c.TimeoutDuration()
+}
+
+// Excludes returns the exclude patterns in effect, with the junk list added
+// when --no-junk is set.
+func (c Config) Excludes() []string {
+	if !c.NoJunk {
+		return c.ExcludePatterns
+	}
+	return append(slices.Clone(c.ExcludePatterns), c.Junk...)
+}
+
+// SizeRange returns the --min-size and --max-size limits in bytes, with
+// zero for a limit that is not set.
+func (c Config) SizeRange() (int64, int64) {
+	minSize, _ := parseSize("min size", c.MinSize)
+	maxSize, _ := parseSize("max size", c.MaxSize)
+	return minSize, maxSize
+}
+
+func parseSize(name, spec string) (int64, error) {
+	if spec == "" {
+		return 0, nil
+	}
+	var size theme.ByteSize
+	if err := size.UnmarshalText([]byte(spec)); err != nil || size < 0 {
+		return 0, fmt.Errorf("invalid %s: %s (must be a size such as 10M or 1.5GB)", name, spec)
+	}
+	return int64(size), nil
+}
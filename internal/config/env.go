@@ -0,0 +1,182 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix starts the name of every environment override. The rest of the
+// name is the config file key in upper case, e.g. LU_MAX_DEPTH.
+const EnvPrefix = "LU_"
+
+// LoadEnv overlays LU_* environment variables onto c. Every config file key
+// has a matching variable; lists and maps are comma separated ("a,b",
+// "k=v,k2=v2"). LU_SORT additionally picks the sort order by name.
+func (c *Config) LoadEnv(getenv func(string) string) error {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		key := t.Field(i).Tag.Get("toml")
+		if key == "" || key == "-" || key == "profiles" || key == "version" {
+			continue
+		}
+		name := EnvPrefix + strings.ToUpper(key)
+		value := getenv(name)
+		if value == "" {
+			continue
+		}
+		if err := setField(v.Field(i), value); err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+	}
+
+	if value := getenv(EnvPrefix + "SORT"); value != "" {
+		if err := c.SetSort(value); err != nil {
+			return fmt.Errorf("invalid %sSORT: %w", EnvPrefix, err)
+		}
+	}
+	return nil
+}
+
+// sortOrders maps every accepted sort name to its canonical form.
+var sortOrders = map[string]string{
+	"name":      "name",
+	"natural":   "natural",
+	"version":   "natural",
+	"time":      "time",
+	"modified":  "time",
+	"created":   "created",
+	"birth":     "created",
+	"accessed":  "accessed",
+	"atime":     "accessed",
+	"size":      "size",
+	"ext":       "ext",
+	"extension": "ext",
+	"owner":     "owner",
+	"user":      "owner",
+	"group":     "group",
+	"inode":     "inode",
+	"links":     "links",
+	"nlink":     "links",
+	"none":      "none",
+}
+
+func canonicalSort(order string) (string, error) {
+	canonical, ok := sortOrders[strings.ToLower(order)]
+	if !ok {
+		return "", fmt.Errorf("%q (must be name, natural, time, created, accessed, size, ext, owner, group, inode, links, or none)", order)
+	}
+	return canonical, nil
+}
+
+// SetSort selects the sort order by name, replacing any order chosen before.
+func (c *Config) SetSort(order string) error {
+	canonical, err := canonicalSort(order)
+	if err != nil {
+		return err
+	}
+	c.Sort = canonical
+	c.SortModified = canonical == "time"
+	c.SortSize = canonical == "size"
+	c.SortExtension = canonical == "ext"
+	c.SortNatural = canonical == "natural"
+	return nil
+}
+
+// NeedsTime reports whether entries need the named extra timestamp
+// ("created" or "accessed"), because it is sorted on or shown.
+func (c Config) NeedsTime(field string) bool {
+	return c.SortOrder() == field || c.TimeField == field
+}
+
+// NeedsInode reports whether entries need their inode number and link
+// count, because they are shown or sorted on.
+func (c Config) NeedsInode() bool {
+	order := c.SortOrder()
+	return c.ShowInode || order == "inode" || order == "links"
+}
+
+// NeedsOwner reports whether entries need their user and group names,
+// because they are shown, sorted on, filtered on or explained with
+// --explain-perms.
+func (c Config) NeedsOwner() bool {
+	order := c.SortOrder()
+	return c.ShowUser || c.ExplainPerms || len(c.FilterOwner) > 0 || len(c.FilterGroup) > 0 || order == "owner" || order == "group"
+}
+
+// DirPlacement says where directories go among the sorted entries: "" for
+// the sort order's usual place, "last", or "mixed" in with the files.
+func (c Config) DirPlacement() string {
+	switch {
+	case !c.GroupDirs:
+		return "mixed"
+	case c.DirsLast:
+		return "last"
+	}
+	return ""
+}
+
+// SortOrder returns the canonical sort order: the sort key when set,
+// otherwise the first sort_* switch in priority order, falling back to name.
+func (c Config) SortOrder() string {
+	switch {
+	case c.Sort != "":
+		if canonical, err := canonicalSort(c.Sort); err == nil {
+			return canonical
+		}
+	case c.SortSize:
+		return "size"
+	case c.SortExtension:
+		return "ext"
+	case c.SortModified:
+		return "time"
+	case c.SortNatural:
+		return "natural"
+	}
+	return "name"
+}
+
+func setField(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", value)
+		}
+		field.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		field.SetInt(int64(n))
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Slice:
+		field.Set(reflect.ValueOf(splitList(value)))
+	case reflect.Map:
+		m := make(map[string]string)
+		for _, pair := range splitList(value) {
+			k, val, ok := strings.Cut(pair, "=")
+			if !ok {
+				return fmt.Errorf("%q is not a key=value pair", pair)
+			}
+			m[k] = val
+		}
+		field.Set(reflect.ValueOf(m))
+	}
+	return nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func envFunc(vars map[string]string) func(string) string {
+	return func(name string) string { return vars[name] }
+}
+
+func TestLoadEnv(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.SortModified = true
+	cfg.ExcludePatterns = []string{"from-file"}
+
+	err := cfg.LoadEnv(envFunc(map[string]string{
+		"LU_COLOR":     "never",
+		"LU_SORT":      "size",
+		"LU_EXCLUDE":   "node_modules, *.log",
+		"LU_MAX_DEPTH": "3",
+		"LU_HIDDEN":    "true",
+		"LU_ICON_MAP":  ".go=G,dir=D",
+	}))
+	if err != nil {
+		t.Fatalf("LoadEnv() error = %v", err)
+	}
+
+	if cfg.ColorMode != "never" || cfg.MaxDepth != 3 || !cfg.ShowHidden {
+		t.Errorf("LoadEnv() = %+v", cfg)
+	}
+	if cfg.SortModified || !cfg.SortSize {
+		t.Errorf("LU_SORT=size: SortModified = %v, SortSize = %v", cfg.SortModified, cfg.SortSize)
+	}
+	if !reflect.DeepEqual(cfg.ExcludePatterns, []string{"node_modules", "*.log"}) {
+		t.Errorf("ExcludePatterns = %v", cfg.ExcludePatterns)
+	}
+	if !reflect.DeepEqual(cfg.IconOverrides, map[string]string{".go": "G", "dir": "D"}) {
+		t.Errorf("IconOverrides = %v", cfg.IconOverrides)
+	}
+}
+
+func TestLoadEnvErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		vars map[string]string
+		want string
+	}{
+		{name: "bool", vars: map[string]string{"LU_GIT": "yes please"}, want: "invalid LU_GIT"},
+		{name: "int", vars: map[string]string{"LU_MAX_DEPTH": "deep"}, want: "invalid LU_MAX_DEPTH"},
+		{name: "sort", vars: map[string]string{"LU_SORT": "random"}, want: "invalid LU_SORT"},
+		{name: "map", vars: map[string]string{"LU_ICON_MAP": ".go"}, want: "invalid LU_ICON_MAP"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultConfig()
+			err := cfg.LoadEnv(envFunc(tt.vars))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadEnv() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadEnvIgnoresExec(t *testing.T) {
+	cfg := NewDefaultConfig()
+	if err := cfg.LoadEnv(envFunc(map[string]string{"LU_EXEC": "rm {}", "LU_OUTPUT": "x"})); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Exec != "" || cfg.Output != "" {
+		t.Errorf("LoadEnv() set Exec = %q, Output = %q", cfg.Exec, cfg.Output)
+	}
+}
+
+func TestSortOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      Config
+		expected string
+	}{
+		{name: "default", cfg: Config{}, expected: "name"},
+		{name: "switch", cfg: Config{SortModified: true}, expected: "time"},
+		{name: "switch priority", cfg: Config{SortModified: true, SortSize: true}, expected: "size"},
+		{name: "sort key wins", cfg: Config{Sort: "natural", SortSize: true}, expected: "natural"},
+		{name: "alias", cfg: Config{Sort: "Extension"}, expected: "ext"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.cfg.SortOrder(); result != tt.expected {
+				t.Errorf("SortOrder() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+
+	cfg := Config{SortSize: true}
+	if err := cfg.SetSort("none"); err != nil || cfg.SortSize || cfg.SortOrder() != "none" {
+		t.Errorf("SetSort(none) = %v, leaving %+v", err, cfg)
+	}
+	if err := (Config{Sort: "random"}).Validate(); err == nil {
+		t.Errorf("Validate() accepted sort = random")
+	}
+}
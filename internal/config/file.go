@@ -0,0 +1,199 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Path returns where the config file lives: $XDG_CONFIG_HOME/lu-hut/config.toml,
+// falling back to ~/.config/lu-hut/config.toml on every platform so dotfiles
+// can be shared between machines.
+func Path() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "lu-hut", "config.toml"), nil
+}
+
+// LoadFile overlays the settings found in the TOML file at path onto c.
+// A missing file is not an error. Unknown keys are rejected so typos do not
+// go unnoticed, and a relative theme path is resolved against the file's
+// directory.
+func (c *Config) LoadFile(path string) error {
+	_, err := c.decodeFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// LocalFile is the name of the per-directory config file.
+const LocalFile = ".lu-hut.toml"
+
+// FindLocal returns the nearest LocalFile in dir or one of its parents.
+func FindLocal(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		path := filepath.Join(dir, LocalFile)
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			return path, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// LoadLocal merges the per-directory config file at path into c, the way
+// direnv scopes settings to a project. Scalars replace the current value,
+// lists are appended and maps are merged key by key. Keys for which keep
+// returns true (usually because the flag was given) are left untouched.
+// default_flags is rejected, since the command line has been parsed by the
+// time a local file is found.
+func (c *Config) LoadLocal(path string, keep func(key string) bool) error {
+	var local Config
+	md, err := local.decodeFile(path)
+	if err != nil {
+		return err
+	}
+	if md.IsDefined("default_flags") {
+		return fmt.Errorf("invalid config %s: default_flags is only read from config.toml", path)
+	}
+	c.merge(local, md, keep)
+	return nil
+}
+
+// ApplyProfile merges the named [profiles.NAME] table into c with the same
+// rules as LoadLocal.
+func (c *Config) ApplyProfile(name string, keep func(key string) bool) error {
+	settings, ok := c.Profiles[name]
+	if !ok {
+		names := make([]string, 0, len(c.Profiles))
+		for n := range c.Profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return fmt.Errorf("unknown profile %q (no profiles defined in the config file)", name)
+		}
+		return fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(names, ", "))
+	}
+
+	profile, md, err := decodeProfile(name, settings)
+	if err != nil {
+		return err
+	}
+	c.merge(profile, md, keep)
+	return nil
+}
+
+// decodeProfile turns a profile table back into a Config by re-encoding it,
+// which reuses the regular key validation.
+func decodeProfile(name string, settings map[string]any) (Config, toml.MetaData, error) {
+	for _, key := range []string{"version", "profile", "profiles", "default_flags"} {
+		if _, ok := settings[key]; ok {
+			return Config{}, toml.MetaData{}, fmt.Errorf("invalid profile %s: %s cannot be set inside a profile", name, key)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(settings); err != nil {
+		return Config{}, toml.MetaData{}, fmt.Errorf("invalid profile %s: %w", name, err)
+	}
+	var profile Config
+	md, err := profile.decode(buf.String(), "profile "+name, "")
+	return profile, md, err
+}
+
+func (c *Config) merge(src Config, md toml.MetaData, keep func(key string) bool) {
+	dst := reflect.ValueOf(c).Elem()
+	from := reflect.ValueOf(src)
+	for i := 0; i < dst.NumField(); i++ {
+		key := dst.Type().Field(i).Tag.Get("toml")
+		if key == "" || key == "-" || !md.IsDefined(key) || keep(key) {
+			continue
+		}
+
+		field, value := dst.Field(i), from.Field(i)
+		switch field.Kind() {
+		case reflect.Slice:
+			field.Set(reflect.AppendSlice(field, value))
+		case reflect.Map:
+			if field.IsNil() {
+				field.Set(reflect.MakeMap(field.Type()))
+			}
+			for _, k := range value.MapKeys() {
+				field.SetMapIndex(k, value.MapIndex(k))
+			}
+		default:
+			field.Set(value)
+		}
+	}
+}
+
+func (c *Config) decodeFile(path string) (toml.MetaData, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return toml.MetaData{}, err
+	}
+	text, err := upgrade(string(data), path)
+	if err != nil {
+		return toml.MetaData{}, err
+	}
+	return c.decode(text, path, filepath.Dir(path))
+}
+
+// decode reads TOML settings from source (a file name or profile label for
+// error messages). A relative theme path is resolved against dir, also for
+// themes named inside profiles, and every profile is validated up front so
+// typos surface even when the profile is not in use.
+func (c *Config) decode(data, source, dir string) (toml.MetaData, error) {
+	md, err := toml.Decode(data, c)
+	if err != nil {
+		return md, fmt.Errorf("invalid config %s: %w", source, err)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, key := range undecoded {
+			keys[i] = key.String()
+		}
+		return md, fmt.Errorf("invalid config %s: unknown key %s", source, describeUnknown(keys))
+	}
+
+	if dir == "" {
+		return md, nil
+	}
+	if isRelativeTheme(c.Theme) {
+		c.Theme = filepath.Join(dir, c.Theme)
+	}
+	for name, settings := range c.Profiles {
+		if theme, ok := settings["theme"].(string); ok && isRelativeTheme(theme) {
+			settings["theme"] = filepath.Join(dir, theme)
+		}
+		if _, _, err := decodeProfile(name, settings); err != nil {
+			return md, fmt.Errorf("invalid config %s: %w", source, err)
+		}
+	}
+	return md, nil
+}
+
+func isRelativeTheme(theme string) bool {
+	return strings.HasSuffix(theme, ".toml") && !filepath.IsAbs(theme)
+}
@@ -0,0 +1,205 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFile(t *testing.T) {
+	path := writeConfig(t, `
+hidden = true
+sort_size = true
+color = "never"
+theme = "mine.toml"
+exclude = ["node_modules", "*.log"]
+
+[icon_map]
+".go" = "G"
+`)
+
+	cfg := NewDefaultConfig()
+	if err := cfg.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+
+	if !cfg.ShowHidden || !cfg.SortSize || cfg.ColorMode != "never" {
+		t.Errorf("LoadFile() flags = %+v", cfg)
+	}
+	if cfg.MaxDepth != 30 {
+		t.Errorf("MaxDepth = %d, want default 30 to be kept", cfg.MaxDepth)
+	}
+	if want := filepath.Join(filepath.Dir(path), "mine.toml"); cfg.Theme != want {
+		t.Errorf("Theme = %q, want %q", cfg.Theme, want)
+	}
+	if !reflect.DeepEqual(cfg.ExcludePatterns, []string{"node_modules", "*.log"}) {
+		t.Errorf("ExcludePatterns = %v", cfg.ExcludePatterns)
+	}
+	if cfg.IconOverrides[".go"] != "G" {
+		t.Errorf("IconOverrides = %v", cfg.IconOverrides)
+	}
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "unknown key", content: "hiden = true\n", want: "unknown key hiden"},
+		{name: "exec not allowed", content: "exec = \"rm {}\"\n", want: "unknown key exec"},
+		{name: "wrong type", content: "max_depth = \"deep\"\n", want: "invalid config"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultConfig()
+			err := cfg.LoadFile(writeConfig(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadFile() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFileMissing(t *testing.T) {
+	cfg := NewDefaultConfig()
+	if err := cfg.LoadFile(filepath.Join(t.TempDir(), "missing.toml")); err != nil {
+		t.Errorf("LoadFile() error = %v, want nil", err)
+	}
+}
+
+func TestLoadLocal(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "pkg", "api")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	content := "exclude = [\"vendor\"]\nhidden = true\nsort_size = true\n[icon_map]\ndir = \"D\"\n"
+	if err := os.WriteFile(filepath.Join(root, LocalFile), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	path, ok := FindLocal(sub)
+	if !ok || path != filepath.Join(root, LocalFile) {
+		t.Fatalf("FindLocal() = %q, %v", path, ok)
+	}
+
+	cfg := NewDefaultConfig()
+	cfg.ExcludePatterns = []string{"*.log"}
+	cfg.IconOverrides = map[string]string{".go": "G"}
+	given := func(key string) bool { return key == "sort_size" }
+	if err := cfg.LoadLocal(path, given); err != nil {
+		t.Fatalf("LoadLocal() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(cfg.ExcludePatterns, []string{"*.log", "vendor"}) {
+		t.Errorf("ExcludePatterns = %v, want local patterns appended", cfg.ExcludePatterns)
+	}
+	if !reflect.DeepEqual(cfg.IconOverrides, map[string]string{".go": "G", "dir": "D"}) {
+		t.Errorf("IconOverrides = %v, want maps merged", cfg.IconOverrides)
+	}
+	if !cfg.ShowHidden {
+		t.Errorf("ShowHidden = false, want the local value")
+	}
+	if cfg.SortSize {
+		t.Errorf("SortSize = true, want the flag to win")
+	}
+	if cfg.MaxDepth != 30 {
+		t.Errorf("MaxDepth = %d, want keys missing from the file left alone", cfg.MaxDepth)
+	}
+}
+
+func TestApplyProfile(t *testing.T) {
+	path := writeConfig(t, `
+git = true
+
+[profiles.media]
+sort_size = true
+icons = true
+theme = "media.toml"
+
+[profiles.work]
+user = true
+`)
+
+	cfg := NewDefaultConfig()
+	if err := cfg.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	given := func(key string) bool { return key == "icons" }
+	if err := cfg.ApplyProfile("media", given); err != nil {
+		t.Fatalf("ApplyProfile() error = %v", err)
+	}
+
+	if !cfg.ShowGit || !cfg.SortSize || cfg.ShowUser {
+		t.Errorf("ApplyProfile() = %+v", cfg)
+	}
+	if cfg.ShowIcons {
+		t.Errorf("ShowIcons = true, want the flag to win")
+	}
+	if want := filepath.Join(filepath.Dir(path), "media.toml"); cfg.Theme != want {
+		t.Errorf("Theme = %q, want %q", cfg.Theme, want)
+	}
+
+	err := cfg.ApplyProfile("nope", given)
+	if err == nil || !strings.Contains(err.Error(), "available: media, work") {
+		t.Errorf("ApplyProfile(nope) error = %v", err)
+	}
+}
+
+func TestProfileValidation(t *testing.T) {
+	cfg := NewDefaultConfig()
+	err := cfg.LoadFile(writeConfig(t, "[profiles.typo]\nsort_sise = true\n"))
+	if err == nil || !strings.Contains(err.Error(), "unknown key sort_sise") {
+		t.Errorf("LoadFile() error = %v, want the profile key rejected", err)
+	}
+
+	cfg = NewDefaultConfig()
+	err = cfg.LoadFile(writeConfig(t, "[profiles.ci]\ndefault_flags = [\"-g\"]\n"))
+	if err == nil || !strings.Contains(err.Error(), "default_flags cannot be set inside a profile") {
+		t.Errorf("LoadFile() error = %v, want default_flags in a profile rejected", err)
+	}
+}
+
+func TestLoadLocalDefaultFlags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), LocalFile)
+	if err := os.WriteFile(path, []byte("default_flags = [\"-g\"]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := NewDefaultConfig()
+	err := cfg.LoadLocal(path, func(string) bool { return false })
+	if err == nil || !strings.Contains(err.Error(), "default_flags is only read from config.toml") {
+		t.Errorf("LoadLocal() error = %v, want default_flags rejected", err)
+	}
+}
+
+func TestJunkExcludes(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.ExcludePatterns = []string{"*.log"}
+	if got := cfg.Excludes(); !reflect.DeepEqual(got, []string{"*.log"}) {
+		t.Errorf("Excludes() without no_junk = %v", got)
+	}
+
+	path := writeConfig(t, "no_junk = true\njunk = [\"dist\", \"*.pyc\"]\n")
+	if err := cfg.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if got, want := cfg.Excludes(), []string{"*.log", "dist", "*.pyc"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Excludes() = %v, want %v, the configured list replacing the default", got, want)
+	}
+	if !reflect.DeepEqual(cfg.ExcludePatterns, []string{"*.log"}) {
+		t.Errorf("Excludes() changed ExcludePatterns to %v", cfg.ExcludePatterns)
+	}
+}
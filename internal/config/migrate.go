@@ -0,0 +1,260 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ipanardian/lu-hut/internal/state"
+)
+
+// SchemaVersion is the config file layout this build reads and writes.
+// Files without a version key predate versioning and count as version 0.
+const SchemaVersion = 1
+
+// migration lists the keys renamed when the layout moved to version.
+type migration struct {
+	version int
+	renames map[string]string
+}
+
+// migrations holds every key rename in release order. When a key changes
+// name, bump SchemaVersion and add an entry here: older files keep loading
+// (with a warning) and `lu config migrate` rewrites them.
+var migrations []migration
+
+// Rename records one key that was or would be rewritten.
+type Rename struct {
+	Table string
+	From  string
+	To    string
+}
+
+func (r Rename) String() string {
+	if r.Table == "" {
+		return fmt.Sprintf("%s -> %s", r.From, r.To)
+	}
+	return fmt.Sprintf("[%s] %s -> %s", r.Table, r.From, r.To)
+}
+
+var (
+	tableLine = regexp.MustCompile(`^\s*\[\s*([^\[\]]+?)\s*\]\s*(#.*)?$`)
+	keyLine   = regexp.MustCompile(`^(\s*)([A-Za-z0-9_-]+)(\s*=.*)$`)
+)
+
+// fileVersion reads the version key of a config file.
+func fileVersion(data string) (int, error) {
+	var header struct {
+		Version int `toml:"version"`
+	}
+	_, err := toml.Decode(data, &header)
+	return header.Version, err
+}
+
+// renameKeys applies the renames of every migration newer than version to
+// the top-level keys and the keys of [profiles.NAME] tables. It edits the
+// text line by line so comments and layout survive.
+func renameKeys(data string, version int) (string, []Rename) {
+	renames := make(map[string]string)
+	for _, m := range migrations {
+		if m.version <= version {
+			continue
+		}
+		for from, to := range m.renames {
+			renames[from] = to
+			// A later rename of the new name follows the chain.
+			for old, current := range renames {
+				if current == from {
+					renames[old] = to
+				}
+			}
+		}
+	}
+	if len(renames) == 0 {
+		return data, nil
+	}
+
+	var (
+		applied []Rename
+		table   string
+	)
+	lines := strings.Split(data, "\n")
+	for i, line := range lines {
+		if m := tableLine.FindStringSubmatch(line); m != nil {
+			table = m[1]
+			continue
+		}
+		if table != "" && !strings.HasPrefix(table, "profiles.") {
+			continue
+		}
+		m := keyLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if to, ok := renames[m[2]]; ok {
+			lines[i] = m[1] + to + m[3]
+			applied = append(applied, Rename{Table: table, From: m[2], To: to})
+		}
+	}
+	return strings.Join(lines, "\n"), applied
+}
+
+// setVersion writes version = SchemaVersion, replacing an existing
+// top-level version key or adding one after the leading comments.
+func setVersion(data string) string {
+	line := "version = " + strconv.Itoa(SchemaVersion)
+	lines := strings.Split(data, "\n")
+	insert := 0
+	for i, l := range lines {
+		if tableLine.MatchString(l) {
+			break
+		}
+		if m := keyLine.FindStringSubmatch(l); m != nil && m[2] == "version" {
+			lines[i] = line
+			return strings.Join(lines, "\n")
+		}
+		if trimmed := strings.TrimSpace(l); insert == i && strings.HasPrefix(trimmed, "#") {
+			insert = i + 1
+		}
+	}
+
+	out := append([]string{}, lines[:insert]...)
+	if insert > 0 {
+		out = append(out, "")
+	}
+	out = append(out, line)
+	if insert < len(lines) && strings.TrimSpace(lines[insert]) != "" {
+		out = append(out, "")
+	}
+	return strings.Join(append(out, lines[insert:]...), "\n")
+}
+
+// upgrade brings data from an older layout up to SchemaVersion in memory,
+// warning on stderr so the file gets migrated for good.
+func upgrade(data, source string) (string, error) {
+	version, err := fileVersion(data)
+	if err != nil {
+		return data, fmt.Errorf("invalid config %s: %w", source, err)
+	}
+	if version > SchemaVersion {
+		fmt.Fprintf(os.Stderr, "Warning: %s is version %d but this lu only knows version %d; update lu if settings are rejected\n", source, version, SchemaVersion)
+		return data, nil
+	}
+
+	data, renamed := renameKeys(data, version)
+	for _, r := range renamed {
+		fmt.Fprintf(os.Stderr, "Warning: %s: renamed key %s; run 'lu config migrate' to update the file\n", source, r)
+	}
+	return data, nil
+}
+
+// MigrateFile rewrites the config file at path to the current layout. It
+// returns the renames performed and whether anything changed; with dryRun
+// the file is left untouched. The previous content is kept in path.bak.
+func MigrateFile(path string, dryRun bool) ([]Rename, bool, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false, err
+	}
+	data := string(raw)
+
+	version, err := fileVersion(data)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if version > SchemaVersion {
+		return nil, false, fmt.Errorf("%s is version %d, newer than this lu supports (%d)", path, version, SchemaVersion)
+	}
+
+	migrated, renamed := renameKeys(data, version)
+	migrated = setVersion(migrated)
+	if migrated == data {
+		return nil, false, nil
+	}
+
+	var check Config
+	if _, err := check.decode(migrated, path, ""); err != nil {
+		return renamed, true, err
+	}
+	if dryRun {
+		return renamed, true, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return renamed, true, err
+	}
+	if err := state.WriteFile(path+".bak", raw, info.Mode().Perm()); err != nil {
+		return renamed, true, err
+	}
+	return renamed, true, state.WriteFile(path, []byte(migrated), info.Mode().Perm())
+}
+
+// suggestKey returns the known key closest to an unknown one, for "did you
+// mean" hints, or "" when nothing is close.
+func suggestKey(unknown string) string {
+	if to, ok := renamedTo(unknown); ok {
+		return to
+	}
+
+	best, bestDist := "", 3
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		key := t.Field(i).Tag.Get("toml")
+		if key == "" || key == "-" {
+			continue
+		}
+		if d := editDistance(unknown, key); d < bestDist && d < len(key) {
+			best, bestDist = key, d
+		}
+	}
+	return best
+}
+
+func renamedTo(key string) (string, bool) {
+	for i := len(migrations) - 1; i >= 0; i-- {
+		if to, ok := migrations[i].renames[key]; ok {
+			return to, true
+		}
+	}
+	return "", false
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// describeUnknown formats unknown keys with a suggestion where one exists.
+func describeUnknown(keys []string) string {
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key
+		name := key[strings.LastIndex(key, ".")+1:]
+		if suggestion := suggestKey(name); suggestion != "" {
+			parts[i] += fmt.Sprintf(" (did you mean %s?)", suggestion)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func withMigrations(t *testing.T, m []migration) {
+	saved := migrations
+	migrations = m
+	t.Cleanup(func() { migrations = saved })
+}
+
+func TestRenameKeys(t *testing.T) {
+	withMigrations(t, []migration{
+		{version: 2, renames: map[string]string{"show_hidden": "hidden_files"}},
+		{version: 3, renames: map[string]string{"hidden_files": "hidden", "icons_on": "icons"}},
+	})
+
+	content := "# keep me\nshow_hidden = true # inline\n[icon_map]\nicons_on = \"x\"\n[profiles.work]\n  icons_on = true\n"
+	got, renamed := renameKeys(content, 1)
+
+	want := "# keep me\nhidden = true # inline\n[icon_map]\nicons_on = \"x\"\n[profiles.work]\n  icons = true\n"
+	if got != want {
+		t.Errorf("renameKeys() =\n%s\nwant\n%s", got, want)
+	}
+	wantRenamed := []Rename{{From: "show_hidden", To: "hidden"}, {Table: "profiles.work", From: "icons_on", To: "icons"}}
+	if !reflect.DeepEqual(renamed, wantRenamed) {
+		t.Errorf("renamed = %v, want %v", renamed, wantRenamed)
+	}
+
+	if got, renamed := renameKeys("hidden_files = true\n", 2); got != "hidden = true\n" || len(renamed) != 1 {
+		t.Errorf("renameKeys() from version 2 = %q, %v", got, renamed)
+	}
+}
+
+func TestSetVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{name: "empty", content: "", expected: "version = 1\n"},
+		{name: "after comments", content: "# lu\n# config\nhidden = true\n", expected: "# lu\n# config\n\nversion = 1\n\nhidden = true\n"},
+		{name: "replaced", content: "version = 0\nhidden = true\n", expected: "version = 1\nhidden = true\n"},
+		{name: "table key untouched", content: "[profiles.a]\nversion = 3\n", expected: "version = 1\n\n[profiles.a]\nversion = 3\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := setVersion(tt.content); got != tt.expected {
+				t.Errorf("setVersion() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMigrateFile(t *testing.T) {
+	withMigrations(t, []migration{{version: 1, renames: map[string]string{"dotfiles": "hidden"}}})
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	original := "# lu\ndotfiles = true\n"
+	if err := os.WriteFile(path, []byte(original), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Loading an old file works before it is migrated.
+	var cfg Config
+	if err := cfg.LoadFile(path); err != nil || !cfg.ShowHidden {
+		t.Fatalf("LoadFile() = %v, hidden %v", err, cfg.ShowHidden)
+	}
+
+	if _, changed, err := MigrateFile(path, true); err != nil || !changed {
+		t.Fatalf("dry run = %v, %v", changed, err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != original {
+		t.Errorf("dry run changed the file: %q", data)
+	}
+
+	renamed, changed, err := MigrateFile(path, false)
+	if err != nil || !changed || len(renamed) != 1 {
+		t.Fatalf("MigrateFile() = %v, %v, %v", renamed, changed, err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "# lu\n\nversion = 1\n\nhidden = true\n" {
+		t.Errorf("migrated file = %q", data)
+	}
+	if data, _ := os.ReadFile(path + ".bak"); string(data) != original {
+		t.Errorf("backup = %q, want the original", data)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("migrated file mode = %v, %v; want 0600", info, err)
+	}
+
+	if _, changed, err := MigrateFile(path, false); err != nil || changed {
+		t.Errorf("second MigrateFile() = %v, %v; want no change", changed, err)
+	}
+}
+
+func TestMigrateFileNewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte("version = 99\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := MigrateFile(path, false); err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Errorf("MigrateFile() error = %v, want a newer-version error", err)
+	}
+}
+
+func TestUnknownKeySuggestion(t *testing.T) {
+	var cfg Config
+	_, err := cfg.decode("hiden = true\nzzzzzz = 1\n", "test", "")
+	if err == nil || !strings.Contains(err.Error(), "hiden (did you mean hidden?), zzzzzz") {
+		t.Errorf("decode() error = %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+// Package deadline bounds how long lu waits for metadata that can hang,
+// such as a name service lookup against an unreachable LDAP server.
+package deadline
+
+import "time"
+
+// Run calls fn and waits at most timeout for its result. It reports false
+// when the time ran out; fn then keeps running in the background and its
+// result is dropped, so it must not touch state the caller keeps using.
+// A timeout of zero or less waits as long as fn takes.
+func Run[T any](timeout time.Duration, fn func() T) (T, bool) {
+	if timeout <= 0 {
+		return fn(), true
+	}
+
+	done := make(chan T, 1)
+	go func() { done <- fn() }()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case result := <-done:
+		return result, true
+	case <-timer.C:
+		var zero T
+		return zero, false
+	}
+}
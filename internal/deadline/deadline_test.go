@@ -0,0 +1,22 @@
+package deadline
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	if result, ok := Run(time.Second, func() int { return 42 }); !ok || result != 42 {
+		t.Errorf("Run() = %d, %v; want 42, true", result, ok)
+	}
+
+	release := make(chan struct{})
+	defer close(release)
+	if result, ok := Run(10*time.Millisecond, func() int { <-release; return 1 }); ok || result != 0 {
+		t.Errorf("Run() = %d, %v; want 0, false after the timeout", result, ok)
+	}
+
+	if result, ok := Run(0, func() string { return "no limit" }); !ok || result != "no limit" {
+		t.Errorf("Run(0) = %q, %v", result, ok)
+	}
+}
@@ -0,0 +1,259 @@
+// Package doctor inspects the environment lu runs in, so problems such as a
+// broken config file or a terminal without color support can be found
+// without guessing why a listing looks wrong.
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ipanardian/lu-hut/internal/config"
+	"github.com/ipanardian/lu-hut/internal/renderer"
+	"github.com/ipanardian/lu-hut/internal/state"
+	"github.com/ipanardian/lu-hut/internal/terminal"
+	"github.com/ipanardian/lu-hut/internal/theme"
+	"golang.org/x/term"
+)
+
+type Status int
+
+const (
+	OK Status = iota
+	Warn
+	Fail
+)
+
+// Result is the outcome of one check. Hint says what to do about a
+// warning or failure.
+type Result struct {
+	Section string
+	Name    string
+	Status  Status
+	Detail  string
+	Hint    string
+}
+
+// Run performs every check, loading the configuration the same way a
+// listing of dir would.
+func Run(dir string) []Result {
+	cfg, results := checkConfig(dir)
+	results = append(results, checkTheme(cfg.Theme))
+	results = append(results, checkTerminal(cfg)...)
+	results = append(results, checkGit())
+	results = append(results, checkCache()...)
+	return results
+}
+
+func checkConfig(dir string) (config.Config, []Result) {
+	cfg := config.NewDefaultConfig()
+	var results []Result
+	add := func(name string, err error, detail, hint string) {
+		r := Result{Section: "config", Name: name, Detail: detail}
+		if err != nil {
+			r.Status, r.Detail, r.Hint = Fail, err.Error(), hint
+		}
+		results = append(results, r)
+	}
+
+	path, err := config.Path()
+	switch {
+	case err != nil:
+		add("config file", err, "", "set HOME or XDG_CONFIG_HOME")
+	case !exists(path):
+		add("config file", nil, path+" (not present, defaults apply)", "")
+	default:
+		add("config file", cfg.LoadFile(path), path, "fix the file or move it aside: "+path)
+	}
+
+	add("environment", cfg.LoadEnv(os.Getenv), envSummary(), "check the LU_* variables")
+
+	if local, ok := config.FindLocal(dir); ok {
+		add("local file", cfg.LoadLocal(local, func(string) bool { return false }), local, "fix or remove "+local)
+	}
+
+	if cfg.Profile != "" {
+		add("profile", cfg.ApplyProfile(cfg.Profile, func(string) bool { return false }), cfg.Profile, "define [profiles."+cfg.Profile+"] or unset profile")
+	}
+
+	add("settings", cfg.Validate(), "valid", "adjust the conflicting options")
+	return cfg, results
+}
+
+func envSummary() string {
+	var names []string
+	for _, kv := range os.Environ() {
+		if name, _, _ := strings.Cut(kv, "="); strings.HasPrefix(name, "LU_") {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "no LU_* variables set"
+	}
+	return strings.Join(names, ", ")
+}
+
+func checkTheme(name string) Result {
+	r := Result{Section: "theme", Name: "theme", Detail: name}
+	if name == "" {
+		r.Detail = "default"
+	}
+	if _, err := theme.Load(name); err != nil {
+		r.Status, r.Detail = Fail, err.Error()
+		r.Hint = "use a preset (default, light, cb-friendly, monochrome, solarized, dracula), auto or a valid theme file"
+	}
+	return r
+}
+
+func checkTerminal(cfg config.Config) []Result {
+	var results []Result
+
+	tty := term.IsTerminal(int(os.Stdout.Fd()))
+	r := Result{Section: "terminal", Name: "output", Detail: "terminal"}
+	if !tty {
+		r.Detail = "not a terminal (pipe or file)"
+	}
+	results = append(results, r)
+
+	r = Result{Section: "terminal", Name: "color", Detail: "enabled"}
+	switch {
+	case cfg.ColorMode == "never":
+		r.Detail = "disabled by --color=never"
+	case !terminal.ColorEnabled(cfg.ColorMode) && os.Getenv("NO_COLOR") != "":
+		r.Status, r.Detail, r.Hint = Warn, "disabled by NO_COLOR", "unset NO_COLOR or pass --color=always"
+	case !terminal.ColorEnabled(cfg.ColorMode) && os.Getenv("TERM") == "dumb":
+		r.Status, r.Detail, r.Hint = Warn, "disabled because TERM=dumb", "set TERM to your terminal type, e.g. xterm-256color"
+	case !terminal.ColorEnabled(cfg.ColorMode):
+		r.Detail = "disabled while output is not a terminal"
+	}
+	results = append(results, r)
+
+	r = Result{Section: "terminal", Name: "truecolor", Detail: "supported (COLORTERM=" + os.Getenv("COLORTERM") + ")"}
+	if !terminal.SupportsTruecolor() {
+		r.Status, r.Detail = Warn, "not advertised, gradients fall back to 256 colors"
+		r.Hint = "export COLORTERM=truecolor if your terminal supports 24-bit color"
+	}
+	results = append(results, r)
+
+	r = Result{Section: "terminal", Name: "unicode", Detail: "supported"}
+	if !terminal.SupportsUnicode() {
+		r.Status, r.Detail = Warn, "not detected, --ascii is used automatically"
+		r.Hint = "use a UTF-8 locale (e.g. LANG=en_US.UTF-8) or run chcp 65001 on Windows"
+	}
+	results = append(results, r)
+
+	width, source := renderer.TerminalWidth()
+	r = Result{Section: "terminal", Name: "width", Detail: fmt.Sprintf("%d columns (from %s)", width, source)}
+	if source == "fallback" {
+		r.Status, r.Hint = Warn, "set COLUMNS if tables are too narrow or wrap"
+	}
+	results = append(results, r)
+
+	r = Result{Section: "terminal", Name: "nerd font"}
+	if font, ok := findNerdFont(); ok {
+		r.Detail = "found " + font
+	} else {
+		r.Detail = "none found in the usual font directories (only needed for --icons)"
+		if cfg.ShowIcons {
+			r.Status, r.Hint = Warn, "install a Nerd Font from https://www.nerdfonts.com or turn off icons"
+		}
+	}
+	results = append(results, r)
+
+	return results
+}
+
+// findNerdFont looks for an installed font whose file name mentions Nerd
+// Font. Which font the terminal actually uses cannot be queried, so this
+// only tells whether one is available.
+func findNerdFont() (string, bool) {
+	var dirs []string
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs,
+			filepath.Join(home, ".local", "share", "fonts"),
+			filepath.Join(home, ".fonts"),
+			filepath.Join(home, "Library", "Fonts"),
+		)
+	}
+	if local := os.Getenv("LOCALAPPDATA"); local != "" {
+		dirs = append(dirs, filepath.Join(local, "Microsoft", "Windows", "Fonts"))
+	}
+	dirs = append(dirs, "/usr/share/fonts", "/usr/local/share/fonts", "/Library/Fonts", `C:\Windows\Fonts`)
+
+	for _, dir := range dirs {
+		var found string
+		filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return filepath.SkipDir
+			}
+			if !d.IsDir() && strings.Contains(strings.ToLower(d.Name()), "nerd") {
+				found = d.Name()
+				return filepath.SkipAll
+			}
+			return nil
+		})
+		if found != "" {
+			return found, true
+		}
+	}
+	return "", false
+}
+
+func checkGit() Result {
+	r := Result{Section: "git", Name: "git"}
+	path, err := exec.LookPath("git")
+	if err != nil {
+		r.Status, r.Detail = Warn, "not found in PATH, --git and --git-ignore have no effect"
+		r.Hint = "install git or add it to PATH"
+		return r
+	}
+	out, err := exec.Command(path, "--version").Output()
+	if err != nil {
+		r.Status, r.Detail, r.Hint = Warn, fmt.Sprintf("%s does not run: %v", path, err), "reinstall git"
+		return r
+	}
+	r.Detail = strings.TrimSpace(string(out))
+	return r
+}
+
+func checkCache() []Result {
+	dir, err := state.CacheDir()
+	if err != nil {
+		return []Result{{Section: "cache", Name: "directory", Status: Warn, Detail: err.Error(), Hint: "set HOME or XDG_CACHE_HOME"}}
+	}
+	results := []Result{{Section: "cache", Name: "directory", Detail: dir}}
+	if locks, err := state.StateDir(); err == nil {
+		results = append(results, Result{Section: "cache", Name: "lock files", Detail: locks})
+	}
+
+	r := Result{Section: "cache", Name: "update check"}
+	info, err := os.Stat(filepath.Join(dir, "last_check"))
+	switch {
+	case err != nil:
+		r.Detail = "never run"
+	case time.Since(info.ModTime()) > 7*24*time.Hour:
+		r.Status, r.Detail = Warn, "last run "+info.ModTime().Format(time.DateOnly)
+		r.Hint = "the daily update check is not completing; check network access and that " + dir + " is writable"
+	default:
+		r.Detail = "last run " + info.ModTime().Format(time.DateTime)
+	}
+	results = append(results, r)
+
+	if home, err := os.UserHomeDir(); err == nil && exists(filepath.Join(home, ".lu-hut")) {
+		legacy := filepath.Join(home, ".lu-hut")
+		results = append(results, Result{
+			Section: "cache", Name: "old cache", Status: Warn,
+			Detail: legacy + " could not be moved",
+			Hint:   "remove " + legacy + ", it is no longer used",
+		})
+	}
+	return results
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
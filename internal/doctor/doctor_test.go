@@ -0,0 +1,72 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		failing string
+	}{
+		{name: "valid", content: "hidden = true\n"},
+		{name: "unknown key", content: "hiden = true\n", failing: "config file"},
+		{name: "conflicting options", content: "tree = true\ndu = true\n", failing: "settings"},
+		{name: "missing profile", content: "profile = \"work\"\n", failing: "profile"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Setenv("XDG_CONFIG_HOME", dir)
+			if err := os.MkdirAll(filepath.Join(dir, "lu-hut"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "lu-hut", "config.toml"), []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, results := checkConfig(t.TempDir())
+			for _, r := range results {
+				if failed := r.Status == Fail; failed != (r.Name == tt.failing) {
+					t.Errorf("%s: status %v (%s), want failure only for %q", r.Name, r.Status, r.Detail, tt.failing)
+				}
+				if r.Status == Fail && r.Hint == "" {
+					t.Errorf("%s: failure without a hint", r.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestEnvironmentFacts(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(name string) string { return vars[name] }
+	}
+
+	depths := []struct {
+		enabled  bool
+		vars     map[string]string
+		expected string
+	}{
+		{false, map[string]string{"COLORTERM": "truecolor"}, "none (color disabled)"},
+		{true, map[string]string{"COLORTERM": "truecolor"}, "24-bit (COLORTERM=truecolor)"},
+		{true, map[string]string{"TERM": "xterm-256color"}, "256 colors"},
+		{true, map[string]string{"TERM": "xterm"}, "16 colors"},
+	}
+	for _, tt := range depths {
+		if got := colorDepth(tt.enabled, env(tt.vars)); got != tt.expected {
+			t.Errorf("colorDepth(%v, %v) = %q, want %q", tt.enabled, tt.vars, got, tt.expected)
+		}
+	}
+
+	if got := locale(env(map[string]string{"LANG": "en_US.UTF-8", "LC_ALL": "C"}), true); got != "LC_ALL=C, unicode box drawing" {
+		t.Errorf("locale() = %q, want LC_ALL to win over LANG", got)
+	}
+	if got := terminalType(env(nil), false); got != "TERM unset, stdout is not a terminal" {
+		t.Errorf("terminalType() = %q", got)
+	}
+}
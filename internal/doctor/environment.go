@@ -0,0 +1,89 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/ipanardian/lu-hut/internal/config"
+	"github.com/ipanardian/lu-hut/internal/constants"
+	"github.com/ipanardian/lu-hut/internal/git"
+	"github.com/ipanardian/lu-hut/internal/renderer"
+	"github.com/ipanardian/lu-hut/internal/terminal"
+	"golang.org/x/term"
+)
+
+// Fact is one line of the environment report printed by --debug.
+type Fact struct {
+	Name  string
+	Value string
+}
+
+// Environment describes what lu detected about the terminal and its tools,
+// the details a rendering bug report needs first: terminal type, color
+// depth, where the width came from, locale and the git backend.
+func Environment(cfg config.Config) []Fact {
+	width, source := renderer.TerminalWidth()
+	return []Fact{
+		{"lu", fmt.Sprintf("%s (%s, %s/%s)", constants.Version, runtime.Version(), runtime.GOOS, runtime.GOARCH)},
+		{"terminal", terminalType(os.Getenv, term.IsTerminal(int(os.Stdout.Fd())))},
+		{"color depth", colorDepth(terminal.ColorEnabled(cfg.ColorMode), os.Getenv)},
+		{"width", fmt.Sprintf("%d columns (from %s)", width, source)},
+		{"locale", locale(os.Getenv, terminal.SupportsUnicode())},
+		{"git", gitBackend()},
+	}
+}
+
+func terminalType(getenv func(string) string, tty bool) string {
+	kind := "TERM=" + getenv("TERM")
+	if getenv("TERM") == "" {
+		kind = "TERM unset"
+	}
+	if program := getenv("TERM_PROGRAM"); program != "" {
+		kind += ", " + program
+	}
+	if !tty {
+		kind += ", stdout is not a terminal"
+	}
+	return kind
+}
+
+// colorDepth names the palette lu draws with: 24-bit when COLORTERM says
+// so, 256 colors for *-256color terminals and the basic 16 otherwise.
+func colorDepth(enabled bool, getenv func(string) string) string {
+	switch {
+	case !enabled:
+		return "none (color disabled)"
+	case strings.EqualFold(getenv("COLORTERM"), "truecolor"), strings.EqualFold(getenv("COLORTERM"), "24bit"):
+		return "24-bit (COLORTERM=" + getenv("COLORTERM") + ")"
+	case strings.Contains(getenv("TERM"), "256color"):
+		return "256 colors"
+	}
+	return "16 colors"
+}
+
+// locale reports the variable that decides the character set, in the order
+// the C library consults them, and whether box drawing is used.
+func locale(getenv func(string) string, unicode bool) string {
+	drawing := "unicode box drawing"
+	if !unicode {
+		drawing = "ascii box drawing"
+	}
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := getenv(name); value != "" {
+			return name + "=" + value + ", " + drawing
+		}
+	}
+	return "unset, " + drawing
+}
+
+// gitBackend reports how git information is gathered: the status backend
+// and the version of the git binary found in PATH.
+func gitBackend() string {
+	r := checkGit()
+	if r.Status != OK {
+		return r.Detail
+	}
+	return git.DefaultStatusBackend().Name() + ", " + r.Detail
+}
@@ -0,0 +1,134 @@
+// Package du adds up directory sizes the way du does, counting content
+// reachable through hardlinks (and followed symlinks) only once.
+package du
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/ipanardian/lu-hut/internal/model"
+	"github.com/ipanardian/lu-hut/internal/pseudofs"
+)
+
+// Usage is an "apparent vs unique" pair. Apparent counts every path it
+// saw; Unique counts each file once no matter how many names lead to it.
+type Usage struct {
+	Apparent int64
+	Unique   int64
+}
+
+func (u *Usage) add(other Usage) {
+	u.Apparent += other.Apparent
+	u.Unique += other.Unique
+}
+
+// Counter remembers every file it has counted, so content shared between
+// several directories of one listing is only unique in the first of them.
+type Counter struct {
+	follow bool
+	skip   bool
+	seen   map[fileID]bool
+	total  Usage
+	fs     *Filesystems
+}
+
+// New returns a Counter. With follow set, symlinks are resolved and their
+// targets counted (directories are walked); otherwise a symlink counts as
+// the link itself.
+func New(follow bool) *Counter {
+	return &Counter{follow: follow, seen: make(map[fileID]bool)}
+}
+
+// Enclose marks dir, usually the directory being listed, as already
+// visited so followed symlinks that lead back up into it are not walked.
+func (c *Counter) Enclose(dir string) {
+	if info, err := os.Stat(dir); err == nil {
+		if id, ok := idOf(info); ok {
+			c.seen[id] = true
+		}
+	}
+}
+
+// SkipSpecial makes the walk pass over device nodes, FIFOs and sockets
+// without calling lstat on them.
+func (c *Counter) SkipSpecial(skip bool) {
+	c.skip = skip
+}
+
+// Track also reports every counted file to fs.
+func (c *Counter) Track(fs *Filesystems) {
+	c.fs = fs
+}
+
+// Total is the sum of everything counted so far.
+func (c *Counter) Total() Usage {
+	return c.total
+}
+
+// Count returns the usage of path, walking it when it is a directory.
+// Unreadable entries are skipped, as du does after reporting them.
+func (c *Counter) Count(ctx context.Context, path string) Usage {
+	usage := c.count(ctx, path, 0)
+	c.total.add(usage)
+	return usage
+}
+
+// maxLinkDepth bounds how many symlinked directories may be nested while
+// following, as a backstop to the cycle check.
+const maxLinkDepth = 40
+
+func (c *Counter) count(ctx context.Context, path string, links int) Usage {
+	if ctx.Err() != nil {
+		return Usage{}
+	}
+
+	info, err := os.Lstat(path)
+	if err != nil {
+		return Usage{}
+	}
+	if info.Mode()&fs.ModeSymlink != 0 && c.follow {
+		if links >= maxLinkDepth {
+			return Usage{}
+		}
+		if target, err := os.Stat(path); err == nil {
+			info = target
+			links++
+		}
+	}
+
+	usage := Usage{Apparent: info.Size()}
+	if id, ok := idOf(info); ok {
+		if c.seen[id] {
+			// Already counted: a hardlink, a followed symlink to known
+			// content, or a directory cycle. Never descend twice.
+			return Usage{Apparent: usage.Apparent}
+		}
+		c.seen[id] = true
+	}
+	usage.Unique = usage.Apparent
+	if c.fs != nil {
+		c.fs.Add(path, info)
+	}
+
+	if !info.IsDir() {
+		return usage
+	}
+
+	// Kernel pseudo-filesystems report fake sizes and can be endless.
+	if pseudofs.Is(path) {
+		return usage
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return usage
+	}
+	for _, entry := range entries {
+		if c.skip && model.IsSpecial(entry.Type()) {
+			continue
+		}
+		usage.add(c.count(ctx, filepath.Join(path, entry.Name()), links))
+	}
+	return usage
+}
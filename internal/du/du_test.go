@@ -0,0 +1,115 @@
+package du
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCounter(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hardlink detection needs inode numbers")
+	}
+
+	root := t.TempDir()
+	for _, dir := range []string{"a", "b"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data := filepath.Join(root, "a", "data")
+	if err := os.WriteFile(data, make([]byte, 10000), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Link(data, filepath.Join(root, "b", "hard")); err != nil {
+		t.Skip("hardlinks not supported:", err)
+	}
+	if err := os.Symlink(filepath.Join(root, "a"), filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(root, filepath.Join(root, "a", "loop")); err != nil {
+		t.Fatal(err)
+	}
+
+	dirSize := func(path string) int64 {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return info.Size()
+	}
+	ctx := context.Background()
+
+	t.Run("hardlinks counted once", func(t *testing.T) {
+		c := New(false)
+		a := c.Count(ctx, filepath.Join(root, "a"))
+		b := c.Count(ctx, filepath.Join(root, "b"))
+		if a.Unique < 10000 {
+			t.Errorf("a.Unique = %d, want the file included", a.Unique)
+		}
+		if want := dirSize(filepath.Join(root, "b")); b.Unique != want {
+			t.Errorf("b.Unique = %d, want only the directory itself (%d)", b.Unique, want)
+		}
+		if b.Apparent != b.Unique+10000 {
+			t.Errorf("b.Apparent = %d, want the hardlink included", b.Apparent)
+		}
+	})
+
+	t.Run("follow without cycles", func(t *testing.T) {
+		c := New(true)
+		c.Enclose(root)
+		a := c.Count(ctx, filepath.Join(root, "a"))
+		link := c.Count(ctx, filepath.Join(root, "link"))
+		if link.Unique != 0 {
+			t.Errorf("link.Unique = %d, want 0 for already counted content", link.Unique)
+		}
+		if link.Apparent != dirSize(filepath.Join(root, "a")) {
+			t.Errorf("link.Apparent = %d, want the target directory without descending again", link.Apparent)
+		}
+		if total := c.Total(); total.Unique != a.Unique {
+			t.Errorf("Total().Unique = %d, want %d", total.Unique, a.Unique)
+		}
+	})
+}
+
+func TestFilesystems(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hardlink detection needs inode numbers")
+	}
+
+	root := t.TempDir()
+	data := filepath.Join(root, "data")
+	if err := os.WriteFile(data, make([]byte, 10000), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	hard := filepath.Join(root, "hard")
+	if err := os.Link(data, hard); err != nil {
+		t.Skip("hardlinks not supported:", err)
+	}
+
+	fs := NewFilesystems()
+	for _, path := range []string{root, data, hard} {
+		info, err := os.Lstat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fs.Add(path, info)
+	}
+
+	list := fs.List()
+	if len(list) != 1 {
+		t.Fatalf("List() = %d filesystems, want 1", len(list))
+	}
+	if list[0].Entries != 2 {
+		t.Errorf("Entries = %d, want 2 (hardlink counted once)", list[0].Entries)
+	}
+	if list[0].OnDisk <= 0 {
+		t.Errorf("OnDisk = %d, want > 0", list[0].OnDisk)
+	}
+	if rel, err := filepath.Rel(list[0].Mount, root); err != nil || strings.HasPrefix(rel, "..") {
+		t.Errorf("Mount = %q, want an ancestor of %q", list[0].Mount, root)
+	}
+}
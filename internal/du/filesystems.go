@@ -0,0 +1,81 @@
+package du
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Filesystem is the space taken on one mounted filesystem.
+type Filesystem struct {
+	Mount   string
+	OnDisk  int64
+	Entries int
+}
+
+// Filesystems adds up on-disk usage per mounted filesystem, so a scan that
+// crosses mount points shows where the bytes actually live. Each file is
+// counted once, however many times it is reached.
+type Filesystems struct {
+	byVolume map[string]*Filesystem
+	order    []string
+	seen     map[fileID]bool
+}
+
+// NewFilesystems returns an empty set of per-filesystem totals.
+func NewFilesystems() *Filesystems {
+	return &Filesystems{byVolume: make(map[string]*Filesystem), seen: make(map[fileID]bool)}
+}
+
+// Add records the entry at path, described by info.
+func (f *Filesystems) Add(path string, info os.FileInfo) {
+	if id, ok := idOf(info); ok {
+		if f.seen[id] {
+			return
+		}
+		f.seen[id] = true
+	}
+
+	volume := volumeOf(path, info)
+	fs, ok := f.byVolume[volume]
+	if !ok {
+		fs = &Filesystem{Mount: mountPoint(path, volume)}
+		f.byVolume[volume] = fs
+		f.order = append(f.order, volume)
+	}
+	fs.OnDisk += onDisk(info)
+	fs.Entries++
+}
+
+// List returns the filesystems in the order they were first reached. A nil
+// Filesystems, as left behind by a --du scan that timed out, has none.
+func (f *Filesystems) List() []Filesystem {
+	if f == nil {
+		return nil
+	}
+	list := make([]Filesystem, len(f.order))
+	for i, volume := range f.order {
+		list[i] = *f.byVolume[volume]
+	}
+	return list
+}
+
+// mountPoint walks up from path to the topmost directory that is still on
+// the same volume.
+func mountPoint(path, volume string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	path, _ = filepath.Abs(path)
+
+	mount := path
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		info, err := os.Stat(dir)
+		if err != nil || volumeOf(dir, info) != volume {
+			return mount
+		}
+		mount = dir
+		if parent := filepath.Dir(dir); parent == dir {
+			return mount
+		}
+	}
+}
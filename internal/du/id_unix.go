@@ -0,0 +1,39 @@
+//go:build !windows
+
+package du
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+)
+
+type fileID struct {
+	dev uint64
+	ino uint64
+}
+
+func idOf(info os.FileInfo) (fileID, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileID{}, false
+	}
+	return fileID{dev: uint64(stat.Dev), ino: uint64(stat.Ino)}, true
+}
+
+// volumeOf identifies the filesystem holding a file by its device number.
+func volumeOf(_ string, info os.FileInfo) string {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return strconv.FormatUint(uint64(stat.Dev), 10)
+	}
+	return ""
+}
+
+// onDisk is the space allocated to a file, which is smaller than its size
+// for sparse files and larger for files that do not fill their last block.
+func onDisk(info os.FileInfo) int64 {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return int64(stat.Blocks) * 512
+	}
+	return info.Size()
+}
@@ -0,0 +1,30 @@
+//go:build windows
+
+package du
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type fileID struct{}
+
+// idOf has no inode to offer on Windows, where os.FileInfo does not expose
+// the file index, so every path counts as unique there.
+func idOf(os.FileInfo) (fileID, bool) {
+	return fileID{}, false
+}
+
+// volumeOf identifies the filesystem holding a file by its drive or UNC
+// share, the closest Windows has to a mount point without extra calls.
+func volumeOf(path string, _ os.FileInfo) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return strings.ToUpper(filepath.VolumeName(path))
+}
+
+func onDisk(info os.FileInfo) int64 {
+	return info.Size()
+}
@@ -0,0 +1,69 @@
+// Package entropy estimates how well a file would compress from the byte
+// entropy of its first few kilobytes, without compressing anything.
+package entropy
+
+import (
+	"io"
+	"math"
+	"os"
+)
+
+// SampleSize is how much of each file is read. Headers can skew a sample
+// this small, which is why the estimate is only a rating.
+const SampleSize = 16 << 10
+
+// Ratings, from most to least worth compressing.
+const (
+	High   = "high"
+	Medium = "medium"
+	Low    = "low"
+)
+
+// Rate returns the compressibility rating of the file at path, or an empty
+// string for empty or unreadable files.
+func Rate(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	buf := make([]byte, SampleSize)
+	n, _ := io.ReadFull(f, buf)
+	if n == 0 {
+		return ""
+	}
+	return Rating(Bits(buf[:n]))
+}
+
+// Bits returns the Shannon entropy of data in bits per byte, from 0 for a
+// single repeated byte to 8 for uniformly random data.
+func Bits(data []byte) float64 {
+	var counts [256]int
+	for _, b := range data {
+		counts[b]++
+	}
+	total := float64(len(data))
+	bits := 0.0
+	for _, c := range counts {
+		if c > 0 {
+			p := float64(c) / total
+			bits -= p * math.Log2(p)
+		}
+	}
+	return bits
+}
+
+// Rating maps bits per byte to a rating. Text and most source code land
+// well below 6; compressed archives, media and encrypted data sit close
+// to 8 and gain nothing from another pass.
+func Rating(bits float64) string {
+	switch {
+	case bits < 6:
+		return High
+	case bits < 7.5:
+		return Medium
+	default:
+		return Low
+	}
+}
@@ -0,0 +1,42 @@
+package entropy
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRate(t *testing.T) {
+	random := make([]byte, SampleSize)
+	rand.New(rand.NewSource(1)).Read(random)
+
+	tests := []struct {
+		name     string
+		content  []byte
+		expected string
+	}{
+		{"empty", nil, ""},
+		{"repeated byte", bytes.Repeat([]byte{0}, 4096), High},
+		{"text", bytes.Repeat([]byte("the quick brown fox jumps over the lazy dog\n"), 200), High},
+		{"random", random, Low},
+	}
+
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name)
+			if err := os.WriteFile(path, tt.content, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if result := Rate(path); result != tt.expected {
+				t.Errorf("Rate() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+
+	if result := Rate(filepath.Join(dir, "missing")); result != "" {
+		t.Errorf("Rate() on a missing file = %q, want empty", result)
+	}
+}
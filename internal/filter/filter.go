@@ -2,16 +2,34 @@
 package filter
 
 import (
+	"io/fs"
+	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/ipanardian/lu-hut/internal/model"
+	"github.com/ipanardian/lu-hut/internal/perms"
+	"github.com/ipanardian/lu-hut/internal/sniff"
 )
 
 type Filter struct {
 	includePatterns []string
 	excludePatterns []string
+	minSize         int64
+	maxSize         int64
+	root            string
+	types           map[string]bool
+	owners          []string
+	groups          []string
+	mimes           []string
+	perm            *perms.Match
+	ignore          *Ignore
 }
 
+// Types are the entry types --only accepts.
+var Types = []string{"dirs", "files", "links", "exec"}
+
 func NewFilter(includePatterns, excludePatterns []string) *Filter {
 	return &Filter{
 		includePatterns: includePatterns,
@@ -25,10 +43,13 @@ func (f *Filter) Apply(files []model.FileEntry, showHidden bool) []model.FileEnt
 		if !showHidden && file.IsHidden {
 			continue
 		}
-		if f.shouldExclude(file.Name) {
+		if f.matches(f.excludePatterns, file.Name, file.Path) || f.ignored(file.Path, func() bool { return file.IsDir }) {
+			continue
+		}
+		if len(f.includePatterns) > 0 && !f.matches(f.includePatterns, file.Name, file.Path) && !(file.IsDir && f.leadsTo(file.Path)) {
 			continue
 		}
-		if len(f.includePatterns) > 0 && !f.shouldInclude(file.Name) {
+		if !f.MatchesSize(file) {
 			continue
 		}
 		filtered = append(filtered, file)
@@ -36,8 +57,153 @@ func (f *Filter) Apply(files []model.FileEntry, showHidden bool) []model.FileEnt
 	return filtered
 }
 
-func (f *Filter) shouldExclude(name string) bool {
-	for _, pattern := range f.excludePatterns {
+// SetSizeRange limits files to sizes between min and max bytes, inclusive.
+// Zero leaves that end open.
+func (f *Filter) SetSizeRange(min, max int64) {
+	f.minSize, f.maxSize = min, max
+}
+
+// MatchesSize reports whether file lies within the size range. Directories
+// always match, so recursive listings still descend into them.
+func (f *Filter) MatchesSize(file model.FileEntry) bool {
+	if file.IsDir {
+		return true
+	}
+	if f.minSize > 0 && file.Size < f.minSize {
+		return false
+	}
+	return f.maxSize <= 0 || file.Size <= f.maxSize
+}
+
+// SetTypes limits entries to the given types, any of Types. Files covers
+// every regular file and exec only those with an execute bit; symlinks are
+// links, never files or dirs. An empty list allows everything.
+func (f *Filter) SetTypes(types []string) {
+	f.types = nil
+	if len(types) == 0 {
+		return
+	}
+	f.types = make(map[string]bool, len(types))
+	for _, t := range types {
+		f.types[t] = true
+	}
+}
+
+// MatchesType reports whether file is one of the types SetTypes allows.
+func (f *Filter) MatchesType(file model.FileEntry) bool {
+	if len(f.types) == 0 {
+		return true
+	}
+	switch {
+	case file.Mode&fs.ModeSymlink != 0:
+		return f.types["links"]
+	case file.IsDir:
+		return f.types["dirs"]
+	case file.Mode.IsRegular():
+		return f.types["files"] || (f.types["exec"] && file.Mode.Perm()&0111 != 0)
+	}
+	return false
+}
+
+// SetOwners limits entries to those owned by one of users or by one of
+// groups, given as names. Entries must pass both lists when both are set;
+// an empty list allows everyone.
+func (f *Filter) SetOwners(users, groups []string) {
+	f.owners, f.groups = users, groups
+}
+
+// MatchesOwner reports whether file belongs to a user and group SetOwners
+// allows. It relies on Author and Group being filled in.
+func (f *Filter) MatchesOwner(file model.FileEntry) bool {
+	if len(f.owners) > 0 && !slices.Contains(f.owners, file.Author) {
+		return false
+	}
+	return len(f.groups) == 0 || slices.Contains(f.groups, file.Group)
+}
+
+// SetMimes limits files to those whose sniffed Mime matches one of the
+// patterns, e.g. "image/*". Directories and anything else without a
+// detected type never match. An empty list allows everything.
+func (f *Filter) SetMimes(patterns []string) {
+	f.mimes = patterns
+}
+
+// MatchesMime reports whether file has a MIME type SetMimes allows.
+func (f *Filter) MatchesMime(file model.FileEntry) bool {
+	return len(f.mimes) == 0 || sniff.Match(f.mimes, file.Mime)
+}
+
+// SetPerm limits entries to those whose mode m selects. Symlinks never
+// match, since their own mode is meaningless on most systems. Nil allows
+// everything.
+func (f *Filter) SetPerm(m *perms.Match) {
+	f.perm = m
+}
+
+// MatchesPerm reports whether file has a mode SetPerm allows.
+func (f *Filter) MatchesPerm(file model.FileEntry) bool {
+	if f.perm == nil {
+		return true
+	}
+	return file.Mode&fs.ModeSymlink == 0 && f.perm.Matches(file.Mode)
+}
+
+// Matches reports whether file passes every filter that looks at the entry
+// itself rather than its name or size: --only, --owner, --group, --mime and
+// --perm.
+func (f *Filter) Matches(file model.FileEntry) bool {
+	return f.MatchesType(file) && f.MatchesOwner(file) && f.MatchesMime(file) && f.MatchesPerm(file)
+}
+
+// SetRoot sets the directory that path patterns are matched against, as in
+// .gitignore: "/build" matches only the build directly inside root,
+// "src/gen" only that one nested directory and "src/**/*.go" any Go file
+// below src. Patterns without a slash or "**" match the base name at any
+// depth.
+func (f *Filter) SetRoot(root string) {
+	f.root = root
+}
+
+// SetIgnore excludes what the rules of an ignore file, read from the root
+// directory, match. Nil removes them.
+func (f *Filter) SetIgnore(ig *Ignore) {
+	f.ignore = ig
+}
+
+// ignored reports whether the ignore rules match p, which is below the root.
+func (f *Filter) ignored(p string, isDir func() bool) bool {
+	if f.ignore == nil {
+		return false
+	}
+	rel, ok := f.relative(p)
+	return ok && f.ignore.Match(rel, isDir)
+}
+
+func (f *Filter) shouldExclude(path string) bool {
+	if f.matches(f.excludePatterns, filepath.Base(path), path) {
+		return true
+	}
+	return f.ignored(path, func() bool {
+		info, err := os.Lstat(path)
+		return err == nil && info.IsDir()
+	})
+}
+
+func (f *Filter) shouldInclude(path string) bool {
+	return f.matches(f.includePatterns, filepath.Base(path), path)
+}
+
+// matches checks name against plain patterns and p, the full path, against
+// path patterns.
+func (f *Filter) matches(patterns []string, name, p string) bool {
+	for _, pattern := range patterns {
+		if isPathPattern(pattern) {
+			rel, ok := f.relative(p)
+			if ok && matchPath(pattern, rel) {
+				return true
+			}
+			continue
+		}
 		if matched, _ := filepath.Match(pattern, name); matched {
 			return true
 		}
@@ -45,8 +211,37 @@ func (f *Filter) shouldExclude(name string) bool {
 	return false
 }
 
-func (f *Filter) shouldInclude(name string) bool {
+// leadsTo reports whether the directory at p could hold a match for one of
+// the include path patterns, so recursive listings still descend into it.
+func (f *Filter) leadsTo(p string) bool {
+	rel, ok := f.relative(p)
+	if !ok {
+		return false
+	}
 	for _, pattern := range f.includePatterns {
+		if isPathPattern(pattern) && leadsTo(pattern, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// relative returns p relative to the root with forward slashes, or false
+// when there is no root or p lies outside it.
+func (f *Filter) relative(p string) (string, bool) {
+	if f.root == "" {
+		return "", false
+	}
+	rel, err := filepath.Rel(f.root, p)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// MatchAny reports whether name matches any of the glob patterns.
+func MatchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
 		if matched, _ := filepath.Match(pattern, name); matched {
 			return true
 		}
@@ -54,14 +249,33 @@ func (f *Filter) shouldInclude(name string) bool {
 	return false
 }
 
-func (f *Filter) ShouldInclude(name string) bool {
-	return f.shouldInclude(name)
+// ShouldInclude reports whether path matches an include pattern. A bare
+// name works for every pattern except the root-anchored ones.
+func (f *Filter) ShouldInclude(path string) bool {
+	return f.shouldInclude(path)
 }
 
-func (f *Filter) ShouldExclude(name string) bool {
-	return f.shouldExclude(name)
+// ShouldExclude reports whether path matches an exclude pattern, with the
+// same rules as ShouldInclude.
+func (f *Filter) ShouldExclude(path string) bool {
+	return f.shouldExclude(path)
 }
 
 func (f *Filter) HasIncludePatterns() bool {
 	return len(f.includePatterns) > 0
 }
+
+// Narrows reports whether any filter picks entries out rather than hiding
+// a few: include patterns, the size range, --only, --owner, --group,
+// --mime or --perm. Directories without a match below them are then only
+// noise in a tree or a recursive listing.
+func (f *Filter) Narrows() bool {
+	return len(f.includePatterns) > 0 || f.minSize > 0 || f.maxSize > 0 || len(f.types) > 0 ||
+		len(f.owners) > 0 || len(f.groups) > 0 || len(f.mimes) > 0 || f.perm != nil
+}
+
+// SelectsDirs reports whether --only asks for directories, so they match
+// in their own right instead of only as the way to matching files.
+func (f *Filter) SelectsDirs() bool {
+	return f.types["dirs"]
+}
@@ -1,9 +1,14 @@
 package filter
 
 import (
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/ipanardian/lu-hut/internal/model"
+	"github.com/ipanardian/lu-hut/internal/perms"
 )
 
 func TestFileFilter(t *testing.T) {
@@ -79,4 +84,263 @@ func TestFileFilter(t *testing.T) {
 			t.Errorf("expected tambang.py, got %s", result[0].Name)
 		}
 	})
+
+	t.Run("size range", func(t *testing.T) {
+		sized := []model.FileEntry{
+			{Name: "small.txt", Size: 100},
+			{Name: "medium.bin", Size: 10 << 20},
+			{Name: "large.iso", Size: 2 << 30},
+			{Name: "dir", IsDir: true},
+		}
+		filter := NewFilter(nil, nil)
+		filter.SetSizeRange(1<<20, 1<<30)
+		result := filter.Apply(sized, false)
+
+		if len(result) != 2 || result[0].Name != "medium.bin" || result[1].Name != "dir" {
+			t.Errorf("expected medium.bin and dir, got %v", result)
+		}
+	})
+
+	t.Run("entry types", func(t *testing.T) {
+		typed := []model.FileEntry{
+			{Name: "notes.txt", Mode: 0o644},
+			{Name: "build.sh", Mode: 0o755},
+			{Name: "current", Mode: fs.ModeSymlink | 0o777},
+			{Name: "src", Mode: fs.ModeDir | 0o755, IsDir: true},
+		}
+		tests := []struct {
+			types    []string
+			expected []string
+		}{
+			{nil, []string{"notes.txt", "build.sh", "current", "src"}},
+			{[]string{"files"}, []string{"notes.txt", "build.sh"}},
+			{[]string{"exec"}, []string{"build.sh"}},
+			{[]string{"dirs", "links"}, []string{"current", "src"}},
+		}
+		for _, tt := range tests {
+			filter := NewFilter(nil, nil)
+			filter.SetTypes(tt.types)
+			var got []string
+			for _, file := range typed {
+				if filter.MatchesType(file) {
+					got = append(got, file.Name)
+				}
+			}
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("types %v: got %v, want %v", tt.types, got, tt.expected)
+			}
+		}
+	})
+
+	t.Run("owners", func(t *testing.T) {
+		owned := []model.FileEntry{
+			{Name: "a", Author: "alice", Group: "staff"},
+			{Name: "b", Author: "bob", Group: "docker"},
+			{Name: "c", Author: "alice", Group: "docker"},
+		}
+		tests := []struct {
+			users, groups []string
+			expected      []string
+		}{
+			{nil, nil, []string{"a", "b", "c"}},
+			{[]string{"alice"}, nil, []string{"a", "c"}},
+			{nil, []string{"docker"}, []string{"b", "c"}},
+			{[]string{"alice"}, []string{"docker"}, []string{"c"}},
+		}
+		for _, tt := range tests {
+			filter := NewFilter(nil, nil)
+			filter.SetOwners(tt.users, tt.groups)
+			var got []string
+			for _, file := range owned {
+				if filter.MatchesOwner(file) {
+					got = append(got, file.Name)
+				}
+			}
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("owners %v %v: got %v, want %v", tt.users, tt.groups, got, tt.expected)
+			}
+		}
+	})
+
+	t.Run("perm", func(t *testing.T) {
+		moded := []model.FileEntry{
+			{Name: "notes.txt", Mode: 0o644},
+			{Name: "shared.log", Mode: 0o666},
+			{Name: "current", Mode: fs.ModeSymlink | 0o777},
+			{Name: "tmp", Mode: fs.ModeDir | fs.ModeSticky | 0o777, IsDir: true},
+		}
+		m, err := perms.ParseMatch("-o+w")
+		if err != nil {
+			t.Fatal(err)
+		}
+		filter := NewFilter(nil, nil)
+		filter.SetPerm(&m)
+		var got []string
+		for _, file := range moded {
+			if filter.MatchesPerm(file) {
+				got = append(got, file.Name)
+			}
+		}
+		if want := []string{"shared.log", "tmp"}; !slices.Equal(got, want) {
+			t.Errorf("perm -o+w: got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("matches combines entry filters", func(t *testing.T) {
+		entries := []model.FileEntry{
+			{Name: "a.sh", Mode: 0o755, Author: "alice"},
+			{Name: "b.sh", Mode: 0o755, Author: "bob"},
+			{Name: "c.txt", Mode: 0o644, Author: "alice"},
+		}
+		filter := NewFilter(nil, nil)
+		filter.SetTypes([]string{"exec"})
+		filter.SetOwners([]string{"alice"}, nil)
+		var got []string
+		for _, file := range entries {
+			if filter.Matches(file) {
+				got = append(got, file.Name)
+			}
+		}
+		if want := []string{"a.sh"}; !slices.Equal(got, want) {
+			t.Errorf("exec owned by alice: got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("narrows", func(t *testing.T) {
+		filter := NewFilter(nil, []string{"*.log"})
+		if filter.Narrows() {
+			t.Error("exclude patterns alone narrow the listing")
+		}
+		filter.SetSizeRange(1<<20, 0)
+		if !filter.Narrows() {
+			t.Error("a minimum size does not narrow the listing")
+		}
+	})
+}
+
+func TestAnchoredPatterns(t *testing.T) {
+	root := filepath.Join("srv", "app")
+	files := []model.FileEntry{
+		{Name: "build", Path: filepath.Join(root, "build"), IsDir: true},
+		{Name: "build", Path: filepath.Join(root, "web", "build"), IsDir: true},
+		{Name: "gen", Path: filepath.Join(root, "src", "gen"), IsDir: true},
+		{Name: "gen", Path: filepath.Join(root, "gen"), IsDir: true},
+	}
+
+	tests := []struct {
+		name     string
+		exclude  []string
+		root     string
+		expected int
+	}{
+		{name: "plain pattern at any depth", exclude: []string{"build"}, root: root, expected: 2},
+		{name: "anchored to the root", exclude: []string{"/build"}, root: root, expected: 3},
+		{name: "anchored nested path", exclude: []string{"/src/gen"}, root: root, expected: 3},
+		{name: "trailing slash", exclude: []string{"/build/"}, root: root, expected: 3},
+		{name: "no root", exclude: []string{"/build"}, expected: 4},
+		{name: "doublestar at any depth", exclude: []string{"**/build"}, root: root, expected: 2},
+		{name: "doublestar below a directory", exclude: []string{"src/**"}, root: root, expected: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := NewFilter(nil, tt.exclude)
+			filter.SetRoot(tt.root)
+			if result := filter.Apply(files, false); len(result) != tt.expected {
+				t.Errorf("expected %d entries, got %d", tt.expected, len(result))
+			}
+		})
+	}
+}
+
+func TestDoublestar(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		matched bool
+	}{
+		{"src/**/*.go", "src/main.go", true},
+		{"src/**/*.go", "src/a/b/main.go", true},
+		{"src/**/*.go", "web/main.go", false},
+		{"src/**/*.go", "src/a/main.js", false},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "a/b/main.go", true},
+		{"src/*.go", "src/a/main.go", false},
+		{"/build/**", "build/out/app", true},
+	}
+	for _, tt := range tests {
+		if got := matchPath(tt.pattern, tt.path); got != tt.matched {
+			t.Errorf("matchPath(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.matched)
+		}
+	}
+
+	root := filepath.Join("srv", "app")
+	files := []model.FileEntry{
+		{Name: "src", Path: filepath.Join(root, "src"), IsDir: true},
+		{Name: "web", Path: filepath.Join(root, "web"), IsDir: true},
+		{Name: "main.go", Path: filepath.Join(root, "main.go")},
+		{Name: "lib.go", Path: filepath.Join(root, "src", "lib.go")},
+	}
+	filter := NewFilter([]string{"src/**/*.go"}, nil)
+	filter.SetRoot(root)
+	var got []string
+	for _, file := range filter.Apply(files, false) {
+		got = append(got, file.Name)
+	}
+	if !slices.Equal(got, []string{"src", "lib.go"}) {
+		t.Errorf("expected src to be kept for descending next to lib.go, got %v", got)
+	}
+}
+
+func TestIgnoreFile(t *testing.T) {
+	ig, err := ParseIgnore(strings.NewReader(`# build output
+/dist
+*.log
+!keep.log
+cache/
+docs/**/*.tmp
+\#notes
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path    string
+		dir     bool
+		ignored bool
+	}{
+		{path: "dist", dir: true, ignored: true},
+		{path: "web/dist", dir: true},
+		{path: "debug.log", ignored: true},
+		{path: "logs/debug.log", ignored: true},
+		{path: "keep.log"},
+		{path: "cache", dir: true, ignored: true},
+		{path: "cache"},
+		{path: "cache/keep.log", ignored: true},
+		{path: "src/cache", dir: true, ignored: true},
+		{path: "docs/a/b/draft.tmp", ignored: true},
+		{path: "draft.tmp"},
+		{path: "#notes", ignored: true},
+		{path: "main.go"},
+	}
+	for _, tt := range tests {
+		if got := ig.Match(tt.path, func() bool { return tt.dir }); got != tt.ignored {
+			t.Errorf("Match(%s, dir %v) = %v, want %v", tt.path, tt.dir, got, tt.ignored)
+		}
+	}
+
+	root := filepath.Join("srv", "app")
+	filter := NewFilter(nil, nil)
+	filter.SetRoot(root)
+	filter.SetIgnore(ig)
+	files := []model.FileEntry{
+		{Name: "dist", Path: filepath.Join(root, "dist"), IsDir: true},
+		{Name: "app.log", Path: filepath.Join(root, "app.log")},
+		{Name: "keep.log", Path: filepath.Join(root, "keep.log")},
+		{Name: "main.go", Path: filepath.Join(root, "main.go")},
+	}
+	if result := filter.Apply(files, false); len(result) != 2 {
+		t.Errorf("Apply() kept %d entries, want keep.log and main.go", len(result))
+	}
 }
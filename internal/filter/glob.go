@@ -0,0 +1,65 @@
+package filter
+
+import (
+	"path"
+	"strings"
+)
+
+// isPathPattern reports whether pattern is matched against the path
+// relative to the root rather than the base name: it is anchored with a
+// leading "/", spans directories, or uses "**".
+func isPathPattern(pattern string) bool {
+	return strings.Contains(pattern, "/") || strings.Contains(pattern, "**")
+}
+
+// matchPath matches a slash-separated path against pattern, where a "**"
+// segment stands for zero or more directories and other segments follow
+// path.Match. "src/**/*.go" matches src/main.go and src/a/b/c.go alike.
+func matchPath(pattern, name string) bool {
+	return matchSegments(splitPattern(pattern), strings.Split(name, "/"))
+}
+
+// leadsTo reports whether a directory at dir, relative to the root, could
+// contain a path matching pattern, so listings keep it while they descend.
+func leadsTo(pattern, dir string) bool {
+	segments := splitPattern(pattern)
+	for _, part := range strings.Split(dir, "/") {
+		if len(segments) == 0 {
+			return false
+		}
+		if segments[0] == "**" {
+			return true
+		}
+		if matched, _ := path.Match(segments[0], part); !matched {
+			return false
+		}
+		segments = segments[1:]
+	}
+	return len(segments) > 0
+}
+
+func splitPattern(pattern string) []string {
+	return strings.Split(strings.Trim(pattern, "/"), "/")
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(rest, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(pattern[0], name[0]); !matched {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
@@ -0,0 +1,107 @@
+package filter
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFile is the per-directory file whose patterns are always excluded
+// from listings of that directory, like a .gitignore that only lu reads.
+const IgnoreFile = ".luignore"
+
+// Ignore holds the rules of an ignore file, in gitignore syntax.
+type Ignore struct {
+	rules []ignoreRule
+}
+
+type ignoreRule struct {
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// LoadIgnore reads the IgnoreFile in dir. A directory without one returns
+// nil and no error.
+func LoadIgnore(dir string) (*Ignore, error) {
+	f, err := os.Open(filepath.Join(dir, IgnoreFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseIgnore(f)
+}
+
+// ParseIgnore reads gitignore-style rules: blank lines and # comments are
+// skipped, ! re-includes what an earlier rule excluded, a trailing / only
+// matches directories, and a pattern with a / anywhere else is anchored to
+// the directory the file is in, while one without matches at any depth.
+func ParseIgnore(r io.Reader) (*Ignore, error) {
+	ig := &Ignore{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var rule ignoreRule
+		if strings.HasPrefix(line, "!") {
+			rule.negate, line = true, line[1:]
+		} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly, line = true, strings.TrimRight(line, "/")
+		}
+		rule.anchored = strings.Contains(line, "/")
+		rule.pattern = strings.TrimPrefix(line, "/")
+		if rule.pattern != "" {
+			ig.rules = append(ig.rules, rule)
+		}
+	}
+	return ig, scanner.Err()
+}
+
+// Match reports whether rel, a slash-separated path relative to the
+// directory of the ignore file, is ignored. As in git, nothing inside an
+// ignored directory can be re-included. isDir is only called when a
+// directory-only rule needs the answer.
+func (ig *Ignore) Match(rel string, isDir func() bool) bool {
+	if ig == nil || len(ig.rules) == 0 {
+		return false
+	}
+	parts := strings.Split(rel, "/")
+	for i := 1; i < len(parts); i++ {
+		if ig.match(parts[:i], func() bool { return true }) {
+			return true
+		}
+	}
+	return ig.match(parts, isDir)
+}
+
+// match applies the rules to one path, the last matching rule winning.
+func (ig *Ignore) match(parts []string, isDir func() bool) bool {
+	ignored := false
+	for _, rule := range ig.rules {
+		if rule.negate != ignored {
+			continue
+		}
+		var matched bool
+		if rule.anchored {
+			matched = matchSegments(splitPattern(rule.pattern), parts)
+		} else {
+			matched, _ = path.Match(rule.pattern, parts[len(parts)-1])
+		}
+		if matched && (!rule.dirOnly || isDir()) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
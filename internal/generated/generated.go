@@ -0,0 +1,209 @@
+// Package generated recognizes files that are produced by tools rather than
+// written by hand: lockfiles, well-known generated name patterns, files
+// carrying a "Code generated ... DO NOT EDIT." or "@generated" header, and
+// paths marked linguist-generated in .gitattributes.
+package generated
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var lockfiles = map[string]bool{
+	"package-lock.json":   true,
+	"npm-shrinkwrap.json": true,
+	"yarn.lock":           true,
+	"pnpm-lock.yaml":      true,
+	"bun.lockb":           true,
+	"go.sum":              true,
+	"Cargo.lock":          true,
+	"Gemfile.lock":        true,
+	"composer.lock":       true,
+	"poetry.lock":         true,
+	"Pipfile.lock":        true,
+	"uv.lock":             true,
+	"flake.lock":          true,
+	"mix.lock":            true,
+	"Podfile.lock":        true,
+	"packages.lock.json":  true,
+}
+
+var namePatterns = []string{
+	"*.pb.go",
+	"*_generated.go",
+	"*_string.go",
+	"*.min.js",
+	"*.min.css",
+	"*.js.map",
+	"*.css.map",
+	"*_pb2.py",
+	"*_pb2_grpc.py",
+	"*.g.dart",
+	"*.freezed.dart",
+	"*.designer.cs",
+}
+
+// headerExtensions limits header sniffing to source files, so listing a
+// directory of photos never opens them.
+var headerExtensions = map[string]bool{
+	".go": true, ".js": true, ".mjs": true, ".ts": true, ".tsx": true, ".jsx": true,
+	".py": true, ".rb": true, ".php": true, ".java": true, ".kt": true, ".swift": true,
+	".rs": true, ".c": true, ".h": true, ".cc": true, ".cpp": true, ".hpp": true,
+	".cs": true, ".dart": true, ".scala": true, ".sql": true, ".proto": true,
+}
+
+// headerSize is how much of a file is searched for a generated marker.
+const headerSize = 1024
+
+var goHeader = regexp.MustCompile(`(?m)^// Code generated .* DO NOT EDIT\.$`)
+
+// ByName reports whether name alone identifies a generated file.
+func ByName(name string) bool {
+	if lockfiles[name] {
+		return true
+	}
+	for _, pattern := range namePatterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ByHeader reports whether the source file at path starts with a generated
+// code marker. Only files with a known source extension are opened.
+func ByHeader(path string) bool {
+	if !headerExtensions[strings.ToLower(filepath.Ext(path))] {
+		return false
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	buf := make([]byte, headerSize)
+	n, _ := f.Read(buf)
+	return hasMarker(buf[:n])
+}
+
+func hasMarker(header []byte) bool {
+	return goHeader.Match(header) || bytes.Contains(header, []byte("@generated"))
+}
+
+// Attributes holds linguist-generated patterns read from .gitattributes.
+type Attributes struct {
+	rules []attrRule
+}
+
+type attrRule struct {
+	dir       string
+	pattern   string
+	generated bool
+}
+
+// LoadAttributes reads the .gitattributes files from dir up to the root of
+// the enclosing git repository (or dir alone outside a repository).
+func LoadAttributes(dir string) *Attributes {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return &Attributes{}
+	}
+
+	var files []string
+	for current := dir; ; {
+		files = append(files, current)
+		if _, err := os.Stat(filepath.Join(current, ".git")); err == nil {
+			break
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			files = files[:1]
+			break
+		}
+		current = parent
+	}
+
+	a := &Attributes{}
+	// Outer files first so rules closer to the file override them.
+	for i := len(files) - 1; i >= 0; i-- {
+		a.read(files[i])
+	}
+	return a
+}
+
+func (a *Attributes) read(dir string) {
+	f, err := os.Open(filepath.Join(dir, ".gitattributes"))
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		for _, attr := range fields[1:] {
+			switch attr {
+			case "linguist-generated", "linguist-generated=true":
+				a.rules = append(a.rules, attrRule{dir: dir, pattern: fields[0], generated: true})
+			case "-linguist-generated", "linguist-generated=false":
+				a.rules = append(a.rules, attrRule{dir: dir, pattern: fields[0], generated: false})
+			}
+		}
+	}
+}
+
+// Detect reports whether the entry at path is generated. A .gitattributes
+// rule decides first, since it can also clear the mark; otherwise the name
+// is checked and, when sniff is set, the header. Callers only set sniff
+// for regular files the user asked lu to read.
+func (a *Attributes) Detect(path string, sniff bool) bool {
+	if generated, ok := a.Match(path); ok {
+		return generated
+	}
+	if ByName(filepath.Base(path)) {
+		return true
+	}
+	return sniff && ByHeader(path)
+}
+
+// Match reports whether .gitattributes marks path as generated. The last
+// matching rule wins, as in git. Patterns without a slash match the base
+// name anywhere below their file; others match the path relative to it.
+func (a *Attributes) Match(path string) (bool, bool) {
+	generated, matched := false, false
+	if a == nil {
+		return generated, matched
+	}
+	for _, rule := range a.rules {
+		rel, err := filepath.Rel(rule.dir, path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+
+		pattern := strings.TrimPrefix(rule.pattern, "/")
+		subject := rel
+		if !strings.Contains(rule.pattern, "/") {
+			subject = filepath.Base(path)
+		}
+		if ok, _ := filepath.Match(pattern, subject); ok || matchesDir(pattern, rel) {
+			generated, matched = rule.generated, true
+		}
+	}
+	return generated, matched
+}
+
+// matchesDir handles "dir/**" style patterns, which mark everything below
+// a directory.
+func matchesDir(pattern, rel string) bool {
+	prefix, ok := strings.CutSuffix(pattern, "/**")
+	return ok && strings.HasPrefix(rel, prefix+"/")
+}
@@ -0,0 +1,98 @@
+package generated
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestByName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected bool
+	}{
+		{"go.sum", true},
+		{"package-lock.json", true},
+		{"api.pb.go", true},
+		{"app.min.js", true},
+		{"model.g.dart", true},
+		{"main.go", false},
+		{"go.mod", false},
+		{"lock.json", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ByName(tt.name); got != tt.expected {
+				t.Errorf("ByName(%q) = %v, want %v", tt.name, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHasMarker(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected bool
+	}{
+		{"go", "// Code generated by stringer; DO NOT EDIT.\n\npackage x\n", true},
+		{"go after build tag", "//go:build linux\n\n// Code generated by mkerrors.sh; DO NOT EDIT.\n", true},
+		{"at generated", "/**\n * @generated SignedSource<<abc>>\n */\n", true},
+		{"mentioned in prose", "// This file is not Code generated by hand, DO NOT EDIT it lightly\n", false},
+		{"plain", "package main\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasMarker([]byte(tt.header)); got != tt.expected {
+				t.Errorf("hasMarker() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAttributes(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "web")
+	if err := os.MkdirAll(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(sub, "dist"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(path, content string) {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(root, ".gitattributes"), "*.snap linguist-generated\nweb/dist/** linguist-generated=true\n")
+	write(filepath.Join(sub, ".gitattributes"), "keep.snap -linguist-generated\n")
+
+	attrs := LoadAttributes(sub)
+	tests := []struct {
+		path      string
+		generated bool
+		matched   bool
+	}{
+		{filepath.Join(sub, "a.snap"), true, true},
+		{filepath.Join(sub, "keep.snap"), false, true},
+		{filepath.Join(sub, "dist", "bundle.js"), true, true},
+		{filepath.Join(sub, "index.js"), false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(filepath.Base(tt.path), func(t *testing.T) {
+			generated, matched := attrs.Match(tt.path)
+			if generated != tt.generated || matched != tt.matched {
+				t.Errorf("Match(%q) = %v, %v; want %v, %v", tt.path, generated, matched, tt.generated, tt.matched)
+			}
+		})
+	}
+
+	// An attribute can clear a mark that the name alone would set.
+	write(filepath.Join(sub, ".gitattributes"), "go.sum -linguist-generated\n")
+	if LoadAttributes(sub).Detect(filepath.Join(sub, "go.sum"), true) {
+		t.Errorf("Detect() ignored -linguist-generated")
+	}
+}
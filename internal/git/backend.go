@@ -0,0 +1,54 @@
+package git
+
+import (
+	"os/exec"
+	"time"
+)
+
+// StatusBackend reads the status of a whole worktree for a Repository.
+// lu has only ever asked the git command, which is fast even on monorepos
+// thanks to the index and fsmonitor; the interface keeps that choice in one
+// place and lets tests feed a Repository canned output.
+type StatusBackend interface {
+	// Name says which backend this is.
+	Name() string
+	// Status returns the status of the worktree at root in the format of
+	// git status --porcelain -z --branch, giving up after timeout unless
+	// it is zero. Failures are *CommandError.
+	Status(root string, timeout time.Duration) ([]byte, error)
+}
+
+// DefaultStatusBackend returns the git command when it is on the PATH,
+// and otherwise a backend that fails at once with ErrNotInstalled instead
+// of trying to start git for every directory.
+func DefaultStatusBackend() StatusBackend {
+	if _, err := exec.LookPath("git"); err != nil {
+		return missingGit{err: err}
+	}
+	return gitCommand{}
+}
+
+type gitCommand struct{}
+
+func (gitCommand) Name() string {
+	return "git status --porcelain"
+}
+
+func (gitCommand) Status(root string, timeout time.Duration) ([]byte, error) {
+	// -z keeps paths unquoted and puts the source of a rename or copy in
+	// its own record right after the destination. --branch adds a first
+	// record with the branch and its upstream for Summary.
+	return run(timeout, "-C", root, "status", "--porcelain", "-z", "--branch")
+}
+
+type missingGit struct {
+	err error
+}
+
+func (missingGit) Name() string {
+	return "none, git is not installed"
+}
+
+func (m missingGit) Status(root string, _ time.Duration) ([]byte, error) {
+	return nil, newCommandError([]string{"-C", root, "status"}, &exec.Error{Name: "git", Err: m.err})
+}
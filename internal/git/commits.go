@@ -0,0 +1,149 @@
+package git
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Commit is the last commit that touched a path, as GitHub's file listing
+// shows it next to every entry.
+type Commit struct {
+	Hash   string
+	Author string
+	Time   time.Time
+}
+
+// LastCommits returns the last commit touching each entry directly inside
+// dir, by name; for a subdirectory that is the last commit touching
+// anything in it. Only entries in HEAD are looked up, so untracked files
+// have none. The history is read newest first and only until every entry
+// has been seen, and each directory only once per run.
+//
+// A walk cut short by the timeout returns what it found together with an
+// error wrapping ErrTimeout. Other failures are *CommandError. Failures are
+// remembered like results, and after one timeout no further walks are
+// started, so -R on a huge history waits for the timeout once rather than
+// once per directory.
+func (g *Repository) LastCommits(dir string) (map[string]Commit, error) {
+	if commits, ok := g.commits[dir]; ok {
+		return commits, g.commitErrs[dir]
+	}
+	if g.commitTimeout != nil {
+		return nil, g.commitTimeout
+	}
+	commits, err := g.lastCommits(dir)
+	if g.commits == nil {
+		g.commits = make(map[string]map[string]Commit)
+		g.commitErrs = make(map[string]error)
+	}
+	g.commits[dir], g.commitErrs[dir] = commits, err
+	if errors.Is(err, ErrTimeout) {
+		g.commitTimeout = err
+	}
+	return commits, err
+}
+
+func (g *Repository) lastCommits(dir string) (map[string]Commit, error) {
+	commits := make(map[string]Commit)
+
+	tree, err := g.output("-C", dir, "ls-tree", "-z", "--name-only", "HEAD")
+	if err != nil {
+		if errors.Is(err, ErrTimeout) {
+			return nil, err
+		}
+		// A repository without commits yet has no history to show.
+		if _, headErr := g.output("-C", dir, "rev-parse", "--verify", "-q", "HEAD"); headErr != nil && !errors.Is(headErr, ErrTimeout) {
+			return commits, nil
+		}
+		return nil, err
+	}
+	wanted := make(map[string]bool)
+	for _, name := range strings.Split(string(tree), "\x00") {
+		if name != "" {
+			wanted[name] = true
+		}
+	}
+	if len(wanted) == 0 {
+		return commits, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if g.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, g.timeout)
+		defer cancel()
+	}
+
+	// \x1e starts each commit and \x1f separates its fields; -z ends the
+	// header and every path with NUL, and --relative makes the paths
+	// relative to dir.
+	args := []string{"-C", dir, "log", "--relative", "--name-only", "-z", "--format=%x1e%h%x1f%an%x1f%at", "--", "."}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, newCommandError(args, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, newCommandError(args, err)
+	}
+
+	readErr := parseLog(stdout, func(commit Commit, path string) bool {
+		name, _, _ := strings.Cut(path, "/")
+		if wanted[name] {
+			commits[name] = commit
+			delete(wanted, name)
+		}
+		return len(wanted) > 0
+	})
+	// Stopping early leaves git with history nobody reads; cancelling
+	// kills it, which is not a failure.
+	done := len(wanted) == 0
+	cancel()
+	waitErr := cmd.Wait()
+
+	switch {
+	case ctx.Err() == context.DeadlineExceeded && !done:
+		return commits, newCommandError(args, fmt.Errorf("%w after %s", ErrTimeout, g.timeout))
+	case done:
+		return commits, nil
+	case waitErr != nil:
+		return commits, newCommandError(args, waitErr)
+	}
+	return commits, readErr
+}
+
+// parseLog reads the output of git log --name-only -z with the format
+// lastCommits uses and calls visit for every path of every commit, newest
+// first, until it returns false.
+func parseLog(r io.Reader, visit func(commit Commit, path string) bool) error {
+	reader := bufio.NewReader(r)
+	var commit Commit
+	for {
+		record, err := reader.ReadString('\x00')
+		record = strings.TrimPrefix(strings.TrimSuffix(record, "\x00"), "\n")
+		if header, ok := strings.CutPrefix(record, "\x1e"); ok {
+			fields := strings.Split(header, "\x1f")
+			if len(fields) == 3 {
+				seconds, _ := strconv.ParseInt(fields[2], 10, 64)
+				commit = Commit{Hash: fields[0], Author: fields[1], Time: time.Unix(seconds, 0)}
+			}
+		} else if record != "" && commit.Hash != "" {
+			if !visit(commit, record) {
+				return nil
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
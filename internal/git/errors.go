@@ -0,0 +1,66 @@
+package git
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+var (
+	// ErrNotRepository is returned by NewRepository for a path outside any
+	// git worktree.
+	ErrNotRepository = errors.New("not a git repository")
+	// ErrTimeout means git did not finish within the repository's timeout.
+	ErrTimeout = errors.New("timed out")
+	// ErrNotInstalled means there is no git executable on the PATH.
+	ErrNotInstalled = errors.New("git is not installed")
+	// ErrIndexLocked means another git process holds .git/index.lock.
+	ErrIndexLocked = errors.New("index is locked by another git process")
+	// ErrPermission means git could not read part of the repository.
+	ErrPermission = errors.New("permission denied")
+)
+
+// CommandError is a git command that failed. It matches ErrTimeout,
+// ErrNotInstalled, ErrIndexLocked and ErrPermission with errors.Is when
+// that is why it failed.
+type CommandError struct {
+	Args []string
+	// Stderr is the first line of git's complaint, without its "fatal:"
+	// or "error:" prefix.
+	Stderr string
+	Err    error
+}
+
+func newCommandError(args []string, err error) *CommandError {
+	e := &CommandError{Args: args, Err: err}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		line, _, _ := strings.Cut(strings.TrimSpace(string(exitErr.Stderr)), "\n")
+		line = strings.TrimPrefix(line, "fatal: ")
+		e.Stderr = strings.TrimPrefix(line, "error: ")
+	}
+	return e
+}
+
+func (e *CommandError) Error() string {
+	if e.Stderr != "" {
+		return e.Stderr
+	}
+	return e.Err.Error()
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+func (e *CommandError) Is(target error) bool {
+	switch target {
+	case ErrNotInstalled:
+		return errors.Is(e.Err, exec.ErrNotFound)
+	case ErrIndexLocked:
+		return strings.Contains(e.Stderr, "index.lock")
+	case ErrPermission:
+		return strings.Contains(strings.ToLower(e.Stderr), "permission denied")
+	}
+	return false
+}
@@ -2,17 +2,39 @@
 package git
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 )
 
 type Repository struct {
 	repoRoot     string
 	statusCache  map[string]string
+	origins      map[string]string
 	statusLoaded bool
+	statusErr    error
+	summary      Summary
+	commits      map[string]map[string]Commit
+	timeout      time.Duration
+	backend      StatusBackend
+	// ignored holds the paths git ignores as ls-files reports them, with
+	// a trailing slash for whole directories.
+	ignored       map[string]bool
+	ignoredLoaded bool
+	ignoredErr    error
+	// commitErrs holds why LastCommits failed for a directory, and
+	// commitTimeout the first walk that ran out of time.
+	commitErrs    map[string]error
+	commitTimeout error
+	// dirChanges counts the statuses below each directory, keyed like
+	// statusCache, with "." for the root.
+	dirChanges map[string]map[string]int
 }
 
 func NewRepository(path string) (*Repository, error) {
@@ -23,22 +45,92 @@ func NewRepository(path string) (*Repository, error) {
 	return &Repository{
 		repoRoot:    root,
 		statusCache: make(map[string]string),
+		origins:     make(map[string]string),
 	}, nil
 }
 
+// SetTimeout bounds how long each git command may run. Zero waits for as
+// long as git takes.
+func (g *Repository) SetTimeout(timeout time.Duration) {
+	g.timeout = timeout
+}
+
+// SetStatusBackend replaces the backend Status and the lookups read the
+// worktree status with. It must be called before the first of them.
+func (g *Repository) SetStatusBackend(backend StatusBackend) {
+	g.backend = backend
+}
+
+// StatusBackend returns the backend the worktree status is read with,
+// DefaultStatusBackend unless SetStatusBackend chose another.
+func (g *Repository) StatusBackend() StatusBackend {
+	if g.backend == nil {
+		g.backend = DefaultStatusBackend()
+	}
+	return g.backend
+}
+
+// output runs git with args and returns what it printed, giving up once
+// the repository's timeout has passed. Failures are *CommandError.
+func (g *Repository) output(args ...string) ([]byte, error) {
+	return run(g.timeout, args...)
+}
+
+// run runs git with args, giving up after timeout unless it is zero.
+func run(timeout time.Duration, args ...string) ([]byte, error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	output, err := exec.CommandContext(ctx, "git", args...).Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, newCommandError(args, fmt.Errorf("%w after %s", ErrTimeout, timeout))
+	}
+	if err != nil {
+		return nil, newCommandError(args, err)
+	}
+	return output, nil
+}
+
+// loadAllStatus reads the status once. A failure is remembered rather than
+// retried, since asking again for every file would only multiply the wait
+// or the noise.
 func (g *Repository) loadAllStatus() error {
 	if g.statusLoaded {
-		return nil
+		return g.statusErr
 	}
+	g.statusLoaded = true
 
-	cmd := exec.Command("git", "-C", g.repoRoot, "status", "--porcelain")
-	output, err := cmd.Output()
+	output, err := g.StatusBackend().Status(g.repoRoot, g.timeout)
 	if err != nil {
+		g.statusErr = err
 		return err
 	}
 
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
+	g.parseStatus(string(output))
+	return nil
+}
+
+// Status loads the status of the whole worktree and reports why it could
+// not be read, as a *CommandError. The lookups such as GetStatus return
+// empty results after a failure, so callers that show them should check
+// Status first and say why the column is blank.
+func (g *Repository) Status() error {
+	return g.loadAllStatus()
+}
+
+// parseStatus fills the status and origin caches and the summary from the
+// output of git status --porcelain -z, with or without --branch.
+func (g *Repository) parseStatus(output string) {
+	records := strings.Split(output, "\x00")
+	for i := 0; i < len(records); i++ {
+		line := records[i]
+		if header, ok := strings.CutPrefix(line, "## "); ok && i == 0 {
+			g.parseBranch(header)
+			continue
+		}
 		if len(line) < 4 {
 			continue
 		}
@@ -46,75 +138,284 @@ func (g *Repository) loadAllStatus() error {
 		staging := line[0]
 		worktree := line[1]
 		filePath := line[3:]
+		g.count(staging, worktree)
 
-		if staging == 'R' || staging == 'C' {
-			if idx := strings.Index(filePath, " -> "); idx != -1 {
-				filePath = filePath[idx+4:]
+		if staging == 'R' || staging == 'C' || worktree == 'R' || worktree == 'C' {
+			if i+1 < len(records) {
+				i++
+				g.origins[filePath] = records[i]
 			}
 		}
 
+		// The worktree side wins, and letters such as T for a type change
+		// are kept as git reports them.
 		var status string
-		if worktree != ' ' && worktree != '?' {
-			switch worktree {
-			case 'M':
-				status = "M"
-			case 'D':
-				status = "D"
-			case 'A':
-				status = "A"
-			case 'R':
-				status = "R"
-			case 'C':
-				status = "C"
-			}
-		} else if staging != ' ' {
-			switch staging {
-			case 'A':
-				status = "A"
-			case 'M':
-				status = "M"
-			case 'D':
-				status = "D"
-			case 'R':
-				status = "R"
-			case 'C':
-				status = "C"
-			}
-		} else if worktree == '?' {
+		if unmerged(staging, worktree) {
+			status = "U"
+		} else if staging == '?' {
 			status = "?"
+		} else if worktree != ' ' {
+			status = string(worktree)
+		} else if staging != ' ' {
+			status = string(staging)
 		}
 
 		if status != "" {
 			g.statusCache[filePath] = status
+			g.countDirChange(filePath, status)
+		}
+	}
+}
+
+// countDirChange adds status to the counts of every directory above the
+// changed path, up to the root.
+func (g *Repository) countDirChange(changed, status string) {
+	if g.dirChanges == nil {
+		g.dirChanges = make(map[string]map[string]int)
+	}
+	dir := strings.TrimSuffix(changed, "/")
+	for dir != "." {
+		dir = path.Dir(dir)
+		counts := g.dirChanges[dir]
+		if counts == nil {
+			counts = make(map[string]int)
+			g.dirChanges[dir] = counts
 		}
+		counts[status]++
 	}
+}
 
-	g.statusLoaded = true
-	return nil
+// statusReady loads the status for lookups that have no way to report an
+// error; Status tells why they come back empty.
+func (g *Repository) statusReady() bool {
+	return g.loadAllStatus() == nil
 }
 
-func (g *Repository) GetStatus(filePath string) string {
+// unmerged reports whether a porcelain XY pair is one of the states git
+// uses for paths with unresolved merge conflicts.
+func unmerged(staging, worktree byte) bool {
+	return staging == 'U' || worktree == 'U' ||
+		(staging == 'A' && worktree == 'A') ||
+		(staging == 'D' && worktree == 'D')
+}
+
+// HasConflicts reports whether the file at filePath has unresolved merge
+// conflicts or, for a directory, whether anything inside it does.
+func (g *Repository) HasConflicts(filePath string) bool {
+	return g.contains(filePath, func(status string) bool { return status == "U" })
+}
+
+// HasChanges reports whether the file at filePath is modified, staged or
+// untracked or, for a directory, whether anything inside it is. Files in
+// an untracked directory count as untracked themselves.
+func (g *Repository) HasChanges(filePath string) bool {
+	return g.contains(filePath, func(string) bool { return true })
+}
+
+// contains reports whether filePath, or anything below it, has a status
+// that match accepts. It looks up the path itself, the counts kept for
+// what lies below it and the untracked directories above it, so a check
+// costs the same however many paths are changed.
+func (g *Repository) contains(filePath string, match func(status string) bool) bool {
+	if g == nil {
+		return false
+	}
+	if !g.statusReady() {
+		return false
+	}
+	relPath, ok := g.relative(filePath)
+	if !ok {
+		return false
+	}
+	if status, ok := g.statusCache[relPath]; ok && match(status) {
+		return true
+	}
+	for status, n := range g.dirChanges[relPath] {
+		if n > 0 && match(status) {
+			return true
+		}
+	}
+	for dir := relPath; dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if status, ok := g.statusCache[dir+"/"]; ok && match(status) {
+			return true
+		}
+	}
+	return false
+}
+
+// Origin returns where a renamed or copied file came from, or "" for any
+// other file. The source is given relative to the file's own directory
+// when it stayed there, and relative to the repository root otherwise.
+func (g *Repository) Origin(filePath string) string {
+	if !g.statusReady() {
+		return ""
+	}
+	relPath, ok := g.relative(filePath)
+	if !ok {
+		return ""
+	}
+	origin, ok := g.origins[relPath]
+	if !ok {
+		return ""
+	}
+	if path.Dir(origin) == path.Dir(relPath) {
+		return path.Base(origin)
+	}
+	return origin
+}
+
+// Change is one path git status reports, relative to the repository root.
+type Change struct {
+	Path   string
+	Status string
+	Origin string
+}
+
+// Root returns the top-level directory of the worktree.
+func (g *Repository) Root() string {
+	return g.repoRoot
+}
+
+// Changes returns every changed and untracked path in the worktree, sorted
+// by path. Untracked directories are reported once, as git does.
+func (g *Repository) Changes() ([]Change, error) {
 	if err := g.loadAllStatus(); err != nil {
+		return nil, err
+	}
+	changes := make([]Change, 0, len(g.statusCache))
+	for path, status := range g.statusCache {
+		changes = append(changes, Change{Path: path, Status: status, Origin: g.origins[path]})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// DirtyCount returns the number of changed and untracked paths in the
+// directory at dir and below it. Inside an untracked directory, which git
+// reports as one path, that is 1. Failures are *CommandError, so a count
+// of 0 always means nothing under dir changed.
+func (g *Repository) DirtyCount(dir string) (int, error) {
+	if err := g.loadAllStatus(); err != nil {
+		return 0, err
+	}
+	if g.GetStatus(dir) != "" {
+		return 1, nil
+	}
+	relPath, ok := g.relative(dir)
+	if !ok {
+		return 0, nil
+	}
+	count := 0
+	for _, n := range g.dirChanges[relPath] {
+		count += n
+	}
+	return count, nil
+}
+
+// GetStatus returns the status letter of the entry at filePath. Git
+// reports an untracked directory once, as "dir/", so the directory and
+// everything inside it are untracked too.
+func (g *Repository) GetStatus(filePath string) string {
+	if !g.statusReady() {
+		return ""
+	}
+
+	relPath, ok := g.relative(filePath)
+	if !ok {
 		return ""
 	}
 
+	if status, ok := g.statusCache[relPath]; ok {
+		return status
+	}
+	for dir := relPath; dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if status, ok := g.statusCache[dir+"/"]; ok {
+			return status
+		}
+	}
+
+	return ""
+}
+
+// DirChanges counts the changed and untracked paths below the directory at
+// filePath by status letter, so a directory row can tell what is pending
+// inside it. An untracked directory further down counts once. The counts
+// are worked out when the status is loaded and shared between callers,
+// which must not modify them.
+func (g *Repository) DirChanges(filePath string) map[string]int {
+	if !g.statusReady() {
+		return nil
+	}
+	relPath, ok := g.relative(filePath)
+	if !ok {
+		return nil
+	}
+	return g.dirChanges[relPath]
+}
+
+// relative turns filePath into the slash-separated, root-relative form git
+// reports paths in.
+func (g *Repository) relative(filePath string) (string, bool) {
 	absPath, err := filepath.Abs(filePath)
 	if err != nil {
-		return ""
+		return "", false
 	}
 
 	relPath, err := filepath.Rel(g.repoRoot, absPath)
 	if err != nil {
-		return ""
+		return "", false
 	}
 
-	relPath = filepath.ToSlash(relPath)
+	return filepath.ToSlash(relPath), true
+}
 
-	if status, ok := g.statusCache[relPath]; ok {
-		return status
+// Ignored loads the paths git ignores in the whole worktree, once per run
+// like the status, and reports why it could not. It asks git itself, so
+// .gitignore files, $GIT_DIR/info/exclude and the user's core.excludesFile
+// all apply exactly as they do for git. Failures are *CommandError.
+func (g *Repository) Ignored() error {
+	if g.ignoredLoaded {
+		return g.ignoredErr
 	}
+	g.ignoredLoaded = true
 
-	return ""
+	// --directory reports a directory holding nothing but ignored files
+	// once, as "dir/", so node_modules costs one line however big it is.
+	output, err := g.output("-C", g.repoRoot, "ls-files", "-z", "--others", "--ignored", "--exclude-standard", "--directory")
+	if err != nil {
+		g.ignoredErr = err
+		return err
+	}
+	g.ignored = make(map[string]bool)
+	for _, path := range strings.Split(string(output), "\x00") {
+		if path != "" {
+			g.ignored[path] = true
+		}
+	}
+	return nil
+}
+
+// IsIgnored reports whether git ignores the entry at filePath, either by
+// itself or because it is inside an ignored directory. Tracked files are
+// never ignored, even when a pattern matches them. It is false until
+// Ignored has loaded the paths.
+func (g *Repository) IsIgnored(filePath string) bool {
+	if g.ignored == nil {
+		return false
+	}
+	relPath, ok := g.relative(filePath)
+	if !ok || relPath == "." {
+		return false
+	}
+	if g.ignored[relPath] {
+		return true
+	}
+	for dir := relPath; dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if g.ignored[dir+"/"] {
+			return true
+		}
+	}
+	return false
 }
 
 func findGitRoot(start string) (string, error) {
@@ -129,7 +430,7 @@ func findGitRoot(start string) (string, error) {
 		}
 		parent := filepath.Dir(dir)
 		if parent == dir {
-			return "", fmt.Errorf("not a git repository")
+			return "", ErrNotRepository
 		}
 		dir = parent
 	}
@@ -0,0 +1,334 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseStatus(t *testing.T) {
+	output := " M main.go\x00?? notes/\x00R  docs/new.md\x00src/old.md\x00UU conflict.txt\x00A  added.go\x00 D gone.txt\x00"
+
+	g := &Repository{statusCache: make(map[string]string), origins: make(map[string]string)}
+	g.parseStatus(output)
+
+	expected := map[string]string{
+		"main.go":      "M",
+		"notes/":       "?",
+		"docs/new.md":  "R",
+		"conflict.txt": "U",
+		"added.go":     "A",
+		"gone.txt":     "D",
+	}
+	if !reflect.DeepEqual(g.statusCache, expected) {
+		t.Errorf("statusCache = %v, want %v", g.statusCache, expected)
+	}
+	if origin := g.origins["docs/new.md"]; origin != "src/old.md" {
+		t.Errorf("origins[docs/new.md] = %q, want src/old.md", origin)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	tests := []struct {
+		output string
+		want   Summary
+	}{
+		{"## main...origin/main [ahead 1, behind 2]\x00 M main.go\x00MM both.go\x00?? notes/\x00R  new.md\x00old.md\x00UU conflict.txt\x00",
+			Summary{Branch: "main", Upstream: "origin/main", Ahead: 1, Behind: 2, Staged: 2, Modified: 2, Untracked: 1, Conflicted: 1}},
+		{"## feature...origin/feature [gone]\x00", Summary{Branch: "feature", Upstream: "origin/feature", Gone: true}},
+		{"## HEAD (no branch)\x00A  added.go\x00", Summary{Staged: 1}},
+		{"## No commits yet on main\x00?? a\x00", Summary{Branch: "main", Untracked: 1}},
+		{"## main\x00", Summary{Branch: "main"}},
+	}
+	for _, tt := range tests {
+		g := &Repository{statusCache: make(map[string]string), origins: make(map[string]string)}
+		g.parseStatus(tt.output)
+		if g.summary != tt.want {
+			t.Errorf("parseStatus(%q) summary = %+v, want %+v", tt.output, g.summary, tt.want)
+		}
+	}
+}
+
+func TestHasChanges(t *testing.T) {
+	root := t.TempDir()
+	g := &Repository{repoRoot: root, statusCache: make(map[string]string), origins: make(map[string]string), statusLoaded: true}
+	g.parseStatus(" M src/main.go\x00?? notes/\x00UU conflict.txt\x00")
+
+	tests := []struct {
+		path      string
+		changes   bool
+		conflicts bool
+	}{
+		{path: "src", changes: true},
+		{path: "src/main.go", changes: true},
+		{path: "src/clean.go"},
+		{path: "notes", changes: true},
+		{path: "notes/todo.md", changes: true},
+		{path: "docs"},
+		{path: "conflict.txt", changes: true, conflicts: true},
+		{path: "notes/deep/todo.md", changes: true},
+		{path: "sr"},
+		{path: ".", changes: true, conflicts: true},
+	}
+	for _, tt := range tests {
+		full := filepath.Join(root, filepath.FromSlash(tt.path))
+		if got := g.HasChanges(full); got != tt.changes {
+			t.Errorf("HasChanges(%s) = %v, want %v", tt.path, got, tt.changes)
+		}
+		if got := g.HasConflicts(full); got != tt.conflicts {
+			t.Errorf("HasConflicts(%s) = %v, want %v", tt.path, got, tt.conflicts)
+		}
+	}
+}
+
+func TestCommandErrorKinds(t *testing.T) {
+	tests := []struct {
+		err  *CommandError
+		want error
+	}{
+		{err: &CommandError{Stderr: "Unable to create '/repo/.git/index.lock': File exists.", Err: errors.New("exit status 128")}, want: ErrIndexLocked},
+		{err: &CommandError{Stderr: "could not open directory 'secret/': Permission denied", Err: errors.New("exit status 128")}, want: ErrPermission},
+		{err: &CommandError{Err: &exec.Error{Name: "git", Err: exec.ErrNotFound}}, want: ErrNotInstalled},
+		{err: &CommandError{Err: ErrTimeout}, want: ErrTimeout},
+	}
+	kinds := []error{ErrIndexLocked, ErrPermission, ErrNotInstalled, ErrTimeout}
+	for _, tt := range tests {
+		for _, kind := range kinds {
+			if got := errors.Is(tt.err, kind); got != (kind == tt.want) {
+				t.Errorf("errors.Is(%q, %v) = %v", tt.err, kind, got)
+			}
+		}
+	}
+}
+
+func TestStatusFailure(t *testing.T) {
+	if _, err := NewRepository(t.TempDir()); !errors.Is(err, ErrNotRepository) {
+		t.Errorf("NewRepository(temp dir) error = %v, want ErrNotRepository", err)
+	}
+
+	root := filepath.Join(t.TempDir(), "gone")
+	g := &Repository{repoRoot: root, statusCache: make(map[string]string), origins: make(map[string]string)}
+	err := g.Status()
+	var cmdErr *CommandError
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("Status() error = %v, want a *CommandError", err)
+	}
+	if again := g.Status(); again != err {
+		t.Errorf("second Status() = %v, want the first failure %v remembered", again, err)
+	}
+	if status := g.GetStatus(filepath.Join(root, "file")); status != "" {
+		t.Errorf("GetStatus() after a failure = %q, want blank", status)
+	}
+}
+
+func TestParseLog(t *testing.T) {
+	output := "\x1ea1b2c3d\x1falice\x1f1700000000\x00\nmain.go\x00docs/guide.md\x00\x1e0f9e8d7\x1fbob\x1f1600000000\x00\nREADME.md\x00main.go\x00"
+
+	var seen []string
+	err := parseLog(strings.NewReader(output), func(commit Commit, path string) bool {
+		seen = append(seen, commit.Hash+" "+commit.Author+" "+path)
+		return path != "README.md"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"a1b2c3d alice main.go", "a1b2c3d alice docs/guide.md", "0f9e8d7 bob README.md"}
+	if !reflect.DeepEqual(seen, want) {
+		t.Errorf("parseLog visited %q, want %q", seen, want)
+	}
+}
+
+func TestLastCommits(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	root := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", root, "-c", "user.name=alice", "-c", "user.email=a@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name string) {
+		t.Helper()
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q")
+	write("old.txt")
+	write("src/a.go")
+	run("add", ".")
+	run("commit", "-q", "-m", "first")
+	write("src/b.go")
+	run("add", ".")
+	run("commit", "-q", "-m", "second")
+	write("untracked.txt")
+
+	g, err := NewRepository(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commits, err := g.LastCommits(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 2 || commits["src"].Hash == "" || commits["src"].Hash == commits["old.txt"].Hash || commits["old.txt"].Author != "alice" {
+		t.Errorf("LastCommits() = %+v", commits)
+	}
+	if _, ok := commits["untracked.txt"]; ok {
+		t.Error("LastCommits() reported a commit for an untracked file")
+	}
+	// After one walk runs out of time, later directories do not wait again.
+	slow, err := NewRepository(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slow.SetTimeout(time.Nanosecond)
+	_, err = slow.LastCommits(root)
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("LastCommits() with a tiny timeout error = %v, want ErrTimeout", err)
+	}
+	if _, again := slow.LastCommits(filepath.Join(root, "src")); again != err {
+		t.Errorf("LastCommits(src) after a timeout = %v, want the first timeout %v", again, err)
+	}
+}
+
+func TestIgnored(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	root := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", root, "-c", "user.name=alice", "-c", "user.email=a@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	for name, content := range map[string]string{".gitignore": "build\n*.log\n", "main.go": "", "debug.log": "", "build/out": "", "build/keep.txt": "", "build/sub/x": ""} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run("init", "-q")
+	run("add", ".gitignore", "main.go")
+	run("add", "-f", "build/keep.txt")
+
+	g, err := NewRepository(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.IsIgnored(filepath.Join(root, "debug.log")) {
+		t.Error("IsIgnored() before Ignored() = true, want false")
+	}
+	if err := g.Ignored(); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]bool{
+		"main.go":        false,
+		"debug.log":      true,
+		"build":          false,
+		"build/keep.txt": false,
+		"build/out":      true,
+		"build/sub":      true,
+		"build/sub/x":    true,
+	} {
+		if got := g.IsIgnored(filepath.Join(root, filepath.FromSlash(name))); got != want {
+			t.Errorf("IsIgnored(%s) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+// cannedStatus is a StatusBackend that returns fixed output.
+type cannedStatus string
+
+func (cannedStatus) Name() string { return "canned" }
+
+func (c cannedStatus) Status(string, time.Duration) ([]byte, error) {
+	return []byte(c), nil
+}
+
+func TestStatusBackend(t *testing.T) {
+	root := t.TempDir()
+	g := &Repository{repoRoot: root, statusCache: make(map[string]string), origins: make(map[string]string)}
+	g.SetStatusBackend(cannedStatus("## main\x00 M main.go\x00?? notes/\x00"))
+	if err := g.Status(); err != nil {
+		t.Fatal(err)
+	}
+	if status := g.GetStatus(filepath.Join(root, "main.go")); status != "M" {
+		t.Errorf("GetStatus(main.go) = %q, want M", status)
+	}
+	if summary, _ := g.Summary(); summary.Branch != "main" || summary.Modified != 1 || summary.Untracked != 1 {
+		t.Errorf("Summary() = %+v", summary)
+	}
+
+	t.Setenv("PATH", "")
+	backend := DefaultStatusBackend()
+	if _, err := backend.Status(root, 0); !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("%s: Status() error = %v, want ErrNotInstalled", backend.Name(), err)
+	}
+}
+
+func TestDirChanges(t *testing.T) {
+	root := t.TempDir()
+	g := &Repository{repoRoot: root, statusCache: make(map[string]string), origins: make(map[string]string)}
+	g.SetStatusBackend(cannedStatus(" M src/a.go\x00 M src/pkg/b.go\x00D  src/c.go\x00?? src/new.go\x00 T src/link\x00?? notes/\x00"))
+
+	src := filepath.Join(root, "src")
+	if got, want := g.DirChanges(src), map[string]int{"M": 2, "D": 1, "?": 1, "T": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DirChanges(src) = %v, want %v", got, want)
+	}
+	if got, want := g.DirChanges(filepath.Join(src, "pkg")), map[string]int{"M": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DirChanges(src/pkg) = %v, want %v", got, want)
+	}
+	if got, want := g.DirChanges(root), map[string]int{"M": 2, "D": 1, "?": 2, "T": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DirChanges(root) = %v, want %v", got, want)
+	}
+	if got := g.DirChanges(filepath.Join(root, "docs")); got != nil {
+		t.Errorf("DirChanges(docs) = %v, want nil", got)
+	}
+	for _, name := range []string{"notes", "notes/todo.md", "notes/old/2024.md"} {
+		if status := g.GetStatus(filepath.Join(root, filepath.FromSlash(name))); status != "?" {
+			t.Errorf("GetStatus(%s) = %q, want ? from the untracked directory", name, status)
+		}
+	}
+}
+
+func TestDirtyCount(t *testing.T) {
+	root := t.TempDir()
+	g := &Repository{repoRoot: root, statusCache: make(map[string]string), origins: make(map[string]string)}
+	g.SetStatusBackend(cannedStatus(" M src/a.go\x00 M src/pkg/b.go\x00?? notes/\x00 M README.md\x00"))
+
+	tests := []struct {
+		dir  string
+		want int
+	}{
+		{".", 4},
+		{"src", 2},
+		{"src/pkg", 1},
+		{"docs", 0},
+		{"notes/old", 1},
+	}
+	for _, tt := range tests {
+		got, err := g.DirtyCount(filepath.Join(root, filepath.FromSlash(tt.dir)))
+		if err != nil || got != tt.want {
+			t.Errorf("DirtyCount(%s) = %d, %v; want %d", tt.dir, got, err, tt.want)
+		}
+	}
+}
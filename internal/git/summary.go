@@ -0,0 +1,86 @@
+package git
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Summary is the state of the worktree as a whole, what shell prompts such
+// as starship show next to the directory.
+type Summary struct {
+	// Branch is the checked-out branch, empty when HEAD is detached.
+	Branch string
+	// Upstream is the branch Branch tracks, if any. Gone is set when it
+	// was deleted on the remote.
+	Upstream string
+	Gone     bool
+	// Ahead and Behind count the commits Branch and Upstream do not share.
+	Ahead, Behind int
+	// Staged, Modified, Untracked and Conflicted count paths by state. A
+	// path changed both in the index and the worktree counts in both.
+	Staged, Modified, Untracked, Conflicted int
+}
+
+// Clean reports whether nothing in the worktree is changed or untracked.
+func (s Summary) Clean() bool {
+	return s.Staged == 0 && s.Modified == 0 && s.Untracked == 0 && s.Conflicted == 0
+}
+
+// Summary returns the branch and change counts of the worktree, read with
+// the same git status as the per-file lookups. Failures are *CommandError.
+func (g *Repository) Summary() (Summary, error) {
+	if err := g.loadAllStatus(); err != nil {
+		return Summary{}, err
+	}
+	return g.summary, nil
+}
+
+// parseBranch reads the "## " header git status --branch prints, e.g.
+// "main...origin/main [ahead 1, behind 2]", "HEAD (no branch)" or
+// "No commits yet on main".
+func (g *Repository) parseBranch(header string) {
+	s := &g.summary
+	header, tracking, _ := strings.Cut(header, " [")
+	switch {
+	case strings.HasPrefix(header, "HEAD (no branch)"):
+		return
+	case strings.HasPrefix(header, "No commits yet on "):
+		s.Branch = strings.TrimPrefix(header, "No commits yet on ")
+		return
+	case strings.HasPrefix(header, "Initial commit on "):
+		s.Branch = strings.TrimPrefix(header, "Initial commit on ")
+		return
+	}
+	s.Branch, s.Upstream, _ = strings.Cut(header, "...")
+
+	for _, part := range strings.Split(strings.TrimSuffix(tracking, "]"), ", ") {
+		kind, count, _ := strings.Cut(part, " ")
+		n, _ := strconv.Atoi(count)
+		switch kind {
+		case "ahead":
+			s.Ahead = n
+		case "behind":
+			s.Behind = n
+		case "gone":
+			s.Gone = true
+		}
+	}
+}
+
+// count adds one porcelain XY pair to the summary.
+func (g *Repository) count(staging, worktree byte) {
+	s := &g.summary
+	switch {
+	case unmerged(staging, worktree):
+		s.Conflicted++
+	case staging == '?':
+		s.Untracked++
+	default:
+		if staging != ' ' {
+			s.Staged++
+		}
+		if worktree != ' ' {
+			s.Modified++
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Package hook runs user supplied commands against listed entries.
+package hook
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const Placeholder = "{}"
+
+var destructiveCommands = map[string]bool{
+	"rm":       true,
+	"rmdir":    true,
+	"unlink":   true,
+	"shred":    true,
+	"mv":       true,
+	"truncate": true,
+	"dd":       true,
+	"chmod":    true,
+	"chown":    true,
+	"chgrp":    true,
+}
+
+type Runner struct {
+	template string
+}
+
+func New(template string) *Runner {
+	return &Runner{template: template}
+}
+
+func (r *Runner) Template() string {
+	return r.template
+}
+
+// IsDestructive reports whether the command looks like it modifies or
+// removes the entries it is given, so callers can ask for confirmation.
+// Any word of the template counts, which errs on the side of asking for
+// chains like "echo {} && rm {}".
+func (r *Runner) IsDestructive() bool {
+	for _, field := range strings.FieldsFunc(r.template, func(c rune) bool {
+		return c == ' ' || c == '\t' || c == ';' || c == '&' || c == '|'
+	}) {
+		if destructiveCommands[filepath.Base(field)] || field == "-delete" {
+			return true
+		}
+	}
+	return false
+}
+
+// Command expands the template for path. Every {} is replaced with the
+// shell-quoted path; if the template has none, the path is appended.
+func (r *Runner) Command(path string) string {
+	quoted := shellQuote(path)
+	if strings.Contains(r.template, Placeholder) {
+		return strings.ReplaceAll(r.template, Placeholder, quoted)
+	}
+	return r.template + " " + quoted
+}
+
+// Run executes the command for path through sh and returns a short status
+// suitable for a table cell.
+func (r *Runner) Run(path string) string {
+	cmd := exec.Command("sh", "-c", r.Command(path))
+	cmd.Dir = filepath.Dir(path)
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Sprintf("exit %d", exitErr.ExitCode())
+		}
+		return "error"
+	}
+	return "ok"
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,150 @@
+// Package icons maps file entries to Nerd Font glyphs.
+package icons
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/ipanardian/lu-hut/internal/model"
+)
+
+const (
+	Directory  = "\uf07b"
+	File       = "\uf15b"
+	Symlink    = "\uf0c1"
+	Executable = "\uf489"
+)
+
+var byName = map[string]string{
+	".git":           "\ue5fb",
+	".gitignore":     "\ue702",
+	".gitmodules":    "\ue702",
+	".gitattributes": "\ue702",
+	".github":        "\ue5fd",
+	"node_modules":   "\ue5fa",
+	"go.mod":         "\ue627",
+	"go.sum":         "\ue627",
+	"Makefile":       "\ue779",
+	"Dockerfile":     "\uf308",
+	"LICENSE":        "\uf718",
+	"README.md":      "\uf48a",
+	"package.json":   "\ue71e",
+	"Cargo.toml":     "\ue7a8",
+}
+
+var byExtension = map[string]string{
+	".go":   "\ue627",
+	".rs":   "\ue7a8",
+	".py":   "\ue606",
+	".js":   "\ue74e",
+	".jsx":  "\ue7ba",
+	".ts":   "\ue628",
+	".tsx":  "\ue7ba",
+	".c":    "\ue61e",
+	".h":    "\ue61e",
+	".cpp":  "\ue61d",
+	".java": "\ue738",
+	".rb":   "\ue21e",
+	".php":  "\ue73d",
+	".lua":  "\ue620",
+	".sh":   "\uf489",
+	".bash": "\uf489",
+	".zsh":  "\uf489",
+	".html": "\ue736",
+	".css":  "\ue749",
+	".scss": "\ue603",
+	".json": "\ue60b",
+	".yml":  "\uf481",
+	".yaml": "\uf481",
+	".toml": "\ue615",
+	".ini":  "\ue615",
+	".xml":  "\ue619",
+	".md":   "\uf48a",
+	".txt":  "\uf15c",
+	".rst":  "\uf15c",
+	".pdf":  "\uf1c1",
+	".log":  "\uf18d",
+	".lock": "\uf023",
+	".sql":  "\uf1c0",
+	".db":   "\uf1c0",
+	".png":  "\uf1c5",
+	".jpg":  "\uf1c5",
+	".jpeg": "\uf1c5",
+	".gif":  "\uf1c5",
+	".bmp":  "\uf1c5",
+	".svg":  "\uf1c5",
+	".webp": "\uf1c5",
+	".ico":  "\uf1c5",
+	".mp3":  "\uf1c7",
+	".wav":  "\uf1c7",
+	".flac": "\uf1c7",
+	".ogg":  "\uf1c7",
+	".mp4":  "\uf1c8",
+	".mkv":  "\uf1c8",
+	".mov":  "\uf1c8",
+	".webm": "\uf1c8",
+	".avi":  "\uf1c8",
+	".zip":  "\uf1c6",
+	".tar":  "\uf1c6",
+	".gz":   "\uf1c6",
+	".tgz":  "\uf1c6",
+	".bz2":  "\uf1c6",
+	".xz":   "\uf1c6",
+	".7z":   "\uf1c6",
+	".rar":  "\uf1c6",
+	".zst":  "\uf1c6",
+}
+
+// Set resolves icons from the built-in map, with user overrides taking
+// precedence. Override keys are either an exact file name, an extension
+// with its leading dot, or one of "dir", "file", "link" and "exec".
+type Set struct {
+	overrides map[string]string
+}
+
+func NewSet(overrides map[string]string) *Set {
+	return &Set{overrides: overrides}
+}
+
+func (s *Set) Lookup(file model.FileEntry) string {
+	if icon, ok := s.lookup(file.Name, byName); ok {
+		return icon
+	}
+
+	if file.Mode&fs.ModeSymlink != 0 {
+		return s.fallback("link", Symlink)
+	}
+
+	if file.IsDir {
+		return s.fallback("dir", Directory)
+	}
+
+	ext := strings.ToLower(filepath.Ext(file.Name))
+	if ext != "" {
+		if icon, ok := s.lookup(ext, byExtension); ok {
+			return icon
+		}
+	}
+
+	if file.Mode.Perm()&0111 != 0 {
+		return s.fallback("exec", Executable)
+	}
+
+	return s.fallback("file", File)
+}
+
+func (s *Set) lookup(key string, builtin map[string]string) (string, bool) {
+	if icon, ok := s.overrides[key]; ok {
+		return icon, true
+	}
+	icon, ok := builtin[key]
+	return icon, ok
+}
+
+func (s *Set) fallback(key, icon string) string {
+	if override, ok := s.overrides[key]; ok {
+		return override
+	}
+	return icon
+}
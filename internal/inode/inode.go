@@ -0,0 +1,11 @@
+// Package inode reads the low-level identity of a file: its inode number
+// and how many hard links point at it.
+package inode
+
+import "os"
+
+// Of returns the inode number and hard-link count recorded in info, or
+// zeros where the platform does not report them.
+func Of(info os.FileInfo) (ino, links uint64) {
+	return stat(info)
+}
@@ -0,0 +1,16 @@
+//go:build !windows
+
+package inode
+
+import (
+	"os"
+	"syscall"
+)
+
+func stat(info os.FileInfo) (uint64, uint64) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0
+	}
+	return uint64(st.Ino), uint64(st.Nlink)
+}
@@ -0,0 +1,95 @@
+// Package inspect builds the model.FileEntry for one file, filling in the
+// extra fields the configuration asks for, so the table, the tree and the
+// flat listings all describe a file the same way.
+package inspect
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/ipanardian/lu-hut/internal/archive"
+	"github.com/ipanardian/lu-hut/internal/atime"
+	"github.com/ipanardian/lu-hut/internal/birthtime"
+	"github.com/ipanardian/lu-hut/internal/config"
+	"github.com/ipanardian/lu-hut/internal/entropy"
+	"github.com/ipanardian/lu-hut/internal/filter"
+	"github.com/ipanardian/lu-hut/internal/inode"
+	"github.com/ipanardian/lu-hut/internal/media"
+	"github.com/ipanardian/lu-hut/internal/model"
+	"github.com/ipanardian/lu-hut/internal/owner"
+	"github.com/ipanardian/lu-hut/internal/perms"
+	"github.com/ipanardian/lu-hut/internal/shortcut"
+	"github.com/ipanardian/lu-hut/internal/sniff"
+)
+
+// Entry returns the entry for the file at path, shown as name, with the
+// fields that come from its lstat info: the extra timestamps, owner, the
+// --explain-perms note and inode when cfg needs them. info is nil for a
+// path that no longer exists; the entry then carries only its name.
+func Entry(cfg config.Config, name, path string, info fs.FileInfo) model.FileEntry {
+	base := filepath.Base(path)
+	file := model.FileEntry{
+		Name:        name,
+		Path:        path,
+		IsHidden:    strings.HasPrefix(base, "."),
+		Highlighted: filter.MatchAny(cfg.Highlight, base),
+	}
+	if info == nil {
+		return file
+	}
+
+	file.Size = info.Size()
+	file.Mode = info.Mode()
+	file.ModTime = info.ModTime()
+	file.IsDir = info.IsDir()
+	file.BrokenLink = model.IsBrokenLink(file)
+	if cfg.NeedsTime("created") {
+		file.Created = birthtime.Of(path, info)
+	}
+	if cfg.NeedsTime("accessed") {
+		file.Accessed = atime.Of(info)
+	}
+	if cfg.NeedsOwner() {
+		file.Author, file.Group = owner.Of(info)
+	}
+	if cfg.ExplainPerms {
+		file.PermNote = perms.Explain(file.Mode, file.Author)
+	}
+	if cfg.NeedsInode() {
+		file.Inode, file.Links = inode.Of(info)
+	}
+	return file
+}
+
+// Contents fills in the fields that read the file's contents: --entries,
+// --media, --compress, --mime and --shortcuts. Entries on /proc and /sys
+// are left alone, since they report fake sizes and reading them can block.
+func Contents(cfg config.Config, file *model.FileEntry) {
+	if file.Virtual {
+		return
+	}
+	if !file.Mode.IsRegular() {
+		// An archive behind a symlink is still counted; Count itself
+		// refuses FIFOs and devices, whose open would block.
+		if cfg.ShowEntries && file.Mode&fs.ModeSymlink != 0 && archive.IsArchive(file.Name) {
+			file.Entries, _ = archive.Count(file.Path)
+		}
+		return
+	}
+	if cfg.ShowEntries && archive.IsArchive(file.Name) {
+		file.Entries, _ = archive.Count(file.Path)
+	}
+	if cfg.ShowMedia {
+		file.Media = media.Probe(file.Path)
+	}
+	if cfg.ShowCompress {
+		file.Compress = entropy.Rate(file.Path)
+	}
+	if len(cfg.Mime) > 0 {
+		file.Mime = sniff.Type(file.Path)
+	}
+	if cfg.ShowShortcuts {
+		file.Target, _ = shortcut.Resolve(file.Path, file.Size)
+	}
+}
@@ -0,0 +1,135 @@
+// Package linkaudit inspects the symlinks below a directory, for
+// deployments built from link farms where a stale or misdirected link is
+// easy to miss in a plain listing.
+package linkaudit
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Link is one symlink found below the audited root.
+type Link struct {
+	// Path is the link's location relative to the root.
+	Path string
+	// Target is the link's contents as stored, before any resolution.
+	Target string
+	// Resolved is the absolute, cleaned path the link points at. Only the
+	// link itself is resolved, not any symlinks along the target path.
+	Resolved string
+	Absolute bool
+	Broken   bool
+	// Escapes is set when the target lies outside the root, so the link
+	// stops working once the tree is copied or mounted elsewhere.
+	Escapes bool
+}
+
+// Group collects the links that point into the same directory.
+type Group struct {
+	// Dir is the target directory, relative to the root when it lies
+	// inside it and absolute otherwise.
+	Dir   string
+	Links []Link
+}
+
+// Mixed reports whether the group's links use both relative and absolute
+// targets, which usually means one of them was made by hand.
+func (g Group) Mixed() bool {
+	var relative, absolute bool
+	for _, link := range g.Links {
+		if link.Absolute {
+			absolute = true
+		} else {
+			relative = true
+		}
+	}
+	return relative && absolute
+}
+
+// Result is the outcome of an audit, with groups ordered by directory and
+// links within a group ordered by path.
+type Result struct {
+	Root   string
+	Groups []Group
+}
+
+// Audit walks root without following symlinked directories and records
+// every symlink it finds. Subdirectories that cannot be read are skipped.
+func Audit(root string) (*Result, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(root); err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string][]Link)
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type()&fs.ModeSymlink == 0 {
+			return nil
+		}
+
+		link, ok := inspect(root, path)
+		if ok {
+			dir := filepath.Dir(link.Resolved)
+			if rel, inside := within(root, dir); inside {
+				dir = rel
+			}
+			groups[dir] = append(groups[dir], link)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Root: root}
+	for dir, links := range groups {
+		sort.Slice(links, func(i, j int) bool { return links[i].Path < links[j].Path })
+		result.Groups = append(result.Groups, Group{Dir: dir, Links: links})
+	}
+	sort.Slice(result.Groups, func(i, j int) bool { return result.Groups[i].Dir < result.Groups[j].Dir })
+	return result, nil
+}
+
+func inspect(root, path string) (Link, bool) {
+	target, err := os.Readlink(path)
+	if err != nil {
+		return Link{}, false
+	}
+
+	link := Link{Target: target, Absolute: filepath.IsAbs(target)}
+	link.Path, _ = within(root, path)
+	if link.Absolute {
+		link.Resolved = filepath.Clean(target)
+	} else {
+		link.Resolved = filepath.Join(filepath.Dir(path), target)
+	}
+	_, inside := within(root, link.Resolved)
+	link.Escapes = !inside
+	_, err = os.Stat(path)
+	link.Broken = err != nil
+	return link, true
+}
+
+// within returns path relative to root and whether it lies inside root.
+func within(root, path string) (string, bool) {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path, false
+	}
+	return rel, true
+}
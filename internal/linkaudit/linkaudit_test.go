@@ -0,0 +1,60 @@
+package linkaudit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAudit(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	for _, dir := range []string{"releases/42", "shared"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	links := map[string]string{
+		"current":          "releases/42",
+		"shared/app":       "../releases/42",
+		"latest":           filepath.Join(root, "releases", "42"),
+		"shared/missing":   "gone",
+		"shared/elsewhere": outside,
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(root, name)); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+
+	result, err := Audit(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := make(map[string]Link)
+	for _, g := range result.Groups {
+		if g.Dir == "releases" && !g.Mixed() {
+			t.Errorf("group %s: want mixed relative and absolute links", g.Dir)
+		}
+		if g.Dir == "shared" && g.Mixed() {
+			t.Errorf("group %s: not mixed", g.Dir)
+		}
+		for _, link := range g.Links {
+			found[filepath.ToSlash(link.Path)] = link
+		}
+	}
+	if len(found) != len(links) {
+		t.Fatalf("found %d links, want %d", len(found), len(links))
+	}
+
+	if l := found["shared/missing"]; !l.Broken || l.Escapes {
+		t.Errorf("shared/missing = %+v, want broken inside the root", l)
+	}
+	if l := found["shared/elsewhere"]; l.Broken || !l.Escapes || !l.Absolute {
+		t.Errorf("shared/elsewhere = %+v, want an absolute link escaping the root", l)
+	}
+	if l := found["shared/app"]; l.Broken || l.Escapes || l.Absolute {
+		t.Errorf("shared/app = %+v, want a working relative link", l)
+	}
+}
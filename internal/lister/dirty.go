@@ -0,0 +1,65 @@
+package lister
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ipanardian/lu-hut/internal/git"
+	"github.com/ipanardian/lu-hut/internal/inspect"
+	"github.com/ipanardian/lu-hut/internal/model"
+	"github.com/ipanardian/lu-hut/internal/renderer"
+)
+
+// ListDirty prints every changed and untracked path of the worktree that
+// contains dir as one flat table, named relative to the repository root.
+// Deleted files are listed too, without size or permissions.
+func (d *Lister) ListDirty(dir string) error {
+	repo, err := git.NewRepository(dir)
+	if err != nil {
+		return fmt.Errorf("%s is not inside a git repository", dir)
+	}
+	repo.SetTimeout(d.config.TimeoutDuration())
+	d.setRoot(repo.Root())
+	changes, err := repo.Changes()
+	if err != nil {
+		return fmt.Errorf("git status failed: %w", err)
+	}
+
+	files := make([]model.FileEntry, 0, len(changes))
+	for _, change := range changes {
+		name := strings.TrimSuffix(change.Path, "/")
+		full := filepath.Join(repo.Root(), filepath.FromSlash(name))
+		if (d.filter.HasIncludePatterns() && !d.filter.ShouldInclude(full)) || d.filter.ShouldExclude(full) {
+			continue
+		}
+
+		// A deleted file has nothing to stat and is shown by name alone.
+		var info fs.FileInfo
+		if fi, err := os.Lstat(full); err == nil {
+			info = fi
+		}
+		file := inspect.Entry(d.config, name, full, info)
+		file.Virtual = info == nil
+		file.GitStatus = change.Status
+		file.GitOrigin = change.Origin
+		files = append(files, file)
+	}
+	d.sortStrat.Sort(files, d.config.Reverse)
+
+	if d.config.Format == "json" {
+		return renderer.RenderJSON(files)
+	}
+	if len(files) == 0 {
+		fmt.Println("Working tree clean")
+		return nil
+	}
+
+	cfg := d.config
+	cfg.ShowGit = true
+	renderer.NewTable(cfg).Render(files, time.Now())
+	return nil
+}
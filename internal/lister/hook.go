@@ -0,0 +1,48 @@
+package lister
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/ipanardian/lu-hut/internal/hook"
+	"github.com/ipanardian/lu-hut/internal/model"
+	"golang.org/x/term"
+)
+
+func (d *Lister) runHook(ctx context.Context, files []model.FileEntry) {
+	if d.hook == nil {
+		return
+	}
+
+	for i := range files {
+		if ctx.Err() != nil {
+			return
+		}
+		files[i].Exec = d.hook.Run(files[i].Path)
+	}
+}
+
+func confirmHook(runner *hook.Runner) error {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return fmt.Errorf("refusing to run destructive command %q without a terminal; pass --yes to confirm", runner.Template())
+	}
+
+	fmt.Fprintf(os.Stderr, "%s %s will run on every listed entry. Continue? [y/N] ",
+		color.New(color.FgYellow).Sprint("⚠"),
+		color.New(color.FgCyan, color.Bold).Sprint(runner.Template()))
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("confirmation aborted")
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	}
+	return fmt.Errorf("cancelled")
+}
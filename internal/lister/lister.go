@@ -8,17 +8,33 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/ipanardian/lu-hut/internal/config"
+	"github.com/ipanardian/lu-hut/internal/deadline"
+	"github.com/ipanardian/lu-hut/internal/du"
 	"github.com/ipanardian/lu-hut/internal/filter"
+	"github.com/ipanardian/lu-hut/internal/generated"
 	"github.com/ipanardian/lu-hut/internal/git"
+	"github.com/ipanardian/lu-hut/internal/hook"
+	"github.com/ipanardian/lu-hut/internal/inspect"
+	"github.com/ipanardian/lu-hut/internal/lscolors"
 	"github.com/ipanardian/lu-hut/internal/model"
+	"github.com/ipanardian/lu-hut/internal/owner"
+	"github.com/ipanardian/lu-hut/internal/perms"
+	"github.com/ipanardian/lu-hut/internal/portable"
+	"github.com/ipanardian/lu-hut/internal/project"
+	"github.com/ipanardian/lu-hut/internal/pseudofs"
 	"github.com/ipanardian/lu-hut/internal/renderer"
 	"github.com/ipanardian/lu-hut/internal/sort"
+	"github.com/ipanardian/lu-hut/internal/tags"
+	"github.com/ipanardian/lu-hut/internal/terminal"
+	"github.com/ipanardian/lu-hut/internal/theme"
 )
 
 type Lister struct {
@@ -26,38 +42,119 @@ type Lister struct {
 	gitRepo   *git.Repository
 	filter    *filter.Filter
 	sortStrat sort.Strategy
+	hook      *hook.Runner
+	usage     *du.Counter
+	mounts    *du.Filesystems
+	perms     perms.Expectations
+	deviating int
+	// gitWarned records which git warnings were printed, so a broken
+	// repository is reported once rather than for every directory.
+	gitWarned map[string]bool
 }
 
-func New(cfg config.Config) *Lister {
-	switch cfg.ColorMode {
-	case "never":
+func New(cfg config.Config) (*Lister, error) {
+	if cfg.Conflicts || cfg.GitDirty {
+		cfg.ShowGit = true
+	}
+	color.NoColor = !terminal.ColorEnabled(cfg.ColorMode)
+	if cfg.Pick {
+		color.NoColor = !terminal.TTYColorEnabled(cfg.ColorMode)
+	}
+	if cfg.Output != "" && !cfg.Tee && cfg.ColorMode != "always" {
+		color.NoColor = true
+	}
+	if cfg.Format == "json" || cfg.Format == "flat" {
 		color.NoColor = true
-	case "always":
-		color.NoColor = false
-	case "auto":
+	}
+	if !color.NoColor && !terminal.EnableVirtualTerminal() {
+		color.NoColor = true
+	}
+
+	// The OSC 11 query costs a round trip to the terminal, and one that
+	// does not answer can leave its reply on screen, so only --theme auto
+	// sends it. Otherwise COLORFGBG alone picks the light theme.
+	themeName := cfg.Theme
+	if themeName == "" || themeName == theme.Auto {
+		themeName = ""
+		if !color.NoColor {
+			if light, ok := terminal.LightBackground(cfg.Theme == theme.Auto); ok && light {
+				themeName = "light"
+			}
+		}
+	}
+
+	t, err := theme.Load(themeName)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]theme.Rule, 0, len(cfg.ColorRules))
+	for _, spec := range cfg.ColorRules {
+		rule, err := theme.ParseRule(spec)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	t.Rules = append(rules, t.Rules...)
+
+	if cfg.BorderColor != "" {
+		if t.Border, err = theme.ParseStyle(cfg.BorderColor); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.HeaderColor != "" {
+		if t.Header, err = theme.ParseStyle(cfg.HeaderColor); err != nil {
+			return nil, err
+		}
+	}
+	renderer.SetTheme(t)
+	renderer.SetASCII(cfg.ASCII)
+	renderer.SetAlignUnits(cfg.AlignUnits)
+	renderer.SetStatusBadges(cfg.Badges)
+	renderer.SetTruecolor(!color.NoColor && terminal.SupportsTruecolor())
+	renderer.SetLSColors(lscolors.FromEnv())
+	if cfg.ColorSpec != "" {
+		spec, err := lscolors.ParseSpec(cfg.ColorSpec)
+		if err != nil {
+			return nil, err
+		}
+		renderer.SetColorSpec(spec)
 	}
 
-	filter := filter.NewFilter(cfg.IncludePatterns, cfg.ExcludePatterns)
+	owner.SetTimeout(cfg.TimeoutDuration())
 
-	var sortStrat sort.Strategy
-	if cfg.SortSize {
-		sortStrat = &sort.Size{}
-	} else if cfg.SortExtension {
-		sortStrat = &sort.Extension{}
-	} else if cfg.SortModified {
-		sortStrat = &sort.Time{}
-	} else {
-		sortStrat = &sort.Name{}
+	filter := filter.NewFilter(cfg.IncludePatterns, cfg.Excludes())
+	filter.SetSizeRange(cfg.SizeRange())
+	filter.SetTypes(cfg.Only)
+	filter.SetOwners(cfg.FilterOwner, cfg.FilterGroup)
+	filter.SetMimes(cfg.Mime)
+	if cfg.Perm != "" {
+		m, err := perms.ParseMatch(cfg.Perm)
+		if err != nil {
+			return nil, err
+		}
+		filter.SetPerm(&m)
 	}
 
-	return &Lister{
+	sortStrat := sort.New(cfg.SortOrder(), sort.Options{Dirs: cfg.DirPlacement(), CaseSensitive: cfg.CaseSensitive})
+
+	l := &Lister{
 		config:    cfg,
 		filter:    filter,
 		sortStrat: sortStrat,
 	}
+	if cfg.Exec != "" {
+		l.hook = hook.New(cfg.Exec)
+	}
+	if cfg.ExpectPerms != "" {
+		if l.perms, err = perms.Parse(cfg.ExpectPerms); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
 }
 
-func (d *Lister) List(path string) error {
+func (d *Lister) List(paths ...string) (err error) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -72,42 +169,148 @@ func (d *Lister) List(path string) error {
 		}
 	}()
 
-	absPath, err := filepath.Abs(path)
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	roots := make([]string, 0, len(paths))
+	for _, path := range paths {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+
+		info, err := os.Stat(absPath)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && !d.config.Watch {
+			return fmt.Errorf("path %s is not a directory", absPath)
+		}
+		roots = append(roots, absPath)
+	}
+
+	if d.hook != nil && d.hook.IsDestructive() && !d.config.AssumeYes {
+		if err := confirmHook(d.hook); err != nil {
+			return err
+		}
+	}
+
+	if d.config.Pick {
+		if len(roots) > 1 {
+			return fmt.Errorf("--pick takes a single directory")
+		}
+		return d.pick(ctx, roots[0])
+	}
+
+	if d.config.Watch {
+		if len(roots) > 1 {
+			return fmt.Errorf("--watch takes a single file or directory")
+		}
+		return d.watch(ctx, roots[0])
+	}
+
+	restore, err := terminal.Throttle(ctx, d.config.Throttle)
 	if err != nil {
 		return err
 	}
+	defer restore()
 
-	info, err := os.Stat(absPath)
+	finish, err := terminal.RedirectOutput(d.config.Output, d.config.Tee, d.config.ColorMode != "always")
 	if err != nil {
 		return err
 	}
-	if !info.IsDir() {
-		return fmt.Errorf("path %s is not a directory", absPath)
+	completed := false
+	defer func() {
+		// An interrupted or crashed listing must not replace the previous
+		// report; completed stays false while a panic unwinds through here.
+		if finishErr := finish(completed && err == nil && ctx.Err() == nil); err == nil {
+			err = finishErr
+		}
+	}()
+
+	err = d.listRoots(ctx, paths, roots)
+	completed = true
+	return err
+}
+
+// listRoots lists every root in the format the options ask for. paths are
+// the roots as the user wrote them, used for the headings.
+func (d *Lister) listRoots(ctx context.Context, paths, roots []string) error {
+	if d.config.Format == "json" {
+		return d.listJSON(ctx, roots)
 	}
 
-	if d.config.ShowGit {
-		d.gitRepo, _ = git.NewRepository(absPath)
+	if d.config.Tree && len(roots) > 1 {
+		return d.listTrees(ctx, paths, roots)
+	}
+
+	for i, root := range roots {
+		if len(roots) > 1 {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("%s:\n", paths[i])
+		}
+		if err := d.listRoot(ctx, root); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *Lister) listRoot(ctx context.Context, absPath string) error {
+	repo, err := d.openRepo(absPath)
+	if err != nil {
+		return err
+	}
+	d.gitRepo = repo
+	d.setRoot(absPath)
+
+	if d.config.ShowProject && d.config.Format != "flat" {
+		if info, ok := project.Detect(absPath); ok {
+			renderer.RenderProjectHeader(info)
+		}
+	}
+	if repo != nil && d.config.ShowGit && d.config.Format != "flat" {
+		if summary, err := repo.Summary(); err == nil {
+			renderer.RenderGitHeader(summary)
+		}
 	}
 
 	if d.config.Tree {
 		return d.listTree(ctx, absPath)
 	}
 
+	d.mounts = nil
+	if d.config.Recursive || d.config.DiskUsage {
+		d.mounts = du.NewFilesystems()
+	}
+
 	if d.config.Recursive {
-		return d.listRecursive(ctx, absPath)
+		if err := d.listRecursive(ctx, absPath); err != nil {
+			return err
+		}
+		renderer.RenderFilesystems(d.mounts.List())
+		return nil
 	}
 
-	entries, err := os.ReadDir(absPath)
+	files, _, conflicts, more, err := d.readDir(ctx, absPath)
 	if err != nil {
 		return err
 	}
 
-	files := d.collectFiles(absPath, entries)
-	files = d.filter.Apply(files, d.config.ShowHidden)
-	d.sortStrat.Sort(files, d.config.Reverse)
-
-	renderer := renderer.NewTable(d.config)
-	renderer.Render(files, time.Now())
+	table := renderer.NewTable(d.config)
+	table.Render(files, time.Now())
+	renderer.RenderMore(more)
+	if d.config.DiskUsage && len(files) > 0 {
+		if d.usage != nil {
+			renderer.RenderDiskUsage(d.usage.Total())
+		}
+		renderer.RenderFilesystems(d.mounts.List())
+	}
+	d.warnPortability(absPath, conflicts, files)
 
 	return nil
 }
@@ -118,7 +321,48 @@ func (d *Lister) listTree(ctx context.Context, rootPath string) error {
 		treeRenderer.SetGitRepo(d.gitRepo)
 	}
 	treeRenderer.SetFilter(d.filter)
-	return treeRenderer.Render(ctx, rootPath, time.Now())
+	if err := treeRenderer.Render(ctx, rootPath, time.Now()); err != nil {
+		return err
+	}
+	d.warnTreePortability(treeRenderer)
+	d.warnGit("ignore rules", treeRenderer.GitError())
+	d.warnGit("last commits", treeRenderer.CommitError())
+	return nil
+}
+
+func (d *Lister) listTrees(ctx context.Context, labels, roots []string) error {
+	treeRenderer := renderer.NewTree(d.config)
+	treeRenderer.SetFilter(d.filter)
+	now := time.Now()
+
+	for i, root := range roots {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		repo, err := d.openRepo(root)
+		if err != nil {
+			return err
+		}
+		treeRenderer.SetGitRepo(repo)
+		d.setRoot(root)
+
+		if i > 0 && d.config.Format != "flat" {
+			fmt.Println()
+		}
+		treeRenderer.RenderRoot(labels[i])
+		if err := treeRenderer.Render(ctx, root, now); err != nil {
+			return err
+		}
+	}
+
+	if ctx.Err() == nil {
+		treeRenderer.RenderSummary(len(roots))
+		d.warnTreePortability(treeRenderer)
+		d.warnGit("ignore rules", treeRenderer.GitError())
+		d.warnGit("last commits", treeRenderer.CommitError())
+	}
+	return nil
 }
 
 func (d *Lister) listRecursive(ctx context.Context, rootPath string) error {
@@ -131,6 +375,9 @@ func (d *Lister) listRecursive(ctx context.Context, rootPath string) error {
 		level int
 	}
 
+	// Sections are printed as they are read. The columns only ever widen,
+	// so each table lines up with the ones above it whenever it fits.
+	table := renderer.NewTable(d.config)
 	dirs := []dirEntry{{path: rootPath, level: 0}}
 	dirCount := 0
 
@@ -162,70 +409,437 @@ func (d *Lister) listRecursive(ctx context.Context, rootPath string) error {
 			break
 		}
 
-		if current.level > 0 {
+		files, subdirs, conflicts, more, err := d.readDir(ctx, current.path)
+		// A directory the filters left nothing in gets no section, so
+		// --min-size and the like only show where matches are.
+		if current.level > 0 && (err != nil || len(files) > 0 || !d.filter.Narrows()) {
 			indent := strings.Repeat("  ", current.level-1)
 			fmt.Printf("\n%s%s:\n", indent, current.path)
 		}
-
-		entries, err := os.ReadDir(current.path)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", current.path, err)
 			continue
 		}
 
-		files := d.collectFiles(current.path, entries)
-		files = d.filter.Apply(files, d.config.ShowHidden)
-		d.sortStrat.Sort(files, d.config.Reverse)
+		nextLevel := current.level + 1
+		if maxDepth == 0 || nextLevel < maxDepth {
+			for _, dir := range subdirs {
+				dirs = append(dirs, dirEntry{path: dir, level: nextLevel})
+			}
+		}
 
 		if len(files) == 0 {
 			continue
 		}
+		now := time.Now()
+		table.Align(files, now)
+		table.Render(files, now)
+		renderer.RenderMore(more)
+		if d.config.DiskUsage && d.usage != nil {
+			renderer.RenderDiskUsage(d.usage.Total())
+		}
+		d.warnPortability(current.path, conflicts, files)
+	}
+
+	return nil
+}
+
+// readDir runs one directory through the shared pipeline: collect, filter,
+// sort and run the --exec hook. Case conflicts are returned separately since
+// they are computed before filtering hides any of the colliding names, and
+// so are the subdirectories to recurse into, which --only may leave out of
+// the listed entries, and the number of entries --limit cut off.
+func (d *Lister) readDir(ctx context.Context, path string) ([]model.FileEntry, []string, [][]string, int, error) {
+	entries, err := sort.ReadDir(path, d.config.SortOrder())
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	files, subdirs, conflicts, more := d.process(ctx, path, d.collectFiles(path, d.inspectDir(path), entries))
+	return files, subdirs, conflicts, more, nil
+}
 
-		renderer := renderer.NewTable(d.config)
-		renderer.Render(files, time.Now())
+// process is the part of readDir that needs every entry of the directory
+// at once: filtering on the whole set, sorting and the --exec hook.
+func (d *Lister) process(ctx context.Context, path string, files []model.FileEntry) ([]model.FileEntry, []string, [][]string, int) {
+	var changed map[string]bool
+	if d.config.SinceLastRun {
+		changed = d.changedSinceLast(path, files)
+	}
+	files = d.filter.Apply(files, d.config.ShowHidden)
+	portable.MarkNameProblems(files)
+	if d.config.DiskUsage {
+		d.countUsage(ctx, path, files)
+	} else if d.mounts != nil {
+		d.trackMounts(files)
+	}
+	d.sortStrat.Sort(files, d.config.Reverse)
 
-		for _, file := range files {
+	var subdirs []string
+	for _, file := range files {
+		if file.IsDir {
+			subdirs = append(subdirs, file.Path)
+		}
+	}
+	files = d.narrow(files)
+	// Unchanged directories are still walked, since what changed may be
+	// further down.
+	if changed != nil {
+		files = slices.DeleteFunc(files, func(file model.FileEntry) bool { return !changed[file.Path] })
+	}
+	// Only names that are listed are checked, so a filtered-out entry never
+	// shows up in a conflict.
+	conflicts := portable.MarkCaseConflicts(files)
+
+	// --limit cuts before the hook, so --exec only acts on what is shown,
+	// and -R does not walk into the directories it left out.
+	more := 0
+	if limit := d.config.Limit; limit > 0 && len(files) > limit && !d.config.Pick {
+		cut := make(map[string]bool)
+		for _, file := range files[limit:] {
 			if file.IsDir {
-				nextLevel := current.level + 1
-				if maxDepth > 0 && nextLevel >= maxDepth {
-					continue
+				cut[file.Path] = true
+			}
+		}
+		subdirs = slices.DeleteFunc(subdirs, func(dir string) bool { return cut[dir] })
+		files, more = files[:limit], len(files)-limit
+		files[limit-1].More = more
+	}
+
+	d.checkPerms(files)
+	d.runHook(ctx, files)
+	return files, subdirs, conflicts, more
+}
+
+// narrow drops the entries --only, --owner, --group, --mime and --perm
+// leave out. It runs after subdirectories are noted, so recursion still
+// reaches into them.
+func (d *Lister) narrow(files []model.FileEntry) []model.FileEntry {
+	return slices.DeleteFunc(files, func(file model.FileEntry) bool {
+		return !d.filter.Matches(file)
+	})
+}
+
+// countUsage replaces the size of every directory in files, and with
+// --follow of every symlink, with the total of what it holds. One counter
+// spans the whole directory listing, so content reachable from several
+// entries only counts towards the unique size of the first of them.
+//
+// The scan gives up after the configured timeout, so a hung network mount
+// costs a few seconds instead of the whole listing. The directories and
+// links it did not get to are marked SizeUnknown, while files keep their
+// own size, and the directory total and filesystem summary are dropped
+// since they would be incomplete.
+func (d *Lister) countUsage(ctx context.Context, dir string, files []model.FileEntry) {
+	counter := du.New(d.config.Follow)
+	counter.SkipSpecial(d.config.SkipSpecial)
+	counter.Enclose(dir)
+	if d.mounts != nil {
+		counter.Track(d.mounts)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// A scan that times out keeps running until it notices the cancel, so
+	// it works on its own copy of the paths and only publishes results
+	// under the lock.
+	paths := make([]string, len(files))
+	for i, file := range files {
+		if !file.Virtual {
+			paths[i] = file.Path
+		}
+	}
+	var (
+		mu      sync.Mutex
+		usages  = make([]du.Usage, len(files))
+		counted int
+	)
+	timeout := d.config.DiskUsageTimeout()
+	_, finished := deadline.Run(timeout, func() bool {
+		for i, path := range paths {
+			var usage du.Usage
+			if path != "" {
+				usage = counter.Count(ctx, path)
+			}
+			mu.Lock()
+			if ctx.Err() == nil {
+				usages[i] = usage
+				counted = i + 1
+			}
+			mu.Unlock()
+		}
+		return true
+	})
+	cancel()
+
+	mu.Lock()
+	defer mu.Unlock()
+	d.usage = counter
+	if !finished {
+		fmt.Fprintf(os.Stderr, "Warning: --du gave up on %s after %s; unfinished sizes are shown as ?\n", dir, timeout)
+		d.usage = nil
+		d.mounts = nil
+	}
+	for i := range files {
+		if files[i].Virtual || !(files[i].IsDir || (d.config.Follow && files[i].Mode&fs.ModeSymlink != 0)) {
+			continue
+		}
+		if i >= counted {
+			files[i].SizeUnknown = true
+			continue
+		}
+		files[i].Size = usages[i].Unique
+		files[i].ApparentSize = usages[i].Apparent
+	}
+}
+
+// setRoot points the filter at the directory being listed, along with the
+// ignore file found there.
+func (d *Lister) setRoot(root string) {
+	d.filter.SetRoot(root)
+	ignore, err := filter.LoadIgnore(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: cannot read %s: %v\n", filepath.Join(root, filter.IgnoreFile), err)
+	}
+	d.filter.SetIgnore(ignore)
+}
+
+// openRepo returns the git repository containing path when an option needs
+// one, and nil when none does or path is outside a repository. Only
+// --conflicts and --git-dirty, which would otherwise list nothing, treat
+// that as an error.
+func (d *Lister) openRepo(path string) (*git.Repository, error) {
+	if !d.config.ShowGit && !d.config.GitIgnore && !d.config.GitBlame {
+		return nil, nil
+	}
+	repo, err := git.NewRepository(path)
+	if err != nil {
+		if d.config.Conflicts {
+			return nil, fmt.Errorf("--conflicts needs a git repository, and %s is not inside one", path)
+		}
+		if d.config.GitDirty {
+			return nil, fmt.Errorf("--git-dirty needs a git repository, and %s is not inside one", path)
+		}
+		return nil, nil
+	}
+	repo.SetTimeout(d.config.TimeoutDuration())
+	if d.config.ShowGit {
+		if err := repo.Status(); err != nil {
+			if d.config.Conflicts || d.config.GitDirty {
+				return nil, fmt.Errorf("git status unavailable: %w", err)
+			}
+			d.warnGit("status", err)
+		}
+	}
+	return repo, nil
+}
+
+// warnGit reports once per run that git could not tell what, so a blank
+// Git column or a listing with ignored files in it has an explanation.
+func (d *Lister) warnGit(what string, err error) {
+	if err == nil || d.gitWarned[what] {
+		return
+	}
+	if d.gitWarned == nil {
+		d.gitWarned = make(map[string]bool)
+	}
+	d.gitWarned[what] = true
+	fmt.Fprintf(os.Stderr, "Warning: git %s unavailable: %v\n", what, err)
+}
+
+// checkPerms marks the entries whose mode differs from --expect-perms and
+// reports each on stderr, except in the picker where it would garble the
+// screen.
+func (d *Lister) checkPerms(files []model.FileEntry) {
+	if d.perms == nil {
+		return
+	}
+	for i := range files {
+		if files[i].Virtual {
+			continue
+		}
+		want := d.perms.Check(files[i].Mode)
+		if want == "" {
+			continue
+		}
+		files[i].WantPerms = want
+		d.deviating++
+		if !d.config.Pick {
+			fmt.Fprintf(os.Stderr, "Warning: %s has mode %s, expected %s\n", files[i].Path, perms.Octal(files[i].Mode), want)
+		}
+	}
+}
+
+// Deviations returns how many listed entries did not have the mode
+// --expect-perms asks for.
+func (d *Lister) Deviations() int {
+	return d.deviating
+}
+
+// trackMounts adds the listed entries to the per-filesystem totals of an
+// -R scan without --du, where they are all that gets looked at.
+func (d *Lister) trackMounts(files []model.FileEntry) {
+	for _, file := range files {
+		if file.Virtual {
+			continue
+		}
+		if info, err := os.Lstat(file.Path); err == nil {
+			d.mounts.Add(file.Path, info)
+		}
+	}
+}
+
+// listJSON prints every root (and, with -R, every subdirectory up to
+// --max-depth) as one JSON array so the output stays a single document.
+// With --json-verbose each entry also carries its sort key.
+func (d *Lister) listJSON(ctx context.Context, roots []string) error {
+	var all []model.FileEntry
+	var keys []sort.Key
+
+	for _, root := range roots {
+		repo, err := d.openRepo(root)
+		if err != nil {
+			return err
+		}
+		d.gitRepo = repo
+		d.setRoot(root)
+
+		type dirEntry struct {
+			path  string
+			level int
+		}
+		dirs := []dirEntry{{path: root}}
+		for len(dirs) > 0 {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			current := dirs[0]
+			dirs = dirs[1:]
+
+			files, subdirs, _, _, err := d.readDir(ctx, current.path)
+			if err != nil {
+				if current.level == 0 {
+					return err
+				}
+				fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", current.path, err)
+				continue
+			}
+			all = append(all, files...)
+			if d.config.JSONVerbose {
+				opts := sort.Options{Dirs: d.config.DirPlacement(), CaseSensitive: d.config.CaseSensitive}
+				keys = append(keys, sort.Keys(files, d.config.SortOrder(), opts, d.config.Reverse)...)
+			}
+
+			if !d.config.Recursive {
+				continue
+			}
+			next := current.level + 1
+			if d.config.MaxDepth == 0 || next < d.config.MaxDepth {
+				for _, dir := range subdirs {
+					dirs = append(dirs, dirEntry{path: dir, level: next})
 				}
-				dirPath := filepath.Join(current.path, file.Name)
-				dirs = append(dirs, dirEntry{path: dirPath, level: nextLevel})
 			}
 		}
 	}
 
-	return nil
+	return renderer.RenderVerboseJSON(all, keys)
+}
+
+// dirInfo is what collectFiles needs to know about a directory as a whole.
+// It is looked up once, so a directory read in pages pays for it once.
+type dirInfo struct {
+	virtual bool
+	attrs   *generated.Attributes
+	// ignores is set when entries git ignores are hidden or marked.
+	ignores bool
+	commits map[string]git.Commit
 }
 
-func (d *Lister) collectFiles(path string, entries []fs.DirEntry) []model.FileEntry {
+func (d *Lister) inspectDir(path string) dirInfo {
+	info := dirInfo{virtual: pseudofs.Is(path)}
+	if !info.virtual {
+		info.attrs = generated.LoadAttributes(path)
+	}
+	// --git marks what --git-ignore would hide. The ignored paths are
+	// loaded once for the whole repository.
+	if (d.config.GitIgnore || d.config.ShowGit) && d.gitRepo != nil {
+		err := d.gitRepo.Ignored()
+		d.warnGit("ignore rules", err)
+		info.ignores = err == nil
+	}
+	if d.config.GitBlame && d.gitRepo != nil {
+		var err error
+		info.commits, err = d.gitRepo.LastCommits(path)
+		d.warnGit("last commits", err)
+	}
+	return info
+}
+
+func (d *Lister) collectFiles(path string, dir dirInfo, entries []fs.DirEntry) []model.FileEntry {
 	files := make([]model.FileEntry, 0, len(entries))
+	virtual, attrs := dir.virtual, dir.attrs
 
 	for _, entry := range entries {
+		ignored := dir.ignores && d.gitRepo.IsIgnored(filepath.Join(path, entry.Name()))
+		if d.config.GitIgnore && ignored {
+			continue
+		}
+		// The type comes from the directory itself, so special files are
+		// dropped before a stat that could block on them.
+		if d.config.SkipSpecial && model.IsSpecial(entry.Type()) {
+			continue
+		}
+
 		info, err := entry.Info()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: cannot read %s: %v\n", entry.Name(), err)
 			continue
 		}
 
-		file := model.FileEntry{
-			Name:     entry.Name(),
-			Path:     filepath.Join(path, entry.Name()),
-			Size:     info.Size(),
-			Mode:     info.Mode(),
-			ModTime:  info.ModTime(),
-			IsDir:    entry.IsDir(),
-			IsHidden: strings.HasPrefix(entry.Name(), "."),
+		file := inspect.Entry(d.config, entry.Name(), filepath.Join(path, entry.Name()), info)
+		file.Virtual = virtual
+
+		if (d.config.ShowTags || len(d.config.FilterTags) > 0) && !virtual {
+			file.Tags = tags.Read(file.Path)
+		}
+		if len(d.config.FilterTags) > 0 && !tags.Has(file.Tags, d.config.FilterTags) {
+			continue
+		}
+
+		// Files on /proc and /sys report fake sizes and reading them can
+		// block, so nothing below that opens file contents runs there.
+		if virtual {
+			files = append(files, file)
+			continue
 		}
 
-		if d.config.ShowGit && d.gitRepo != nil && !file.IsDir {
-			file.GitStatus = d.gitRepo.GetStatus(file.Path)
+		file.Generated = attrs.Detect(file.Path, d.config.GeneratedHeader && info.Mode().IsRegular())
+		if file.Generated && !d.config.ShowGenerated {
+			continue
+		}
+
+		if d.config.Conflicts && !d.gitRepo.HasConflicts(file.Path) {
+			continue
+		}
+		if d.config.GitDirty && !d.gitRepo.HasChanges(file.Path) {
+			continue
 		}
 
-		if d.config.ShowUser {
-			file.Author, file.Group = extractUserGroup(info)
+		if d.config.ShowGit && d.gitRepo != nil {
+			file.GitStatus = d.gitRepo.GetStatus(file.Path)
+			if file.IsDir && file.GitStatus == "" {
+				file.GitChanges = d.gitRepo.DirChanges(file.Path)
+			} else {
+				file.GitOrigin = d.gitRepo.Origin(file.Path)
+			}
+		}
+		if d.config.ShowGit && ignored {
+			file.GitStatus = "I"
 		}
+		file.LastCommit = model.Commit(dir.commits[entry.Name()])
+
+		inspect.Contents(d.config, &file)
 
 		files = append(files, file)
 	}
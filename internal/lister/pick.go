@@ -0,0 +1,203 @@
+package lister
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ipanardian/lu-hut/internal/config"
+	"github.com/ipanardian/lu-hut/internal/model"
+	"github.com/ipanardian/lu-hut/internal/renderer"
+	"github.com/ipanardian/lu-hut/internal/sort"
+	"github.com/ipanardian/lu-hut/internal/terminal"
+	"github.com/ipanardian/lu-hut/internal/tui"
+)
+
+// pickPage is how many directory entries the picker reads before showing
+// them, so a directory with a million files opens at once and fills in
+// while the user looks around.
+const pickPage = 1000
+
+// pick lets the user browse from root and mark entries, then prints the
+// chosen paths to stdout for the calling command. Cancelling prints nothing.
+func (d *Lister) pick(ctx context.Context, root string) error {
+	d.setRoot(root)
+	// A load the picker abandoned runs until its next page; the lock keeps
+	// it from overlapping the next one, which shares the lister's caches.
+	var mu sync.Mutex
+	load := func(loadCtx context.Context, dir string, page func([]model.FileEntry)) ([]model.FileEntry, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		loadCtx, cancel := context.WithCancel(loadCtx)
+		defer cancel()
+		stop := context.AfterFunc(ctx, cancel)
+		defer stop()
+		return d.loadPages(loadCtx, dir, page)
+	}
+
+	picker := tui.NewPicker(root, load, renderer.FormatName)
+	picker.SetSettings(pickSettings(d.config))
+	picker.SetExporter(d.exportView)
+	if d.config.SessionLog != "" {
+		f, err := os.OpenFile(d.config.SessionLog, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+		if err != nil {
+			return fmt.Errorf("cannot open session log: %w", err)
+		}
+		defer f.Close()
+		picker.SetSessionLog(tui.NewSessionLog(f))
+	}
+
+	paths, err := picker.Run()
+	if errors.Is(err, tui.ErrCancelled) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	sep := "\n"
+	if d.config.Print0 {
+		sep = "\x00"
+	}
+	cwd, _ := os.Getwd()
+	for _, path := range paths {
+		fmt.Print(displayPath(cwd, path), sep)
+	}
+	return nil
+}
+
+// exportView writes the entries the picker is showing to path, as JSON
+// for a .json file and as the plain table otherwise. The picker draws on
+// the terminal device, so stdout is free to redirect meanwhile.
+func (d *Lister) exportView(path string, files []model.FileEntry) error {
+	finish, err := terminal.RedirectOutput(path, false, true)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = renderer.RenderJSON(files)
+	} else {
+		renderer.NewTable(d.config).Render(files, time.Now())
+	}
+	if ferr := finish(err == nil); err == nil {
+		err = ferr
+	}
+	return err
+}
+
+// loadPages reads dir pickPage entries at a time, handing each full page to
+// page filtered but unsorted, and returns the finished listing once the
+// whole directory has been read. Smaller directories are never paged.
+func (d *Lister) loadPages(ctx context.Context, dir string, page func([]model.FileEntry)) ([]model.FileEntry, error) {
+	f, err := os.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info := d.inspectDir(dir)
+	var files []model.FileEntry
+	for {
+		entries, err := f.ReadDir(pickPage)
+		if len(entries) > 0 {
+			batch := d.collectFiles(dir, info, entries)
+			files = append(files, batch...)
+			if len(entries) == pickPage {
+				if shown := d.narrow(d.filter.Apply(batch, d.config.ShowHidden)); len(shown) > 0 {
+					page(shown)
+				}
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+
+	files, _, _, _ = d.process(ctx, dir, files)
+	return files, nil
+}
+
+// displayPath shortens path relative to the working directory when it lies
+// below it, so picked paths read like the ones a user would type.
+func displayPath(cwd, path string) string {
+	if cwd == "" {
+		return path
+	}
+	rel, err := filepath.Rel(cwd, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return rel
+}
+
+// pickSettings describes the sort order and filters the picker lists with,
+// for its help overlay.
+func pickSettings(cfg config.Config) []tui.Setting {
+	order := cfg.SortOrder()
+	switch cfg.DirPlacement() {
+	case sort.DirsLast:
+		order += ", directories last"
+	case sort.DirsMixed:
+		order += ", directories mixed in"
+	}
+	if cfg.Reverse {
+		order += ", reversed"
+	}
+
+	hidden := "hidden"
+	if cfg.ShowHidden {
+		hidden = "shown"
+	}
+	settings := []tui.Setting{{Name: "Sort", Value: order}, {Name: "Hidden files", Value: hidden}}
+
+	if len(cfg.IncludePatterns) > 0 {
+		settings = append(settings, tui.Setting{Name: "Include", Value: strings.Join(cfg.IncludePatterns, " ")})
+	}
+	if excludes := cfg.Excludes(); len(excludes) > 0 {
+		settings = append(settings, tui.Setting{Name: "Exclude", Value: strings.Join(excludes, " ")})
+	}
+	if cfg.MinSize != "" || cfg.MaxSize != "" {
+		minSize, maxSize := cfg.MinSize, cfg.MaxSize
+		if minSize == "" {
+			minSize = "0"
+		}
+		if maxSize == "" {
+			maxSize = "any"
+		}
+		settings = append(settings, tui.Setting{Name: "Size", Value: minSize + " to " + maxSize})
+	}
+	if len(cfg.Only) > 0 {
+		settings = append(settings, tui.Setting{Name: "Only", Value: strings.Join(cfg.Only, " ")})
+	}
+	if len(cfg.Mime) > 0 {
+		settings = append(settings, tui.Setting{Name: "MIME", Value: strings.Join(cfg.Mime, " ")})
+	}
+	if cfg.Perm != "" {
+		settings = append(settings, tui.Setting{Name: "Perm", Value: cfg.Perm})
+	}
+	if cfg.GitIgnore {
+		settings = append(settings, tui.Setting{Name: "Git ignored", Value: "hidden"})
+	}
+	if len(cfg.FilterTags) > 0 {
+		settings = append(settings, tui.Setting{Name: "Tags", Value: strings.Join(cfg.FilterTags, " ")})
+	}
+	if len(cfg.FilterOwner) > 0 {
+		settings = append(settings, tui.Setting{Name: "Owner", Value: strings.Join(cfg.FilterOwner, " ")})
+	}
+	if len(cfg.FilterGroup) > 0 {
+		settings = append(settings, tui.Setting{Name: "Group", Value: strings.Join(cfg.FilterGroup, " ")})
+	}
+	return settings
+}
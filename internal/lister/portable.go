@@ -0,0 +1,35 @@
+package lister
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ipanardian/lu-hut/internal/model"
+	"github.com/ipanardian/lu-hut/internal/renderer"
+)
+
+func (d *Lister) warnPortability(dir string, conflicts [][]string, files []model.FileEntry) {
+	for _, group := range conflicts {
+		fmt.Fprintf(os.Stderr, "Warning: names in %s differ only by case and collide on case-insensitive filesystems: %s\n",
+			dir, strings.Join(group, ", "))
+	}
+
+	if !d.config.StrictNames {
+		return
+	}
+	for _, file := range files {
+		if file.NameProblem != "" {
+			fmt.Fprintf(os.Stderr, "Warning: %+q has a risky name (%s)\n", file.Path, file.NameProblem)
+		}
+	}
+}
+
+func (d *Lister) warnTreePortability(tree *renderer.Tree) {
+	if count := tree.CaseConflicts(); count > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %d group(s) of names differ only by case and collide on case-insensitive filesystems\n", count)
+	}
+	if count := tree.NameProblems(); count > 0 && d.config.StrictNames {
+		fmt.Fprintf(os.Stderr, "Warning: %d name(s) contain invisible, bidi control or non-NFC characters\n", count)
+	}
+}
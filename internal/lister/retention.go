@@ -0,0 +1,90 @@
+package lister
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ipanardian/lu-hut/internal/inspect"
+	"github.com/ipanardian/lu-hut/internal/model"
+	"github.com/ipanardian/lu-hut/internal/pseudofs"
+	"github.com/ipanardian/lu-hut/internal/renderer"
+)
+
+// ListRetention previews a retention policy: every file below dir that was
+// last modified more than keep ago is listed as one that would be deleted,
+// named relative to dir, followed by the space that would be reclaimed.
+// Include and exclude patterns narrow the policy to matching names, while
+// .luignore does not apply: a preview that hid ignored files would
+// understate what the policy deletes. Nothing is removed.
+func (d *Lister) ListRetention(dir string, keep time.Duration) error {
+	now := time.Now()
+	cutoff := now.Add(-keep)
+	if abs, err := filepath.Abs(dir); err == nil {
+		d.filter.SetRoot(abs)
+		dir = abs
+	}
+
+	var (
+		expired   []model.FileEntry
+		kept      int
+		keptBytes int64
+	)
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Warning: cannot read %s: %v\n", path, err)
+			return nil
+		}
+
+		name := entry.Name()
+		hidden := strings.HasPrefix(name, ".") && path != dir
+		if entry.IsDir() {
+			if path != dir && ((hidden && !d.config.ShowHidden) || pseudofs.Is(path)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if hidden && !d.config.ShowHidden {
+			return nil
+		}
+		if (d.filter.HasIncludePatterns() && !d.filter.ShouldInclude(path)) || d.filter.ShouldExclude(path) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		if !info.ModTime().Before(cutoff) {
+			kept++
+			keptBytes += info.Size()
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			rel = path
+		}
+		expired = append(expired, inspect.Entry(d.config, filepath.ToSlash(rel), path, info))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	d.sortStrat.Sort(expired, d.config.Reverse)
+
+	if d.config.Format == "json" {
+		return renderer.RenderJSON(expired)
+	}
+	if len(expired) > 0 {
+		renderer.NewTable(d.config).Render(expired, now)
+	}
+	renderer.RenderRetention(expired, kept, keptBytes)
+	return nil
+}
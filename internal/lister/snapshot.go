@@ -0,0 +1,44 @@
+package lister
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ipanardian/lu-hut/internal/model"
+	"github.com/ipanardian/lu-hut/internal/snapshot"
+)
+
+// changedSinceLast returns the paths of the entries of dir that were added
+// or modified since the previous --changed-since-last run, then records the
+// current state for the next one. The first run returns nil, which keeps
+// everything.
+func (d *Lister) changedSinceLast(dir string, files []model.FileEntry) map[string]bool {
+	prev, ok, err := snapshot.Load(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: cannot read snapshot of %s: %v\n", dir, err)
+	}
+
+	now := snapshot.Take(files)
+	if err := snapshot.Save(dir, now); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: cannot save snapshot of %s: %v\n", dir, err)
+	}
+
+	if !ok {
+		fmt.Fprintf(os.Stderr, "No previous snapshot of %s, listing everything\n", dir)
+		return nil
+	}
+	if removed := prev.Removed(now); removed > 0 {
+		fmt.Fprintf(os.Stderr, "Removed from %s since the last run: %d\n", dir, removed)
+	}
+
+	changed := make(map[string]bool)
+	for _, file := range files {
+		if prev.Changed(file) {
+			changed[file.Path] = true
+		}
+	}
+	if len(changed) == 0 {
+		fmt.Fprintf(os.Stderr, "Nothing added or modified in %s since the last run\n", dir)
+	}
+	return changed
+}
@@ -0,0 +1,120 @@
+package lister
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ipanardian/lu-hut/internal/model"
+	"github.com/ipanardian/lu-hut/internal/renderer"
+	"golang.org/x/term"
+)
+
+// sample is an entry's size at one --watch refresh.
+type sample struct {
+	size int64
+	at   time.Time
+}
+
+// watch lists path again every --interval until interrupted, redrawing the
+// screen each time like watch(1). A file gets a table of its own single
+// row, a lighter `watch ls -l file`. The Growth column tells how fast each
+// entry grew since the previous refresh.
+func (d *Lister) watch(ctx context.Context, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	dir := path
+	if !info.IsDir() {
+		dir = filepath.Dir(path)
+	}
+	d.setRoot(dir)
+
+	interval := d.config.IntervalDuration()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	clear := term.IsTerminal(int(os.Stdout.Fd()))
+	var previous map[string]sample
+	for frame := 0; ; frame++ {
+		if d.config.ShowGit {
+			// Each refresh asks git again; the status it loaded before is
+			// as stale as the sizes.
+			if d.gitRepo, err = d.openRepo(dir); err != nil {
+				return err
+			}
+		}
+
+		files, more, err := d.watchEntries(ctx, path, info.IsDir())
+		now := time.Now()
+		previous = growth(files, previous, now, d.config.GrowthAlertRate())
+
+		switch {
+		case clear:
+			fmt.Print("\x1b[H\x1b[2J")
+		case frame > 0:
+			fmt.Println()
+		}
+		fmt.Printf("Every %s: %s  %s\n\n", interval, path, now.Format(time.TimeOnly))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", path, err)
+		} else {
+			renderer.NewTable(d.config).Render(files, now)
+			renderer.RenderMore(more)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// watchEntries reads what one --watch refresh shows: the listing of a
+// directory, or the single entry of a file. A file named on the command
+// line is shown even when git ignores it, marked as ignored under --git.
+func (d *Lister) watchEntries(ctx context.Context, path string, isDir bool) ([]model.FileEntry, int, error) {
+	if isDir {
+		files, _, _, more, err := d.readDir(ctx, path)
+		return files, more, err
+	}
+	info, err := os.Lstat(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	parent := filepath.Dir(path)
+	dir := d.inspectDir(parent)
+	if d.config.GitIgnore && !d.config.ShowGit {
+		dir.ignores = false
+	}
+	return d.collectFiles(parent, dir, []fs.DirEntry{fs.FileInfoToDirEntry(info)}), 0, nil
+}
+
+// growth sets the Growth of every file from its size in previous and
+// returns the samples to compare the next refresh with. Directories have
+// no meaningful size to compare. Files growing at least alert bytes per
+// second are highlighted, so a runaway log stands out in a busy directory.
+func growth(files []model.FileEntry, previous map[string]sample, now time.Time, alert int64) map[string]sample {
+	next := make(map[string]sample, len(files))
+	for i := range files {
+		file := &files[i]
+		if file.IsDir {
+			continue
+		}
+		if last, ok := previous[file.Path]; ok {
+			if elapsed := now.Sub(last.at).Seconds(); elapsed > 0 {
+				file.Growth, file.HasGrowth = float64(file.Size-last.size)/elapsed, true
+				if alert > 0 && file.Growth >= float64(alert) {
+					file.Highlighted = true
+				}
+			}
+		}
+		next[file.Path] = sample{size: file.Size, at: now}
+	}
+	return next
+}
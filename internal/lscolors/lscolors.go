@@ -0,0 +1,218 @@
+// Package lscolors parses the LS_COLORS environment variable used by GNU ls and dircolors.
+package lscolors
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/ipanardian/lu-hut/internal/model"
+)
+
+type Palette struct {
+	types    map[string][]color.Attribute
+	suffixes map[string][]color.Attribute
+	// partial palettes only answer for the keys they set, so callers can
+	// layer them over another palette.
+	partial bool
+}
+
+func FromEnv() *Palette {
+	value := os.Getenv("LS_COLORS")
+	if value == "" {
+		return nil
+	}
+	return Parse(value)
+}
+
+// Parse reads a colon separated list of key=SGR pairs. Keys are either two
+// letter file type indicators (di, ln, ex, ...) or glob-like suffixes such as
+// "*.go". Entries that do not parse are ignored, mirroring GNU ls.
+func Parse(value string) *Palette {
+	p, _ := parse(value, false)
+	return p
+}
+
+// ParseSpec reads the same syntax as Parse for a palette given on the
+// command line. Unlike LS_COLORS it reports malformed entries, and lookups
+// only succeed for the keys it sets, leaving everything else to the
+// palette underneath.
+func ParseSpec(value string) (*Palette, error) {
+	p, err := parse(value, true)
+	if err != nil {
+		return nil, err
+	}
+	p.partial = true
+	return p, nil
+}
+
+func parse(value string, strict bool) (*Palette, error) {
+	p := &Palette{
+		types:    make(map[string][]color.Attribute),
+		suffixes: make(map[string][]color.Attribute),
+	}
+
+	for _, entry := range strings.Split(value, ":") {
+		if entry == "" {
+			continue
+		}
+		key, seq, ok := strings.Cut(entry, "=")
+		if !ok || key == "" {
+			if strict {
+				return nil, fmt.Errorf("invalid color spec entry %q (expected key=SGR, e.g. di=34 or *.go=32)", entry)
+			}
+			continue
+		}
+
+		if seq == "target" {
+			p.types[key] = nil
+			continue
+		}
+
+		attrs, ok := parseSGR(seq)
+		if !ok {
+			if strict {
+				return nil, fmt.Errorf("invalid color spec entry %q: %q is not a list of SGR codes", entry, seq)
+			}
+			continue
+		}
+
+		if suffix, found := strings.CutPrefix(key, "*"); found {
+			p.suffixes[suffix] = attrs
+		} else {
+			p.types[key] = attrs
+		}
+	}
+
+	return p, nil
+}
+
+func parseSGR(seq string) ([]color.Attribute, bool) {
+	if seq == "" {
+		return nil, false
+	}
+
+	parts := strings.Split(seq, ";")
+	attrs := make([]color.Attribute, 0, len(parts))
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, false
+		}
+		attrs = append(attrs, color.Attribute(n))
+	}
+	return attrs, true
+}
+
+func (p *Palette) Lookup(file model.FileEntry) (*color.Color, bool) {
+	if p == nil {
+		return nil, false
+	}
+
+	attrs, ok := p.lookup(file)
+	if !ok || len(attrs) == 0 {
+		return nil, false
+	}
+	return color.New(attrs...), true
+}
+
+func (p *Palette) lookup(file model.FileEntry) ([]color.Attribute, bool) {
+	mode := file.Mode
+
+	switch {
+	case mode&fs.ModeSymlink != 0:
+		if _, err := os.Stat(file.Path); err != nil {
+			if attrs, ok := p.types["or"]; ok {
+				return attrs, true
+			}
+		}
+		attrs, ok := p.types["ln"]
+		if ok && attrs != nil {
+			return attrs, true
+		}
+		if !ok && p.partial {
+			return nil, false
+		}
+		if info, err := os.Stat(file.Path); err == nil {
+			target := file
+			target.Mode = info.Mode()
+			target.IsDir = info.IsDir()
+			return p.lookup(target)
+		}
+		return nil, false
+	case file.IsDir:
+		sticky := mode&fs.ModeSticky != 0
+		otherWritable := mode.Perm()&0002 != 0
+		switch {
+		case sticky && otherWritable:
+			if attrs, ok := p.types["tw"]; ok {
+				return attrs, true
+			}
+		case otherWritable:
+			if attrs, ok := p.types["ow"]; ok {
+				return attrs, true
+			}
+		case sticky:
+			if attrs, ok := p.types["st"]; ok {
+				return attrs, true
+			}
+		}
+		attrs, ok := p.types["di"]
+		return attrs, ok
+	case mode&fs.ModeNamedPipe != 0:
+		attrs, ok := p.types["pi"]
+		return attrs, ok
+	case mode&fs.ModeSocket != 0:
+		attrs, ok := p.types["so"]
+		return attrs, ok
+	case mode&fs.ModeCharDevice != 0:
+		attrs, ok := p.types["cd"]
+		return attrs, ok
+	case mode&fs.ModeDevice != 0:
+		attrs, ok := p.types["bd"]
+		return attrs, ok
+	}
+
+	if mode&fs.ModeSetuid != 0 {
+		if attrs, ok := p.types["su"]; ok {
+			return attrs, true
+		}
+	}
+	if mode&fs.ModeSetgid != 0 {
+		if attrs, ok := p.types["sg"]; ok {
+			return attrs, true
+		}
+	}
+	if mode.Perm()&0111 != 0 {
+		if attrs, ok := p.types["ex"]; ok {
+			return attrs, true
+		}
+	}
+
+	if attrs, ok := p.matchSuffix(file.Name); ok {
+		return attrs, true
+	}
+
+	attrs, ok := p.types["fi"]
+	return attrs, ok
+}
+
+func (p *Palette) matchSuffix(name string) ([]color.Attribute, bool) {
+	var (
+		best    []color.Attribute
+		bestLen int
+	)
+	lower := strings.ToLower(name)
+	for suffix, attrs := range p.suffixes {
+		if len(suffix) <= bestLen {
+			continue
+		}
+		if strings.HasSuffix(name, suffix) || strings.HasSuffix(lower, strings.ToLower(suffix)) {
+			best, bestLen = attrs, len(suffix)
+		}
+	}
+	return best, bestLen > 0
+}
@@ -0,0 +1,102 @@
+package lscolors
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/ipanardian/lu-hut/internal/model"
+)
+
+func TestPaletteLookup(t *testing.T) {
+	palette := Parse("di=01;34:ex=01;32:fi=0:*.go=38;5;208:*.tar.gz=01;31:*.TXT=33:bogus:ln=")
+
+	tests := []struct {
+		name     string
+		file     model.FileEntry
+		expected *color.Color
+	}{
+		{
+			name:     "directory",
+			file:     model.FileEntry{Name: "src", IsDir: true, Mode: fs.ModeDir | 0o755},
+			expected: color.New(color.Bold, color.FgBlue),
+		},
+		{
+			name:     "executable wins over suffix",
+			file:     model.FileEntry{Name: "build.go", Mode: 0o755},
+			expected: color.New(color.Bold, color.FgGreen),
+		},
+		{
+			name:     "suffix",
+			file:     model.FileEntry{Name: "main.go", Mode: 0o644},
+			expected: color.New(38, 5, 208),
+		},
+		{
+			name:     "longest suffix",
+			file:     model.FileEntry{Name: "dist.tar.gz", Mode: 0o644},
+			expected: color.New(color.Bold, color.FgRed),
+		},
+		{
+			name:     "case insensitive suffix",
+			file:     model.FileEntry{Name: "notes.txt", Mode: 0o644},
+			expected: color.New(color.FgYellow),
+		},
+		{
+			name:     "regular file",
+			file:     model.FileEntry{Name: "data.bin", Mode: 0o644},
+			expected: color.New(color.Reset),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, ok := palette.Lookup(tt.file)
+			if !ok {
+				t.Fatalf("Lookup(%q) found no color", tt.file.Name)
+			}
+			if !c.Equals(tt.expected) {
+				t.Errorf("Lookup(%q) returned unexpected color", tt.file.Name)
+			}
+		})
+	}
+}
+
+func TestPaletteLookupMissing(t *testing.T) {
+	palette := Parse("di=01;34")
+	if _, ok := palette.Lookup(model.FileEntry{Name: "main.go", Mode: 0o644}); ok {
+		t.Errorf("expected no color for unmatched regular file")
+	}
+
+	var nilPalette *Palette
+	if _, ok := nilPalette.Lookup(model.FileEntry{Name: "main.go"}); ok {
+		t.Errorf("expected nil palette to match nothing")
+	}
+}
+
+func TestParseSpec(t *testing.T) {
+	palette, err := ParseSpec("di=34:*.go=32:")
+	if err != nil {
+		t.Fatalf("ParseSpec() error = %v", err)
+	}
+
+	if c, ok := palette.Lookup(model.FileEntry{Name: "src", IsDir: true, Mode: fs.ModeDir | 0o755}); !ok || !c.Equals(color.New(color.FgBlue)) {
+		t.Errorf("Lookup(dir) = %v, %v", c, ok)
+	}
+	if c, ok := palette.Lookup(model.FileEntry{Name: "main.go", Mode: 0o644}); !ok || !c.Equals(color.New(color.FgGreen)) {
+		t.Errorf("Lookup(main.go) = %v, %v", c, ok)
+	}
+	for _, file := range []model.FileEntry{
+		{Name: "notes.txt", Mode: 0o644},
+		{Name: "link.go", Mode: fs.ModeSymlink | 0o777, Path: "/nonexistent/link.go"},
+	} {
+		if _, ok := palette.Lookup(file); ok {
+			t.Errorf("Lookup(%q) answered for a key the spec does not set", file.Name)
+		}
+	}
+
+	for _, spec := range []string{"di", "di=blue", "=32"} {
+		if _, err := ParseSpec(spec); err == nil {
+			t.Errorf("ParseSpec(%q) accepted a malformed entry", spec)
+		}
+	}
+}
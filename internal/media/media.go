@@ -0,0 +1,221 @@
+// Package media extracts lightweight metadata such as image dimensions and
+// audio/video duration by reading only file headers.
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const maxAtoms = 64
+
+// Probe returns a short description of the media file at path, such as
+// "1920×1080" for images or "3:25" for audio and video. It returns an empty
+// string for unsupported or unreadable files. Only regular files with a
+// known extension are opened, so a FIFO or device never blocks it.
+func Probe(path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
+	case ".png", ".jpg", ".jpeg", ".gif", ".wav", ".flac", ".mp4", ".m4a", ".m4v", ".mov":
+	default:
+		return ""
+	}
+	if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+		return ""
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	switch ext {
+	case ".png", ".jpg", ".jpeg", ".gif":
+		cfg, _, err := image.DecodeConfig(f)
+		if err != nil {
+			return ""
+		}
+		return fmt.Sprintf("%d×%d", cfg.Width, cfg.Height)
+	case ".wav":
+		return formatDuration(wavDuration(f))
+	case ".flac":
+		return formatDuration(flacDuration(f))
+	case ".mp4", ".m4a", ".m4v", ".mov":
+		return formatDuration(mp4Duration(f))
+	}
+
+	return ""
+}
+
+func formatDuration(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+
+	total := int(d.Round(time.Second).Seconds())
+	h, m, s := total/3600, (total%3600)/60, total%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%d:%02d", m, s)
+}
+
+func wavDuration(r io.ReadSeeker) time.Duration {
+	var header [12]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return 0
+	}
+
+	var byteRate uint32
+	for range maxAtoms {
+		var chunk [8]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			return 0
+		}
+		id := string(chunk[0:4])
+		size := binary.LittleEndian.Uint32(chunk[4:8])
+
+		switch id {
+		case "fmt ":
+			var fmtChunk [12]byte
+			if size < 12 {
+				return 0
+			}
+			if _, err := io.ReadFull(r, fmtChunk[:]); err != nil {
+				return 0
+			}
+			byteRate = binary.LittleEndian.Uint32(fmtChunk[8:12])
+			size -= 12
+		case "data":
+			if byteRate == 0 {
+				return 0
+			}
+			return time.Duration(float64(size) / float64(byteRate) * float64(time.Second))
+		}
+
+		if _, err := r.Seek(int64(size+size%2), io.SeekCurrent); err != nil {
+			return 0
+		}
+	}
+
+	return 0
+}
+
+func flacDuration(r io.Reader) time.Duration {
+	var header [4 + 4 + 18]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0
+	}
+	if string(header[0:4]) != "fLaC" || header[4]&0x7f != 0 {
+		return 0
+	}
+
+	info := header[8:]
+	packed := binary.BigEndian.Uint64(info[10:18])
+	sampleRate := packed >> 44
+	totalSamples := packed & 0xfffffffff
+	if sampleRate == 0 {
+		return 0
+	}
+
+	return time.Duration(float64(totalSamples) / float64(sampleRate) * float64(time.Second))
+}
+
+func mp4Duration(r io.ReadSeeker) time.Duration {
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return 0
+	}
+
+	moovStart, moovSize, ok := findAtom(r, 0, end, "moov")
+	if !ok {
+		return 0
+	}
+	mvhdStart, _, ok := findAtom(r, moovStart, moovStart+moovSize, "mvhd")
+	if !ok {
+		return 0
+	}
+	if _, err := r.Seek(mvhdStart, io.SeekStart); err != nil {
+		return 0
+	}
+
+	var body [32]byte
+	if _, err := io.ReadFull(r, body[:]); err != nil {
+		return 0
+	}
+
+	var timescale uint32
+	var duration uint64
+	if body[0] == 1 {
+		timescale = binary.BigEndian.Uint32(body[20:24])
+		duration = binary.BigEndian.Uint64(body[24:32])
+	} else {
+		timescale = binary.BigEndian.Uint32(body[12:16])
+		duration = uint64(binary.BigEndian.Uint32(body[16:20]))
+	}
+	if timescale == 0 {
+		return 0
+	}
+
+	return time.Duration(float64(duration) / float64(timescale) * float64(time.Second))
+}
+
+// findAtom scans the sibling atoms in [start, end) for the given type and
+// returns the offset and size of its payload.
+func findAtom(r io.ReadSeeker, start, end int64, kind string) (int64, int64, bool) {
+	offset := start
+	for range maxAtoms {
+		if offset+8 > end {
+			return 0, 0, false
+		}
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return 0, 0, false
+		}
+
+		var header [8]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return 0, 0, false
+		}
+		size := int64(binary.BigEndian.Uint32(header[0:4]))
+		headerLen := int64(8)
+
+		switch size {
+		case 0:
+			size = end - offset
+		case 1:
+			var ext [8]byte
+			if _, err := io.ReadFull(r, ext[:]); err != nil {
+				return 0, 0, false
+			}
+			size = int64(binary.BigEndian.Uint64(ext[:]))
+			headerLen = 16
+		}
+		if size < headerLen {
+			return 0, 0, false
+		}
+
+		if bytes.Equal(header[4:8], []byte(kind)) {
+			return offset + headerLen, size - headerLen, true
+		}
+		offset += size
+	}
+
+	return 0, 0, false
+}
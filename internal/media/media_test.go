@@ -0,0 +1,49 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		d        time.Duration
+		expected string
+	}{
+		{"zero", 0, ""},
+		{"seconds", 8 * time.Second, "0:08"},
+		{"minutes", 3*time.Minute + 25*time.Second, "3:25"},
+		{"hours", time.Hour + 2*time.Minute + 3*time.Second, "1:02:03"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := formatDuration(tt.d); result != tt.expected {
+				t.Errorf("formatDuration(%v) = %q, want %q", tt.d, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWavDuration(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(0))
+	buf.WriteString("WAVEfmt ")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(16))
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(1))
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(2))
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(44100))
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(44100*4))
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(4))
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(16))
+	buf.WriteString("data")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(44100*4*90))
+
+	if d := wavDuration(bytes.NewReader(buf.Bytes())); d != 90*time.Second {
+		t.Errorf("wavDuration() = %v, want %v", d, 90*time.Second)
+	}
+}
@@ -0,0 +1,27 @@
+//go:build !windows
+
+package media
+
+import (
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestProbeFIFO(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stream.wav")
+	if err := syscall.Mkfifo(path, 0o644); err != nil {
+		t.Skipf("mkfifo: %v", err)
+	}
+	done := make(chan string, 1)
+	go func() { done <- Probe(path) }()
+	select {
+	case got := <-done:
+		if got != "" {
+			t.Errorf("Probe(fifo) = %q, want empty", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Probe blocked on a FIFO")
+	}
+}
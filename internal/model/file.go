@@ -3,18 +3,81 @@ package model
 
 import (
 	"io/fs"
+	"os"
 	"time"
 )
 
 type FileEntry struct {
-	Name      string
-	Path      string
-	Size      int64
-	Mode      fs.FileMode
-	ModTime   time.Time
-	IsDir     bool
-	IsHidden  bool
-	GitStatus string
-	Author    string
-	Group     string
+	Name         string
+	Path         string
+	Size         int64
+	ApparentSize int64
+	SizeUnknown  bool
+	Mode         fs.FileMode
+	ModTime      time.Time
+	Created      time.Time
+	Accessed     time.Time
+	IsDir        bool
+	IsHidden     bool
+	GitStatus    string
+	GitOrigin    string
+	// GitChanges counts the changed and untracked paths inside a directory
+	// by status letter.
+	GitChanges   map[string]int
+	Author       string
+	Group        string
+	Media        string
+	Compress     string
+	Mime         string
+	Entries      int
+	Exec         string
+	Target       string
+	Tags         []Tag
+	Virtual      bool
+	Highlighted  bool
+	Generated    bool
+	BrokenLink   bool
+	CaseConflict bool
+	NameProblem  string
+	WantPerms    string
+	PermNote     string
+	Inode        uint64
+	Links        uint64
+	// Growth is how many bytes per second the entry grew since the previous
+	// --watch refresh, and HasGrowth whether there was one to compare with.
+	Growth     float64
+	HasGrowth  bool
+	LastCommit Commit
+	// More is set on the last entry --limit kept from a directory, to how
+	// many entries of that directory it left out.
+	More int
+}
+
+// Commit is the last commit that touched an entry, shown with --git-blame.
+// The zero value means there is none, as for untracked files.
+type Commit struct {
+	Hash   string
+	Author string
+	Time   time.Time
+}
+
+// Tag is a user-defined label attached to a file. Color is the Finder
+// label index, or 0 when the tag has no color of its own.
+type Tag struct {
+	Name  string
+	Color int
+}
+
+// IsSpecial reports whether mode belongs to a device node, FIFO or socket.
+func IsSpecial(mode fs.FileMode) bool {
+	return mode&(fs.ModeDevice|fs.ModeCharDevice|fs.ModeNamedPipe|fs.ModeSocket) != 0
+}
+
+// IsBrokenLink reports whether file is a symlink whose target is missing.
+func IsBrokenLink(file FileEntry) bool {
+	if file.Mode&fs.ModeSymlink == 0 {
+		return false
+	}
+	_, err := os.Stat(file.Path)
+	return err != nil
 }
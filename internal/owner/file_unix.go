@@ -0,0 +1,16 @@
+//go:build !windows
+
+package owner
+
+import (
+	"os"
+	"syscall"
+)
+
+// Of returns the user and group owning the file described by info.
+func Of(info os.FileInfo) (string, string) {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return files.User(stat.Uid), files.Group(stat.Gid)
+	}
+	return Unknown, Unknown
+}
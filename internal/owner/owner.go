@@ -0,0 +1,157 @@
+// Package owner turns numeric user and group ids into names. Each id is
+// resolved once per run, and the local passwd and group files are read up
+// front so most entries never reach the slower name service lookups.
+package owner
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ipanardian/lu-hut/internal/deadline"
+)
+
+// Unknown is the name reported for ids that cannot be resolved.
+const Unknown = "unknown"
+
+// TimedOut is the name reported for ids whose lookup took longer than the
+// resolver's timeout.
+const TimedOut = "?"
+
+// Resolver caches id to name lookups. The zero value is not usable; call
+// NewResolver.
+type Resolver struct {
+	mu       sync.Mutex
+	once     sync.Once
+	prefetch bool
+	timeout  time.Duration
+	users    map[uint32]string
+	groups   map[uint32]string
+}
+
+// files resolves the owners reported by Of. It is shared by every directory
+// and view of a run, so each uid and gid is only looked up once.
+var files = NewResolver(true)
+
+// SetTimeout bounds each name service lookup made by Of, so a slow LDAP
+// server shows TimedOut in the owner columns instead of stalling the
+// listing. Zero waits indefinitely.
+func SetTimeout(timeout time.Duration) {
+	files.SetTimeout(timeout)
+}
+
+// NewResolver returns an empty Resolver. With prefetch set, the first
+// lookup loads /etc/passwd and /etc/group in one go.
+func NewResolver(prefetch bool) *Resolver {
+	return &Resolver{
+		prefetch: prefetch,
+		users:    make(map[uint32]string),
+		groups:   make(map[uint32]string),
+	}
+}
+
+// SetTimeout bounds each name service lookup. Zero waits indefinitely.
+func (r *Resolver) SetTimeout(timeout time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timeout = timeout
+}
+
+// User returns the login name for uid.
+func (r *Resolver) User(uid uint32) string {
+	return r.resolve(r.users, uid, func(id string) (string, error) {
+		u, err := user.LookupId(id)
+		if err != nil {
+			return "", err
+		}
+		return u.Username, nil
+	})
+}
+
+// Group returns the group name for gid.
+func (r *Resolver) Group(gid uint32) string {
+	return r.resolve(r.groups, gid, func(id string) (string, error) {
+		g, err := user.LookupGroupId(id)
+		if err != nil {
+			return "", err
+		}
+		return g.Name, nil
+	})
+}
+
+func (r *Resolver) resolve(names map[uint32]string, id uint32, lookup func(string) (string, error)) string {
+	if r.prefetch {
+		r.once.Do(r.load)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if name, ok := names[id]; ok {
+		return name
+	}
+
+	// Misses and timeouts are cached too, so an id the name service does
+	// not know, or does not answer for, is only asked about once.
+	type result struct {
+		name string
+		err  error
+	}
+	found, ok := deadline.Run(r.timeout, func() result {
+		name, err := lookup(strconv.FormatUint(uint64(id), 10))
+		return result{name, err}
+	})
+
+	name := found.name
+	switch {
+	case !ok:
+		name = TimedOut
+	case found.err != nil || name == "":
+		name = Unknown
+	}
+	names[id] = name
+	return name
+}
+
+func (r *Resolver) load() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	readIDFile("/etc/passwd", r.users)
+	readIDFile("/etc/group", r.groups)
+}
+
+func readIDFile(path string, names map[uint32]string) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	parseIDFile(f, names)
+}
+
+// parseIDFile reads name:password:id:... lines, the layout shared by the
+// passwd and group files. Earlier entries win, as they do for getpwuid.
+func parseIDFile(rd io.Reader, names map[uint32]string) {
+	scanner := bufio.NewScanner(rd)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.SplitN(line, ":", 4)
+		if len(fields) < 3 || fields[0] == "" || strings.HasPrefix(fields[0], "+") || strings.HasPrefix(fields[0], "-") {
+			continue
+		}
+		id, err := strconv.ParseUint(fields[2], 10, 32)
+		if err != nil {
+			continue
+		}
+		if _, ok := names[uint32(id)]; !ok {
+			names[uint32(id)] = fields[0]
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package owner
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseIDFile(t *testing.T) {
+	content := `# comment
+root:x:0:0:root:/root:/bin/bash
+daemon:x:1:1::/usr/sbin:/usr/sbin/nologin
+
+toor:x:0:0:duplicate:/root:/bin/sh
++nisuser::::::
+broken:x:notanumber:0::/:/bin/sh
+staff:*:20:
+`
+	names := make(map[uint32]string)
+	parseIDFile(strings.NewReader(content), names)
+
+	expected := map[uint32]string{0: "root", 1: "daemon", 20: "staff"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("parseIDFile() = %v, want %v", names, expected)
+	}
+}
+
+func TestResolverCachesMisses(t *testing.T) {
+	r := NewResolver(false)
+	r.users[1000] = "ipan"
+
+	if got := r.User(1000); got != "ipan" {
+		t.Errorf("User(1000) = %q, want cached name", got)
+	}
+
+	calls := 0
+	lookup := func(string) (string, error) {
+		calls++
+		return "", errNotFound
+	}
+	for range 3 {
+		if got := r.resolve(r.groups, 4242, lookup); got != Unknown {
+			t.Errorf("resolve() = %q, want %q", got, Unknown)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("lookup called %d times, want 1", calls)
+	}
+}
+
+func TestResolverTimeout(t *testing.T) {
+	r := NewResolver(false)
+	r.SetTimeout(10 * time.Millisecond)
+
+	release := make(chan struct{})
+	defer close(release)
+	hang := func(string) (string, error) {
+		<-release
+		return "ldap", nil
+	}
+	if got := r.resolve(r.users, 5000, hang); got != TimedOut {
+		t.Errorf("resolve() = %q, want %q", got, TimedOut)
+	}
+	if got := r.users[5000]; got != TimedOut {
+		t.Errorf("timed out id cached as %q, want %q", got, TimedOut)
+	}
+}
+
+var errNotFound = errors.New("not found")
@@ -0,0 +1,41 @@
+package perms
+
+import "io/fs"
+
+// Explain describes an unusual mode in a few plain words, e.g. "setuid
+// root binary" or "world-writable directory without sticky bit", and
+// returns "" for everyday modes. owner is the name of the owning user,
+// used to single out files that run as root.
+func Explain(mode fs.FileMode, owner string) string {
+	perm := mode.Perm()
+	switch {
+	case mode&fs.ModeSymlink != 0, mode&fs.ModeType&^fs.ModeDir != 0:
+		return ""
+	case mode.IsDir():
+		switch {
+		case perm&0o002 != 0 && mode&fs.ModeSticky == 0:
+			return "world-writable directory without sticky bit"
+		case mode&fs.ModeSetgid != 0:
+			return "setgid directory, new files inherit its group"
+		case perm&0o500 != 0o500:
+			return "owner cannot list or enter"
+		}
+		return ""
+	}
+
+	switch {
+	case mode&fs.ModeSetuid != 0 && owner == "root":
+		return "setuid root binary"
+	case mode&fs.ModeSetuid != 0:
+		return "setuid binary, runs as its owner"
+	case mode&fs.ModeSetgid != 0:
+		return "setgid binary, runs with its group"
+	case perm&0o002 != 0:
+		return "world-writable file"
+	case perm == 0:
+		return "no permissions for anyone"
+	case perm&0o400 == 0 && perm&0o044 != 0:
+		return "others can read but the owner cannot"
+	}
+	return ""
+}
@@ -0,0 +1,128 @@
+package perms
+
+import (
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// Match selects entries by mode the way find -perm does, for --perm.
+type Match struct {
+	Mode fs.FileMode
+	// How is '=' for exactly Mode, '-' for at least the bits of Mode and
+	// '/' for any of them.
+	How byte
+}
+
+// ParseMatch reads a find-style mode: "4000" matches exactly that mode,
+// "-o+w" every mode with all of the bits set and "/6000" every mode with
+// any of them. Modes are octal or symbolic, e.g. "u+s" or "go=rx".
+func ParseMatch(spec string) (Match, error) {
+	m := Match{How: '='}
+	text := strings.TrimSpace(spec)
+	if strings.HasPrefix(text, "-") || strings.HasPrefix(text, "/") {
+		m.How, text = text[0], text[1:]
+	}
+	if text == "" {
+		return Match{}, fmt.Errorf("invalid --perm %q (use a mode such as 4000, -o+w or /6000)", spec)
+	}
+
+	if text[0] >= '0' && text[0] <= '9' {
+		value, err := strconv.ParseUint(text, 8, 32)
+		if err != nil || value > 0o7777 {
+			return Match{}, fmt.Errorf("invalid --perm mode %q (must be octal, e.g. 4000)", text)
+		}
+		m.Mode = fromOctal(uint32(value))
+		return m, nil
+	}
+
+	mode, err := parseSymbolic(text)
+	if err != nil {
+		return Match{}, fmt.Errorf("invalid --perm mode %q: %w", text, err)
+	}
+	m.Mode = fromOctal(mode)
+	return m, nil
+}
+
+// parseSymbolic applies chmod-style clauses such as "u+s,o+w" to a mode
+// of 0. A clause without users applies to all of them.
+func parseSymbolic(text string) (uint32, error) {
+	var mode uint32
+	for _, clause := range strings.Split(text, ",") {
+		who := strings.TrimLeft(clause, "ugoa")
+		users := clause[:len(clause)-len(who)]
+		if who == "" || !strings.ContainsRune("+-=", rune(who[0])) {
+			return 0, fmt.Errorf("%q needs an operator: +, - or =", clause)
+		}
+		op, letters := who[0], who[1:]
+
+		var mask uint32
+		if users == "" || strings.Contains(users, "a") {
+			users = "ugo"
+		}
+		for _, u := range users {
+			switch u {
+			case 'u':
+				mask |= 0o4700
+			case 'g':
+				mask |= 0o2070
+			case 'o':
+				mask |= 0o1007
+			}
+		}
+
+		var bits uint32
+		for _, l := range letters {
+			switch l {
+			case 'r':
+				bits |= 0o444
+			case 'w':
+				bits |= 0o222
+			case 'x':
+				bits |= 0o111
+			case 's':
+				bits |= 0o6000
+			case 't':
+				bits |= 0o1000
+			default:
+				return 0, fmt.Errorf("unknown permission %q in %q (use r, w, x, s or t)", l, clause)
+			}
+		}
+		bits &= mask
+
+		switch op {
+		case '+':
+			mode |= bits
+		case '-':
+			mode &^= bits
+		case '=':
+			mode = mode&^mask | bits
+		}
+	}
+	return mode, nil
+}
+
+// Matches reports whether mode is selected. Only the permission and
+// special bits are compared, so a directory and a file with the same bits
+// match alike.
+func (m Match) Matches(mode fs.FileMode) bool {
+	have := bits(mode)
+	switch m.How {
+	case '-':
+		return have&m.Mode == m.Mode
+	case '/':
+		// As in find, /000 asks for no bits at all and matches everything.
+		return m.Mode == 0 || have&m.Mode != 0
+	}
+	return have == m.Mode
+}
+
+// String formats m as ParseMatch accepts it, with an octal mode.
+func (m Match) String() string {
+	prefix := ""
+	if m.How != '=' {
+		prefix = string(m.How)
+	}
+	return prefix + Octal(m.Mode)
+}
@@ -0,0 +1,126 @@
+// Package perms checks file modes against the expectations given with
+// --expect-perms, e.g. "0644:files,0755:dirs".
+package perms
+
+import (
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// Rule expects Mode on every entry of a kind: "files", "dirs" or "all".
+type Rule struct {
+	Mode fs.FileMode
+	Kind string
+}
+
+// Expectations are the parsed rules of one --expect-perms value.
+type Expectations []Rule
+
+// kinds maps the accepted kind names to their canonical form.
+var kinds = map[string]string{
+	"":      "all",
+	"all":   "all",
+	"file":  "files",
+	"files": "files",
+	"dir":   "dirs",
+	"dirs":  "dirs",
+}
+
+// Parse reads comma-separated MODE[:KIND] rules. Modes are octal and may
+// include the setuid, setgid and sticky digit, e.g. 2775.
+func Parse(spec string) (Expectations, error) {
+	var rules Expectations
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		modeText, kindText, _ := strings.Cut(part, ":")
+		kind, ok := kinds[strings.ToLower(strings.TrimSpace(kindText))]
+		if !ok {
+			return nil, fmt.Errorf("invalid kind %q in %q (must be files, dirs or all)", kindText, part)
+		}
+		value, err := strconv.ParseUint(strings.TrimSpace(modeText), 8, 32)
+		if err != nil || value > 0o7777 {
+			return nil, fmt.Errorf("invalid mode %q in %q (must be octal, e.g. 0644)", modeText, part)
+		}
+		rules = append(rules, Rule{Mode: fromOctal(uint32(value)), Kind: kind})
+	}
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("no rules in %q", spec)
+	}
+	return rules, nil
+}
+
+// Expected returns the mode a rule expects for an entry with mode. A rule
+// for the entry's own kind wins over an "all" rule. Symlinks, devices and
+// other special files are never checked.
+func (e Expectations) Expected(mode fs.FileMode) (fs.FileMode, bool) {
+	var kind string
+	switch {
+	case mode.IsDir():
+		kind = "dirs"
+	case mode.IsRegular():
+		kind = "files"
+	default:
+		return 0, false
+	}
+
+	var expected fs.FileMode
+	found := false
+	for _, rule := range e {
+		if rule.Kind == kind {
+			return rule.Mode, true
+		}
+		if rule.Kind == "all" && !found {
+			expected, found = rule.Mode, true
+		}
+	}
+	return expected, found
+}
+
+// Check returns the expected mode, formatted like Octal, when mode
+// deviates from it, and "" when it matches or no rule applies.
+func (e Expectations) Check(mode fs.FileMode) string {
+	expected, ok := e.Expected(mode)
+	if !ok || bits(mode) == expected {
+		return ""
+	}
+	return Octal(expected)
+}
+
+// Octal formats the permission and special bits of mode as four octal
+// digits, e.g. "0644" or "2775".
+func Octal(mode fs.FileMode) string {
+	value := uint32(mode.Perm())
+	if mode&fs.ModeSetuid != 0 {
+		value |= 0o4000
+	}
+	if mode&fs.ModeSetgid != 0 {
+		value |= 0o2000
+	}
+	if mode&fs.ModeSticky != 0 {
+		value |= 0o1000
+	}
+	return fmt.Sprintf("%04o", value)
+}
+
+func bits(mode fs.FileMode) fs.FileMode {
+	return mode & (fs.ModePerm | fs.ModeSetuid | fs.ModeSetgid | fs.ModeSticky)
+}
+
+func fromOctal(value uint32) fs.FileMode {
+	mode := fs.FileMode(value & 0o777)
+	if value&0o4000 != 0 {
+		mode |= fs.ModeSetuid
+	}
+	if value&0o2000 != 0 {
+		mode |= fs.ModeSetgid
+	}
+	if value&0o1000 != 0 {
+		mode |= fs.ModeSticky
+	}
+	return mode
+}
@@ -0,0 +1,113 @@
+package perms
+
+import (
+	"io/fs"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	rules, err := Parse("0644:files, 0755:dirs,2775")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 3 || rules[0].Kind != "files" || rules[1].Mode != 0o755 || rules[2].Kind != "all" || rules[2].Mode != fs.ModeSetgid|0o775 {
+		t.Errorf("Parse() = %+v", rules)
+	}
+
+	for _, spec := range []string{"", "0644:links", "rw-r--r--:files", "17777"} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) accepted an invalid spec", spec)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	rules, _ := Parse("0600:all,0755:dirs")
+
+	tests := []struct {
+		name     string
+		mode     fs.FileMode
+		expected string
+	}{
+		{name: "file matches", mode: 0o600, expected: ""},
+		{name: "file deviates", mode: 0o644, expected: "0600"},
+		{name: "dir rule wins over all", mode: fs.ModeDir | 0o755, expected: ""},
+		{name: "setgid dir deviates", mode: fs.ModeDir | fs.ModeSetgid | 0o755, expected: "0755"},
+		{name: "symlink skipped", mode: fs.ModeSymlink | 0o777, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rules.Check(tt.mode); got != tt.expected {
+				t.Errorf("Check(%v) = %q, want %q", tt.mode, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     fs.FileMode
+		owner    string
+		expected string
+	}{
+		{name: "plain file", mode: 0o644, expected: ""},
+		{name: "plain dir", mode: fs.ModeDir | 0o755, expected: ""},
+		{name: "setuid root", mode: fs.ModeSetuid | 0o755, owner: "root", expected: "setuid root binary"},
+		{name: "setuid user", mode: fs.ModeSetuid | 0o755, owner: "alice", expected: "setuid binary, runs as its owner"},
+		{name: "world-writable dir", mode: fs.ModeDir | 0o777, expected: "world-writable directory without sticky bit"},
+		{name: "tmp-style dir", mode: fs.ModeDir | fs.ModeSticky | 0o777, expected: ""},
+		{name: "world-writable file", mode: 0o666, expected: "world-writable file"},
+		{name: "symlink skipped", mode: fs.ModeSymlink | 0o777, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Explain(tt.mode, tt.owner); got != tt.expected {
+				t.Errorf("Explain(%v) = %q, want %q", tt.mode, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		spec string
+		mode fs.FileMode
+		want bool
+	}{
+		{"4000", fs.ModeSetuid, true},
+		{"4000", fs.ModeSetuid | 0o755, false},
+		{"-4000", fs.ModeSetuid | 0o755, true},
+		{"-o+w", 0o666, true},
+		{"-o+w", 0o664, false},
+		{"-o+w", fs.ModeDir | 0o777, true},
+		{"/6000", fs.ModeSetgid | 0o755, true},
+		{"/6000", 0o755, false},
+		{"/u+s,g+s", fs.ModeSetgid | 0o755, true},
+		{"-go+w", 0o646, false},
+		{"-go+w", 0o666, true},
+		{"g=w", 0o020, true},
+		{"u=rwx,go=rx", 0o755, true},
+		{"a+rw,o-w", 0o664, true},
+		{"+t", fs.ModeSticky, true},
+		{"/000", 0o600, true},
+	}
+	for _, tt := range tests {
+		m, err := ParseMatch(tt.spec)
+		if err != nil {
+			t.Errorf("ParseMatch(%q): %v", tt.spec, err)
+			continue
+		}
+		if got := m.Matches(tt.mode); got != tt.want {
+			t.Errorf("ParseMatch(%q).Matches(%v) = %v, want %v", tt.spec, tt.mode, got, tt.want)
+		}
+	}
+
+	for _, spec := range []string{"", "-", "18", "17777", "o+q", "ow"} {
+		if _, err := ParseMatch(spec); err == nil {
+			t.Errorf("ParseMatch(%q) accepted an invalid mode", spec)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// Package portable detects file names that cause trouble when a tree is
+// moved between operating systems.
+package portable
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ipanardian/lu-hut/internal/model"
+	"golang.org/x/text/unicode/norm"
+)
+
+// CaseConflicts groups names that only differ by case, such as README.md
+// and Readme.md. They coexist on case-sensitive filesystems but collide on
+// macOS and Windows checkouts. Folding is per character, like those
+// filesystems, so "ß" and "SS" are not considered equal. Each group and the
+// group list are sorted.
+func CaseConflicts(names []string) [][]string {
+	byKey := make(map[string][]string, len(names))
+	for _, name := range names {
+		key := strings.ToLower(name)
+		byKey[key] = append(byKey[key], name)
+	}
+
+	var groups [][]string
+	for _, group := range byKey {
+		if len(group) > 1 {
+			sort.Strings(group)
+			groups = append(groups, group)
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i][0] < groups[j][0] })
+	return groups
+}
+
+// MarkCaseConflicts sets CaseConflict on every entry whose name collides
+// with another entry in the same directory and returns the groups.
+func MarkCaseConflicts(files []model.FileEntry) [][]string {
+	names := make([]string, len(files))
+	for i, file := range files {
+		names[i] = file.Name
+	}
+
+	groups := CaseConflicts(names)
+	if len(groups) == 0 {
+		return nil
+	}
+
+	conflicting := make(map[string]bool)
+	for _, group := range groups {
+		for _, name := range group {
+			conflicting[name] = true
+		}
+	}
+	for i := range files {
+		files[i].CaseConflict = conflicting[files[i].Name]
+	}
+	return groups
+}
+
+// CheckName reports why a name is risky to display or share: bidirectional
+// control characters can make a name render as something else, invisible
+// characters make look-alike names, and names that are not NFC-normalized
+// are stored differently by macOS and Linux. It returns "" for clean names.
+func CheckName(name string) string {
+	for _, r := range name {
+		if isBidiControl(r) {
+			return fmt.Sprintf("bidi control U+%04X", r)
+		}
+	}
+	for _, r := range name {
+		if isInvisible(r) {
+			return fmt.Sprintf("invisible U+%04X", r)
+		}
+	}
+	if !norm.NFC.IsNormalString(name) {
+		return "not NFC"
+	}
+	return ""
+}
+
+// Visible replaces bidi controls and invisible characters with U+FFFD so a
+// name cannot reorder or hide parts of the surrounding output. The result is
+// NFC-normalized, which looks the same but keeps column widths right.
+func Visible(name string) string {
+	return norm.NFC.String(strings.Map(func(r rune) rune {
+		if isBidiControl(r) || isInvisible(r) {
+			return utf8.RuneError
+		}
+		return r
+	}, name))
+}
+
+// MarkNameProblems sets NameProblem on every entry with a risky name and
+// returns how many were found.
+func MarkNameProblems(files []model.FileEntry) int {
+	count := 0
+	for i := range files {
+		files[i].NameProblem = CheckName(files[i].Name)
+		if files[i].NameProblem != "" {
+			count++
+		}
+	}
+	return count
+}
+
+func isBidiControl(r rune) bool {
+	return unicode.Is(unicode.Bidi_Control, r)
+}
+
+// isInvisible also covers the Hangul fillers, which are letters but render
+// as blank space.
+func isInvisible(r rune) bool {
+	return unicode.Is(unicode.Cf, r) || unicode.IsControl(r) || r == '\u3164' || r == '\u115f' || r == '\u1160'
+}
@@ -0,0 +1,79 @@
+package portable
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ipanardian/lu-hut/internal/model"
+)
+
+func TestCaseConflicts(t *testing.T) {
+	tests := []struct {
+		name     string
+		names    []string
+		expected [][]string
+	}{
+		{name: "no conflicts", names: []string{"a.go", "b.go", "README.md"}, expected: nil},
+		{name: "pair", names: []string{"Readme.md", "main.go", "README.md"}, expected: [][]string{{"README.md", "Readme.md"}}},
+		{name: "triple", names: []string{"x", "X", "x.go", "X"}, expected: [][]string{{"X", "X", "x"}}},
+		{
+			name:     "multiple groups sorted",
+			names:    []string{"makefile", "b", "Makefile", "B"},
+			expected: [][]string{{"B", "b"}, {"Makefile", "makefile"}},
+		},
+		{name: "unicode folding", names: []string{"STRASSE", "straße", "Straße"}, expected: [][]string{{"Straße", "straße"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CaseConflicts(tt.names)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("CaseConflicts(%v) = %v, want %v", tt.names, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheckName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "ascii", input: "main.go", expected: ""},
+		{name: "nfc accent", input: "café.txt", expected: ""},
+		{name: "nfd accent", input: "cafe\u0301.txt", expected: "not NFC"},
+		{name: "right-to-left override", input: "invoice\u202etxt.exe", expected: "bidi control U+202E"},
+		{name: "zero width space", input: "pass\u200bwd", expected: "invisible U+200B"},
+		{name: "byte order mark", input: "\ufeffnotes", expected: "invisible U+FEFF"},
+		{name: "control character", input: "bad\x07name", expected: "invisible U+0007"},
+		{name: "emoji", input: "🚀.md", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := CheckName(tt.input); result != tt.expected {
+				t.Errorf("CheckName(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestVisible(t *testing.T) {
+	if result := Visible("invoice\u202etxt.exe"); result != "invoice�txt.exe" {
+		t.Errorf("Visible() = %q", result)
+	}
+	if result := Visible("cafe\u0301"); result != "caf\u00e9" {
+		t.Errorf("Visible() should compose combining marks, got %q", result)
+	}
+}
+
+func TestMarkNameProblems(t *testing.T) {
+	files := []model.FileEntry{{Name: "ok.go"}, {Name: "a\u200bb"}, {Name: "cafe\u0301"}}
+	if count := MarkNameProblems(files); count != 2 {
+		t.Errorf("MarkNameProblems() = %d, want 2", count)
+	}
+	if files[0].NameProblem != "" || files[1].NameProblem == "" || files[2].NameProblem == "" {
+		t.Errorf("unexpected marks: %+v", files)
+	}
+}
@@ -0,0 +1,126 @@
+// Package project detects well-known project manifests in a directory.
+package project
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+type Info struct {
+	Kind     string
+	Manifest string
+	Name     string
+	Version  string
+}
+
+type detector struct {
+	manifest string
+	kind     string
+	parse    func(path string) (name, version string, ok bool)
+}
+
+var detectors = []detector{
+	{"go.mod", "go", parseGoMod},
+	{"Cargo.toml", "rust", parseCargo},
+	{"package.json", "node", parseJSONManifest},
+	{"pyproject.toml", "python", parsePyProject},
+	{"composer.json", "php", parseJSONManifest},
+}
+
+// Detect returns the first recognized manifest in dir. Manifests are checked
+// in a fixed order so mixed projects report consistently.
+func Detect(dir string) (Info, bool) {
+	for _, d := range detectors {
+		path := filepath.Join(dir, d.manifest)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+
+		name, version, ok := d.parse(path)
+		if !ok {
+			continue
+		}
+		return Info{Kind: d.kind, Manifest: d.manifest, Name: name, Version: version}, true
+	}
+	return Info{}, false
+}
+
+func parseGoMod(path string) (string, string, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", "", false
+	}
+	defer f.Close()
+
+	var module, goVersion string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "module":
+			module = strings.Trim(fields[1], `"`)
+		case "go":
+			goVersion = "go " + fields[1]
+		}
+	}
+
+	return module, goVersion, module != ""
+}
+
+func parseJSONManifest(path string) (string, string, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", false
+	}
+
+	var manifest struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return "", "", false
+	}
+	return manifest.Name, manifest.Version, manifest.Name != ""
+}
+
+func parseCargo(path string) (string, string, bool) {
+	var manifest struct {
+		Package struct {
+			Name    string `toml:"name"`
+			Version string `toml:"version"`
+		} `toml:"package"`
+	}
+	if _, err := toml.DecodeFile(path, &manifest); err != nil {
+		return "", "", false
+	}
+	return manifest.Package.Name, manifest.Package.Version, manifest.Package.Name != ""
+}
+
+func parsePyProject(path string) (string, string, bool) {
+	type pkg struct {
+		Name    string `toml:"name"`
+		Version string `toml:"version"`
+	}
+	var manifest struct {
+		Project pkg `toml:"project"`
+		Tool    struct {
+			Poetry pkg `toml:"poetry"`
+		} `toml:"tool"`
+	}
+	if _, err := toml.DecodeFile(path, &manifest); err != nil {
+		return "", "", false
+	}
+
+	if manifest.Project.Name != "" {
+		return manifest.Project.Name, manifest.Project.Version, true
+	}
+	return manifest.Tool.Poetry.Name, manifest.Tool.Poetry.Version, manifest.Tool.Poetry.Name != ""
+}
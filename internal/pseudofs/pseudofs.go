@@ -0,0 +1,9 @@
+// Package pseudofs recognizes kernel pseudo-filesystems such as /proc and
+// /sys, whose files report meaningless sizes and can block when read.
+package pseudofs
+
+// Is reports whether path lives on a kernel pseudo-filesystem. It always
+// returns false on platforms without such filesystems.
+func Is(path string) bool {
+	return isPseudo(path)
+}
@@ -0,0 +1,28 @@
+package pseudofs
+
+import "syscall"
+
+// Filesystem magic numbers from linux/magic.h.
+var magics = map[int64]bool{
+	0x9fa0:     true, // proc
+	0x62656572: true, // sysfs
+	0x64626720: true, // debugfs
+	0x74726163: true, // tracefs
+	0x73636673: true, // securityfs
+	0x27e0eb:   true, // cgroup
+	0x63677270: true, // cgroup2
+	0x62656570: true, // configfs
+	0xcafe4a11: true, // bpf
+	0x6e736673: true, // nsfs
+	0x42494e4d: true, // binfmt_misc
+	0xde5e81e4: true, // efivarfs
+	0x50495045: true, // pipefs
+}
+
+func isPseudo(path string) bool {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return false
+	}
+	return magics[int64(st.Type)]
+}
@@ -0,0 +1,7 @@
+//go:build !linux
+
+package pseudofs
+
+func isPseudo(string) bool {
+	return false
+}
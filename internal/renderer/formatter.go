@@ -3,40 +3,54 @@ package renderer
 import (
 	"fmt"
 	"io/fs"
+	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 	"unicode/utf8"
 
 	"github.com/fatih/color"
+	"github.com/ipanardian/lu-hut/internal/entropy"
+	"github.com/ipanardian/lu-hut/internal/icons"
+	"github.com/ipanardian/lu-hut/internal/lscolors"
 	"github.com/ipanardian/lu-hut/internal/model"
+	"github.com/ipanardian/lu-hut/internal/portable"
+	"github.com/ipanardian/lu-hut/internal/theme"
 	"github.com/ipanardian/lu-hut/pkg/helper"
 	"golang.org/x/term"
 )
 
 func getTerminalWidth() int {
+	width, _ := TerminalWidth()
+	return width - 10
+}
+
+// TerminalWidth returns the number of columns available and where that
+// number came from: COLUMNS, the terminal itself, tput, or a fallback.
+func TerminalWidth() (int, string) {
 	if width := os.Getenv("COLUMNS"); width != "" {
 		if w, err := strconv.Atoi(width); err == nil && w > 0 {
-			return w - 10
+			return w, "COLUMNS"
 		}
 	}
 
 	if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && width > 0 {
-		return width - 10
+		return width, "terminal"
 	}
 
 	if cmd := exec.Command("tput", "cols"); cmd != nil {
 		if output, err := cmd.Output(); err == nil {
 			if w, err := strconv.Atoi(strings.TrimSpace(string(output))); err == nil && w > 0 {
-				return w - 10
+				return w, "tput"
 			}
 		}
 	}
 
-	return 70
+	return 80, "fallback"
 }
 
 func calculateDisplayWidths(data [][]string) []int {
@@ -78,7 +92,7 @@ func truncateMiddle(s string, max int) string {
 		return s
 	}
 	if max == 1 {
-		return "…"
+		return ellipsis
 	}
 
 	runes := []rune(s)
@@ -93,7 +107,7 @@ func truncateMiddle(s string, max int) string {
 		head = max - 1
 	}
 
-	return string(runes[:head]) + "…" + string(runes[len(runes)-tail:])
+	return string(runes[:head]) + ellipsis + string(runes[len(runes)-tail:])
 }
 
 func truncateTail(s string, max int) string {
@@ -104,11 +118,11 @@ func truncateTail(s string, max int) string {
 		return s
 	}
 	if max == 1 {
-		return "…"
+		return ellipsis
 	}
 
 	runes := []rune(s)
-	return "…" + string(runes[len(runes)-(max-1):])
+	return ellipsis + string(runes[len(runes)-(max-1):])
 }
 
 const defaultNameMaxWidth = 50
@@ -152,53 +166,291 @@ func truncateSymlinkParts(name, target string, maxWidth int) (string, string) {
 func formatName(file model.FileEntry, maxWidth int) string {
 	originalName := file.Name
 	name := originalName
+	if file.NameProblem != "" {
+		name = portable.Visible(name)
+	}
 	if maxWidth <= 0 {
 		maxWidth = defaultNameMaxWidth
 	}
 
+	if file.Target != "" {
+		return formatNameWithTarget(file, name, file.Target, maxWidth)
+	}
+
 	if file.Mode&fs.ModeSymlink != 0 {
 		if target, err := os.Readlink(file.Path); err == nil {
-			truncName, truncTarget := truncateSymlinkParts(name, target, maxWidth)
-			if truncTarget == "" {
-				return color.New(color.FgMagenta, color.Bold).Sprint(truncName)
-			}
-			return color.New(color.FgMagenta, color.Bold).Sprint(truncName) + " -> " + color.New(color.FgHiBlack).Sprint(truncTarget)
+			return formatNameWithTarget(file, name, target, maxWidth)
+		}
+		return nameColor(file).Sprint(truncateMiddle(name, maxWidth))
+	}
+
+	return nameColor(file).Sprint(truncateMiddle(name, maxWidth))
+}
+
+// FormatName styles and truncates a name the way the table does, for other
+// front ends such as the interactive picker.
+func FormatName(file model.FileEntry, maxWidth int) string {
+	return formatName(file, maxWidth)
+}
+
+func formatNameWithTarget(file model.FileEntry, name, target string, maxWidth int) string {
+	truncName, truncTarget := truncateSymlinkParts(name, target, maxWidth)
+	if truncTarget == "" {
+		return nameColor(file).Sprint(truncName)
+	}
+	targetColor := activeTheme.Names.Target.Color()
+	if file.BrokenLink {
+		targetColor = activeTheme.Names.Broken.Color()
+	}
+	return nameColor(file).Sprint(truncName) + " -> " + targetColor.Sprint(truncTarget)
+}
+
+var (
+	activeTheme = theme.Default()
+	lsColors    *lscolors.Palette
+	colorSpec   *lscolors.Palette
+)
+
+// SetTheme switches the colors used by all renderers.
+func SetTheme(t *theme.Theme) {
+	activeTheme = t
+}
+
+// SetLSColors makes name coloring follow an LS_COLORS palette instead of the
+// built-in extension colors. Passing nil restores the defaults.
+func SetLSColors(p *lscolors.Palette) {
+	lsColors = p
+}
+
+// SetColorSpec layers a partial LS_COLORS-style palette over the theme and
+// LS_COLORS, for the keys it sets. Passing nil removes it.
+func SetColorSpec(p *lscolors.Palette) {
+	colorSpec = p
+}
+
+func nameColor(file model.FileEntry) *color.Color {
+	c := baseNameColor(file)
+	if file.Highlighted {
+		c.Add(activeTheme.Highlight...)
+	}
+	return c
+}
+
+func baseNameColor(file model.FileEntry) *color.Color {
+	if style, ok := activeTheme.Match(file.Name); ok {
+		return style.Color()
+	}
+
+	if c, ok := colorSpec.Lookup(file); ok {
+		return c
+	}
+
+	if lsColors == nil && file.BrokenLink {
+		return activeTheme.Names.Broken.Color()
+	}
+
+	if file.Generated {
+		return activeTheme.Names.Generated.Color()
+	}
+
+	if file.GitStatus == "I" {
+		return activeTheme.Muted.Color()
+	}
+
+	if lsColors != nil {
+		if c, ok := lsColors.Lookup(file); ok {
+			return c
 		}
-		return color.New(color.FgMagenta, color.Bold).Sprint(truncateMiddle(name, maxWidth))
+		return color.New(color.Reset)
 	}
 
-	name = truncateMiddle(name, maxWidth)
+	if file.Mode&fs.ModeSymlink != 0 {
+		return activeTheme.Names.Symlink.Color()
+	}
 
 	if file.IsDir {
-		return color.New(color.FgBlue, color.Bold).Sprint(name)
+		return activeTheme.Names.Directory.Color()
 	}
 
 	if file.Mode.Perm()&0111 != 0 {
-		return color.New(color.FgRed).Sprint(name)
+		return activeTheme.Names.Executable.Color()
 	}
 
 	if file.IsHidden {
-		return color.New(color.FgYellow).Sprint(name)
+		return activeTheme.Names.Hidden.Color()
 	}
 
-	ext := strings.ToLower(filepath.Ext(originalName))
-	switch ext {
-	case ".go", ".rs", ".py", ".js", ".ts", ".jsx", ".tsx":
-		return color.New(color.FgGreen).Sprint(name)
-	case ".md", ".txt", ".rst":
-		return color.New(color.FgYellow).Sprint(name)
-	case ".yml", ".yaml", ".json", ".toml", ".ini":
-		return color.New(color.FgMagenta).Sprint(name)
-	default:
-		return color.New(color.FgWhite).Sprint(name)
+	return activeTheme.Extension(strings.ToLower(filepath.Ext(file.Name))).Color()
+}
+
+const iconWidth = 2
+
+var (
+	ellipsis          = "…"
+	originArrow       = "←"
+	caseConflictBadge = " ⚠"
+	nameProblemBadge  = " ‽"
+	brokenLinkBadge   = " ⨯"
+	moreMark          = "…"
+	upstreamArrow     = "→"
+	aheadMark         = "⇡"
+	behindMark        = "⇣"
+	headerSeparator   = "·"
+	statusBadges      bool
+)
+
+// bigFileSize is the size from which files get the [big] status badge.
+const bigFileSize = 100 << 20
+
+// SetStatusBadges adds textual markers such as [exec] or [M] next to names,
+// so information otherwise carried only by color survives copy and paste.
+func SetStatusBadges(enabled bool) {
+	statusBadges = enabled
+}
+
+// SetASCII replaces the ellipsis and badges with plain ASCII for terminals
+// and logs that mangle Unicode.
+func SetASCII(enabled bool) {
+	if enabled {
+		ellipsis, caseConflictBadge, nameProblemBadge, brokenLinkBadge = "~", " !", " ?", " x"
+		originArrow, moreMark = "<-", "..."
+		upstreamArrow, aheadMark, behindMark, headerSeparator = "->", "+", "-", "|"
+	} else {
+		ellipsis, caseConflictBadge, nameProblemBadge, brokenLinkBadge = "…", " ⚠", " ‽", " ⨯"
+		originArrow, moreMark = "←", "…"
+		upstreamArrow, aheadMark, behindMark, headerSeparator = "→", "⇡", "⇣", "·"
 	}
 }
 
+// formatMore tells how many entries --limit left out.
+func formatMore(more int) string {
+	return activeTheme.Muted.Color().Sprintf("%s and %d more", moreMark, more)
+}
+
+func statusBadgeText(file model.FileEntry) string {
+	if !statusBadges {
+		return ""
+	}
+
+	var b string
+	switch {
+	case file.BrokenLink:
+		b += " [broken]"
+	case file.Mode&fs.ModeSymlink != 0:
+		b += " [link]"
+	case file.IsDir:
+		b += " [dir]"
+	case file.Mode.Perm()&0111 != 0:
+		b += " [exec]"
+	}
+	if !file.IsDir && file.Size >= bigFileSize {
+		b += " [big]"
+	}
+	if status := strings.TrimSpace(file.GitStatus); status != "" {
+		b += " [" + status + "]"
+	}
+	return b
+}
+
+func brokenBadgeText(file model.FileEntry) string {
+	if file.BrokenLink {
+		return brokenLinkBadge
+	}
+	return ""
+}
+
+func warningBadgeText(file model.FileEntry) string {
+	var b string
+	if file.CaseConflict {
+		b += caseConflictBadge
+	}
+	if file.NameProblem != "" {
+		b += nameProblemBadge
+	}
+	return b
+}
+
+func formatBadges(file model.FileEntry) string {
+	var b string
+	if broken := brokenBadgeText(file); broken != "" {
+		b += activeTheme.Names.Broken.Color().Sprint(broken)
+	}
+	if status := statusBadgeText(file); status != "" {
+		b += activeTheme.Muted.Color().Sprint(status)
+	}
+	if warnings := warningBadgeText(file); warnings != "" {
+		b += activeTheme.Warning.Color().Sprint(warnings)
+	}
+	return b
+}
+
+// nameWidthFor leaves room for the badges that follow a name.
+func nameWidthFor(file model.FileEntry, width int) int {
+	return width - runeCount(brokenBadgeText(file)+statusBadgeText(file)+warningBadgeText(file))
+}
+
+func formatIcon(file model.FileEntry, set *icons.Set) string {
+	if set == nil {
+		return ""
+	}
+	return nameColor(file).Sprint(set.Lookup(file)) + " "
+}
+
+// formatEntrySize is formatSize for a listed entry, showing "?" for a
+// directory whose --du total was cut short by the timeout.
+func formatEntrySize(file model.FileEntry, isDir bool) string {
+	if file.SizeUnknown {
+		return activeTheme.DirSize.Color().Sprint(alignSize("?", ""))
+	}
+	return formatSize(file.Size, isDir)
+}
+
 func formatSize(size int64, isDir bool) string {
 	if isDir {
-		return color.New(color.FgCyan).Sprint("-")
+		return activeTheme.DirSize.Color().Sprint(alignSize("-", ""))
 	}
 
+	number, unit, _ := strings.Cut(humanSize(size), " ")
+	result := alignSize(number, unit)
+	if size < 1024 {
+		if style, ok := activeTheme.SizeStyle(size); ok {
+			return style.Color().Sprint(result)
+		}
+		if useGradient() {
+			return sizeGradient(size).Sprint(result)
+		}
+		return result
+	}
+
+	if style, ok := activeTheme.SizeStyle(size); ok {
+		return style.Color().Sprint(result)
+	}
+	if useGradient() {
+		return sizeGradient(size).Sprint(result)
+	}
+	return activeTheme.Size.Color().Sprint(result)
+}
+
+// alignUnits right-aligns size numbers and lines up their units, so sizes
+// can be compared at a glance down the column.
+var alignUnits bool
+
+// SetAlignUnits turns aligned sizes in the Size column on or off.
+func SetAlignUnits(enabled bool) {
+	alignUnits = enabled
+}
+
+// alignSize joins a size's number and unit, padded to a fixed width when
+// units are aligned. The widest number humanSize produces is "1023.9".
+func alignSize(number, unit string) string {
+	if !alignUnits {
+		return strings.TrimSpace(number + " " + unit)
+	}
+	return fmt.Sprintf("%6s %-2s", number, unit)
+}
+
+// humanSize renders size with binary units, e.g. "512 B" or "1.5 MB".
+func humanSize(size int64) string {
 	const unit = 1024
 	if size < unit {
 		return fmt.Sprintf("%d B", size)
@@ -213,14 +465,34 @@ func formatSize(size int64, isDir bool) string {
 	if exp >= len(units) {
 		exp = len(units) - 1
 	}
-	result := fmt.Sprintf("%.1f %s", float64(size)/float64(div), units[exp])
+	return fmt.Sprintf("%.1f %s", float64(size)/float64(div), units[exp])
+}
 
-	return color.New(color.FgHiWhite).Sprint(result)
+// timeHeader names the time column after the timestamp --time selects.
+func timeHeader(field string) string {
+	switch field {
+	case "accessed":
+		return "Accessed"
+	case "created":
+		return "Created"
+	}
+	return "Modified"
+}
+
+// shownTime picks the timestamp --time selects for the time column.
+func shownTime(file model.FileEntry, field string) time.Time {
+	switch field {
+	case "accessed":
+		return file.Accessed
+	case "created":
+		return file.Created
+	}
+	return file.ModTime
 }
 
 func formatModified(t time.Time, now time.Time, showExact bool) string {
 	if showExact {
-		c := color.New(color.FgHiWhite)
+		c := activeTheme.Age.Exact.Color()
 		return c.Sprint(t.Format("Jan 2, 06 15:04"))
 	}
 
@@ -230,60 +502,102 @@ func formatModified(t time.Time, now time.Time, showExact bool) string {
 	var text string
 
 	if duration < 0 {
-		c = color.New(color.FgBlue)
+		c = activeTheme.Age.Future.Color()
 		text = "future"
 	} else if duration < time.Minute {
-		c = color.New(color.FgGreen)
+		c = activeTheme.Age.Seconds.Color()
 		text = fmt.Sprintf("%d seconds ago", int(duration.Seconds()))
 	} else if duration < time.Hour {
-		c = color.New(color.FgGreen)
+		c = activeTheme.Age.Minutes.Color()
 		text = fmt.Sprintf("%d minutes ago", int(duration.Minutes()))
 	} else if duration < 24*time.Hour {
-		c = color.New(color.FgYellow)
+		c = activeTheme.Age.Hours.Color()
 		text = fmt.Sprintf("%d hours ago", int(duration.Hours()))
 	} else if duration < 7*24*time.Hour {
-		c = color.New(color.FgHiYellow)
+		c = activeTheme.Age.Days.Color()
 		text = fmt.Sprintf("%d days ago", int(duration.Hours()/24))
 	} else if duration < 30*24*time.Hour {
-		c = color.New(color.FgRed)
+		c = activeTheme.Age.Weeks.Color()
 		text = fmt.Sprintf("%d weeks ago", int(duration.Hours()/(24*7)))
 	} else if duration < 365*24*time.Hour {
-		c = color.New(color.FgHiRed)
+		c = activeTheme.Age.Months.Color()
 		text = fmt.Sprintf("%d months ago", int(duration.Hours()/(24*30)))
 	} else {
-		c = color.New(color.FgHiBlack)
+		c = activeTheme.Age.Years.Color()
 		text = fmt.Sprintf("%d years ago", int(duration.Hours()/(24*365)))
 	}
 
+	if style, ok := activeTheme.AgeStyle(duration); ok && duration >= 0 {
+		c = style.Color()
+	} else if useGradient() && duration >= 0 {
+		c = ageGradient(duration)
+	}
+
 	return c.Sprint(text)
 }
 
+// formatEntryPermissions is formatPermissions for a listed entry, drawn in
+// the warning style when the mode differs from --expect-perms.
+func formatEntryPermissions(file model.FileEntry, useOctal bool) string {
+	if file.WantPerms != "" {
+		return activeTheme.Warning.Color().Sprint(helper.StripANSI(formatPermissions(file.Mode, useOctal)))
+	}
+	return formatPermissions(file.Mode, useOctal)
+}
+
+// formatPermNote draws an --explain-perms description in the warning style.
+func formatPermNote(note string) string {
+	if note == "" {
+		return ""
+	}
+	return activeTheme.Warning.Color().Sprint(note)
+}
+
+// formatGrowth shows how fast an entry grew between two --watch refreshes,
+// "-" until there was a previous one. Growth of at least alert bytes per
+// second stands out; an alert of 0 never does.
+func formatGrowth(file model.FileEntry, alert int64) string {
+	muted := activeTheme.Muted.Color()
+	rate := int64(math.Round(file.Growth))
+	switch {
+	case !file.HasGrowth || file.IsDir:
+		return muted.Sprint("-")
+	case rate == 0:
+		return muted.Sprint("0 B/s")
+	case rate < 0:
+		return "-" + humanSize(-rate) + "/s"
+	case alert > 0 && rate >= alert:
+		return activeTheme.Warning.Color().Add(color.Bold).Sprint("+" + humanSize(rate) + "/s")
+	}
+	return "+" + humanSize(rate) + "/s"
+}
+
 func formatPermissions(mode fs.FileMode, useOctal bool) string {
 	perm := mode.Perm()
 
 	if useOctal {
-		return color.New(color.FgHiWhite).Sprint(fmt.Sprintf("%04o", perm))
+		return activeTheme.Permissions.Octal.Color().Sprint(fmt.Sprintf("%04o", perm))
 	}
 
 	var result strings.Builder
 
 	switch {
 	case mode&fs.ModeDir != 0:
-		result.WriteString(color.New(color.FgCyan, color.Bold).Sprint("d"))
+		result.WriteString(activeTheme.Permissions.Directory.Color().Sprint("d"))
 	case mode&fs.ModeSymlink != 0:
-		result.WriteString(color.New(color.FgMagenta, color.Bold).Sprint("l"))
+		result.WriteString(activeTheme.Permissions.Symlink.Color().Sprint("l"))
 	case mode&fs.ModeDevice != 0:
 		if mode&fs.ModeCharDevice != 0 {
-			result.WriteString(color.New(color.FgYellow, color.Bold).Sprint("c"))
+			result.WriteString(activeTheme.Permissions.Device.Color().Sprint("c"))
 		} else {
-			result.WriteString(color.New(color.FgYellow, color.Bold).Sprint("b"))
+			result.WriteString(activeTheme.Permissions.Device.Color().Sprint("b"))
 		}
 	case mode&fs.ModeNamedPipe != 0:
-		result.WriteString(color.New(color.FgYellow, color.Bold).Sprint("p"))
+		result.WriteString(activeTheme.Permissions.Device.Color().Sprint("p"))
 	case mode&fs.ModeSocket != 0:
-		result.WriteString(color.New(color.FgYellow, color.Bold).Sprint("s"))
+		result.WriteString(activeTheme.Permissions.Device.Color().Sprint("s"))
 	default:
-		result.WriteString(color.New(color.FgCyan).Sprint("-"))
+		result.WriteString(activeTheme.Permissions.File.Color().Sprint("-"))
 	}
 
 	for i := 8; i >= 0; i-- {
@@ -294,18 +608,18 @@ func formatPermissions(mode fs.FileMode, useOctal bool) string {
 		switch (8 - i) % 3 {
 		case 0:
 			if bit == 1 {
-				c = color.New(color.FgGreen, color.Bold)
+				c = activeTheme.Permissions.Read.Color()
 				result.WriteString(c.Sprint("r"))
 			} else {
-				c = color.New(color.FgHiBlack)
+				c = activeTheme.Permissions.None.Color()
 				result.WriteString(c.Sprint("-"))
 			}
 		case 1:
 			if bit == 1 {
-				c = color.New(color.FgYellow, color.Bold)
+				c = activeTheme.Permissions.Write.Color()
 				result.WriteString(c.Sprint("w"))
 			} else {
-				c = color.New(color.FgHiBlack)
+				c = activeTheme.Permissions.None.Color()
 				result.WriteString(c.Sprint("-"))
 			}
 		case 2:
@@ -322,26 +636,26 @@ func formatPermissions(mode fs.FileMode, useOctal bool) string {
 			if hasSpecial {
 				if group == 2 {
 					if bit == 1 {
-						c = color.New(color.FgRed, color.Bold)
+						c = activeTheme.Permissions.Sticky.Color()
 						result.WriteString(c.Sprint("t"))
 					} else {
-						c = color.New(color.FgRed, color.Bold)
+						c = activeTheme.Permissions.Sticky.Color()
 						result.WriteString(c.Sprint("T"))
 					}
 				} else {
 					if bit == 1 {
-						c = color.New(color.FgMagenta, color.Bold)
+						c = activeTheme.Permissions.Setid.Color()
 						result.WriteString(c.Sprint("s"))
 					} else {
-						c = color.New(color.FgMagenta, color.Bold)
+						c = activeTheme.Permissions.Setid.Color()
 						result.WriteString(c.Sprint("S"))
 					}
 				}
 			} else if bit == 1 {
-				c = color.New(color.FgRed, color.Bold)
+				c = activeTheme.Permissions.Exec.Color()
 				result.WriteString(c.Sprint("x"))
 			} else {
-				c = color.New(color.FgHiBlack)
+				c = activeTheme.Permissions.None.Color()
 				result.WriteString(c.Sprint("-"))
 			}
 		}
@@ -350,23 +664,176 @@ func formatPermissions(mode fs.FileMode, useOctal bool) string {
 	return result.String()
 }
 
+func formatEntries(count int) string {
+	switch {
+	case count <= 0:
+		return ""
+	case count == 1:
+		return " " + activeTheme.Muted.Color().Sprint("(1 entry)")
+	default:
+		return " " + activeTheme.Muted.Color().Sprintf("(%d entries)", count)
+	}
+}
+
+// formatOwner draws a user or group name in the owner columns.
+func formatOwner(name string) string {
+	return color.New(color.FgWhite).Sprint(name)
+}
+
+// formatCommit shows when the last commit touching an entry was made and
+// its short hash, for the --git-blame column.
+func formatCommit(commit model.Commit, now time.Time, showExact bool) string {
+	if commit.Hash == "" {
+		return ""
+	}
+	return formatModified(commit.Time, now, showExact) + " " + activeTheme.Muted.Color().Sprint(commit.Hash)
+}
+
+// formatCommitSuffix is the --git-blame information of a tree line, e.g.
+// "a1b2c3d alice, 3 days ago".
+func formatCommitSuffix(commit model.Commit, now time.Time) string {
+	return activeTheme.Muted.Color().Sprintf("%s %s, %s", commit.Hash, commit.Author, helper.StripANSI(formatModified(commit.Time, now, false)))
+}
+
+func formatMedia(info string) string {
+	if info == "" {
+		return ""
+	}
+	return activeTheme.Accent.Color().Sprint(info)
+}
+
+// formatCount shows an inode number or link count, or nothing where the
+// platform does not report one.
+func formatCount(n uint64) string {
+	if n == 0 {
+		return ""
+	}
+	return activeTheme.Muted.Color().Sprint(n)
+}
+
+// formatCompress colors a compressibility rating: files worth compressing
+// stand out, those that are already compressed fade.
+func formatCompress(rating string) string {
+	switch rating {
+	case "":
+		return ""
+	case entropy.High:
+		return color.New(color.FgGreen).Sprint(rating)
+	case entropy.Medium:
+		return color.New(color.FgYellow).Sprint(rating)
+	default:
+		return activeTheme.Muted.Color().Sprint(rating)
+	}
+}
+
+// finderColors are the chip backgrounds for Finder's label indexes.
+var finderColors = map[int]color.Attribute{
+	1: color.BgHiBlack,
+	2: color.BgGreen,
+	3: color.BgMagenta,
+	4: color.BgBlue,
+	5: color.BgYellow,
+	6: color.BgRed,
+	7: color.BgHiRed,
+}
+
+// formatTags draws each tag as a chip in its Finder color, or in the accent
+// color when it has none. Without color the names are listed plainly.
+func formatTags(tags []model.Tag) string {
+	parts := make([]string, len(tags))
+	for i, tag := range tags {
+		if color.NoColor {
+			parts[i] = tag.Name
+			continue
+		}
+		if bg, ok := finderColors[tag.Color]; ok {
+			parts[i] = color.New(bg, color.FgHiWhite).Sprint(" " + tag.Name + " ")
+		} else {
+			parts[i] = activeTheme.Accent.Color().Sprint("#" + tag.Name)
+		}
+	}
+	if color.NoColor {
+		return strings.Join(parts, ", ")
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatExecStatus(status string) string {
+	switch status {
+	case "":
+		return ""
+	case "ok":
+		return activeTheme.Git.Added.Color().Sprint(status)
+	default:
+		return activeTheme.Git.Untracked.Color().Sprint(status)
+	}
+}
+
+// formatGitOrigin shows where a renamed or copied file came from, dimmed
+// so it reads as a note on the status rather than a status of its own.
+func formatGitOrigin(origin string) string {
+	if origin == "" {
+		return ""
+	}
+	return " " + activeTheme.Muted.Color().Sprint(originArrow+" "+origin)
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
 func formatGitStatus(status string) string {
 	if status == "" {
 		return ""
 	}
 
+	symbols := activeTheme.GitSymbols
 	switch status {
 	case "?":
-		return color.New(color.FgRed, color.Bold).Sprint(status)
+		return activeTheme.Git.Untracked.Color().Sprint(orDefault(symbols.Untracked, status))
 	case "A", "AM":
-		return color.New(color.FgGreen, color.Bold).Sprint(status)
+		return activeTheme.Git.Added.Color().Sprint(orDefault(symbols.Added, status))
 	case "M", " M", "MM":
-		return color.New(color.FgYellow, color.Bold).Sprint(status)
+		return activeTheme.Git.Modified.Color().Sprint(orDefault(symbols.Modified, status))
 	case "D", " D":
-		return color.New(color.FgRed).Sprint(status)
+		return activeTheme.Git.Deleted.Color().Sprint(orDefault(symbols.Deleted, status))
 	case "R", "C":
-		return color.New(color.FgCyan, color.Bold).Sprint(status)
+		return activeTheme.Git.Renamed.Color().Sprint(orDefault(symbols.Renamed, status))
+	case "U":
+		return activeTheme.Git.Conflict.Color().Sprint(orDefault(symbols.Conflict, status))
+	case "I":
+		return activeTheme.Muted.Color().Sprint(status)
 	default:
-		return color.New(color.FgYellow).Sprint(status)
+		return activeTheme.Git.Other.Color().Sprint(status)
+	}
+}
+
+// gitChangeOrder lists the states a directory's changes are summed up in,
+// conflicts first. Any other state, such as T for a type change, follows
+// in alphabetical order.
+var gitChangeOrder = []string{"U", "M", "A", "D", "R", "C", "?"}
+
+// formatGitChanges sums up what is pending inside a directory, e.g.
+// "3M 2?", each count in the color of its state.
+func formatGitChanges(counts map[string]int) string {
+	statuses := slices.Clone(gitChangeOrder)
+	var others []string
+	for status := range counts {
+		if !slices.Contains(gitChangeOrder, status) {
+			others = append(others, status)
+		}
+	}
+	slices.Sort(others)
+	statuses = append(statuses, others...)
+
+	parts := make([]string, 0, len(counts))
+	for _, status := range statuses {
+		if n := counts[status]; n > 0 {
+			parts = append(parts, strconv.Itoa(n)+formatGitStatus(status))
+		}
 	}
+	return strings.Join(parts, " ")
 }
@@ -5,6 +5,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ipanardian/lu-hut/internal/git"
+	"github.com/ipanardian/lu-hut/internal/model"
 	"github.com/ipanardian/lu-hut/pkg/helper"
 )
 
@@ -285,6 +287,24 @@ func TestFormatSize(t *testing.T) {
 	}
 }
 
+func TestFormatSizeAlignedUnits(t *testing.T) {
+	SetAlignUnits(true)
+	defer SetAlignUnits(false)
+
+	for size, expected := range map[int64]string{
+		512:             "   512 B ",
+		1536:            "   1.5 KB",
+		1023 * 1024 * 8: "   8.0 MB",
+	} {
+		if result := helper.StripANSI(formatSize(size, false)); result != expected {
+			t.Errorf("formatSize(%d) = %q, want %q", size, result, expected)
+		}
+	}
+	if result := helper.StripANSI(formatSize(0, true)); result != "     -   " {
+		t.Errorf("formatSize(dir) = %q", result)
+	}
+}
+
 func TestStripANSI(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -409,3 +429,91 @@ func TestFormatModified(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatMore(t *testing.T) {
+	if got := helper.StripANSI(formatMore(1234)); got != "… and 1234 more" {
+		t.Errorf("formatMore() = %q", got)
+	}
+	SetASCII(true)
+	defer SetASCII(false)
+	if got := helper.StripANSI(formatMore(3)); got != "... and 3 more" {
+		t.Errorf("formatMore() with --ascii = %q", got)
+	}
+}
+
+func TestFormatGitHeader(t *testing.T) {
+	tests := []struct {
+		summary git.Summary
+		want    string
+	}{
+		{git.Summary{Branch: "main"}, "main · clean"},
+		{git.Summary{Branch: "main", Upstream: "origin/main", Ahead: 1, Behind: 2, Modified: 3, Untracked: 1}, "main → origin/main ⇡1 ⇣2 · 3 modified, 1 untracked"},
+		{git.Summary{Branch: "fix", Upstream: "origin/fix", Gone: true, Staged: 2, Conflicted: 1}, "fix → origin/fix (gone) · 1 conflicted, 2 staged"},
+		{git.Summary{Untracked: 4}, "(detached) · 4 untracked"},
+	}
+	for _, tt := range tests {
+		if got := helper.StripANSI(formatGitHeader(tt.summary)); got != tt.want {
+			t.Errorf("formatGitHeader(%+v) = %q, want %q", tt.summary, got, tt.want)
+		}
+	}
+	SetASCII(true)
+	defer SetASCII(false)
+	if got := helper.StripANSI(formatGitHeader(tests[1].summary)); got != "main -> origin/main +1 -2 | 3 modified, 1 untracked" {
+		t.Errorf("formatGitHeader() with --ascii = %q", got)
+	}
+}
+
+func TestFormatGrowth(t *testing.T) {
+	tests := []struct {
+		file model.FileEntry
+		want string
+	}{
+		{model.FileEntry{}, "-"},
+		{model.FileEntry{HasGrowth: true}, "0 B/s"},
+		{model.FileEntry{HasGrowth: true, Growth: 0.4}, "0 B/s"},
+		{model.FileEntry{HasGrowth: true, Growth: 1536}, "+1.5 KB/s"},
+		{model.FileEntry{HasGrowth: true, Growth: -200}, "-200 B/s"},
+		{model.FileEntry{HasGrowth: true, Growth: 4 << 20}, "+4.0 MB/s"},
+	}
+	for _, tt := range tests {
+		for _, alert := range []int64{0, 1 << 20} {
+			if got := helper.StripANSI(formatGrowth(tt.file, alert)); got != tt.want {
+				t.Errorf("formatGrowth(%v, %d) = %q, want %q", tt.file.Growth, alert, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestFormatGitStatus(t *testing.T) {
+	tests := []struct {
+		status string
+		want   string
+	}{
+		{"", ""},
+		{"?", "?"},
+		{" M", " M"},
+		{"I", "I"},
+	}
+	for _, tt := range tests {
+		if got := helper.StripANSI(formatGitStatus(tt.status)); got != tt.want {
+			t.Errorf("formatGitStatus(%q) = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestFormatGitChanges(t *testing.T) {
+	tests := []struct {
+		counts map[string]int
+		want   string
+	}{
+		{nil, ""},
+		{map[string]int{"M": 3, "?": 2}, "3M 2?"},
+		{map[string]int{"?": 1, "D": 1, "U": 2, "A": 4}, "2U 4A 1D 1?"},
+		{map[string]int{"T": 1, "M": 2, "X": 3}, "2M 1T 3X"},
+	}
+	for _, tt := range tests {
+		if got := helper.StripANSI(formatGitChanges(tt.counts)); got != tt.want {
+			t.Errorf("formatGitChanges(%v) = %q, want %q", tt.counts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package renderer
+
+import (
+	"math"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+type rgb struct {
+	r, g, b float64
+}
+
+var (
+	ageStops = []rgb{
+		{0x5f, 0xd7, 0x5f},
+		{0xff, 0xd7, 0x00},
+		{0xff, 0x87, 0x00},
+		{0xff, 0x5f, 0x5f},
+	}
+	sizeStops = []rgb{
+		{0x6c, 0x6c, 0x6c},
+		{0xbc, 0xbc, 0xbc},
+		{0xff, 0xff, 0xff},
+	}
+)
+
+const (
+	ageGradientStart  = time.Minute
+	ageGradientEnd    = 2 * 365 * 24 * time.Hour
+	sizeGradientStart = 1.0
+	sizeGradientEnd   = 10 << 30
+)
+
+var truecolor bool
+
+// SetTruecolor enables smooth 24-bit gradients for the Size and Modified
+// columns. It should only be enabled when the terminal supports it.
+func SetTruecolor(enabled bool) {
+	truecolor = enabled
+}
+
+func useGradient() bool {
+	return truecolor && activeTheme.Gradient
+}
+
+func ageGradient(age time.Duration) *color.Color {
+	return gradientAt(ageStops, logPosition(float64(age), float64(ageGradientStart), float64(ageGradientEnd)))
+}
+
+func sizeGradient(size int64) *color.Color {
+	return gradientAt(sizeStops, logPosition(float64(size), sizeGradientStart, sizeGradientEnd))
+}
+
+// logPosition maps v onto [0, 1] on a logarithmic scale between lo and hi,
+// which spreads out the small values where most files live.
+func logPosition(v, lo, hi float64) float64 {
+	if v <= lo {
+		return 0
+	}
+	if v >= hi {
+		return 1
+	}
+	return math.Log(v/lo) / math.Log(hi/lo)
+}
+
+func gradientAt(stops []rgb, t float64) *color.Color {
+	segments := float64(len(stops) - 1)
+	pos := t * segments
+	idx := min(int(pos), len(stops)-2)
+	frac := pos - float64(idx)
+
+	from, to := stops[idx], stops[idx+1]
+	return color.RGB(
+		int(math.Round(from.r+(to.r-from.r)*frac)),
+		int(math.Round(from.g+(to.g-from.g)*frac)),
+		int(math.Round(from.b+(to.b-from.b)*frac)),
+	)
+}
@@ -0,0 +1,125 @@
+package renderer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ipanardian/lu-hut/internal/du"
+	"github.com/ipanardian/lu-hut/internal/git"
+	"github.com/ipanardian/lu-hut/internal/model"
+	"github.com/ipanardian/lu-hut/internal/project"
+)
+
+func RenderProjectHeader(info project.Info) {
+	line := activeTheme.Header.Color().Sprint(info.Name) + " " + activeTheme.Muted.Color().Sprintf("(%s)", info.Kind)
+	if info.Version != "" {
+		line += " " + activeTheme.Accent.Color().Sprint(info.Version)
+	}
+	fmt.Println(line)
+}
+
+// RenderGitHeader prints the one-line repository summary shown above a
+// --git listing, e.g. "main → origin/main ⇡1 ⇣2 · 3 modified, 1 untracked".
+func RenderGitHeader(s git.Summary) {
+	fmt.Println(formatGitHeader(s))
+}
+
+func formatGitHeader(s git.Summary) string {
+	muted := activeTheme.Muted.Color()
+	line := activeTheme.Accent.Color().Sprint(orDefault(s.Branch, "(detached)"))
+	switch {
+	case s.Gone:
+		line += muted.Sprintf(" %s %s (gone)", upstreamArrow, s.Upstream)
+	case s.Upstream != "":
+		line += muted.Sprintf(" %s %s", upstreamArrow, s.Upstream)
+	}
+	if s.Ahead > 0 {
+		line += fmt.Sprintf(" %s%d", aheadMark, s.Ahead)
+	}
+	if s.Behind > 0 {
+		line += fmt.Sprintf(" %s%d", behindMark, s.Behind)
+	}
+
+	line += muted.Sprintf(" %s ", headerSeparator)
+	if s.Clean() {
+		return line + activeTheme.Git.Added.Color().Sprint("clean")
+	}
+	var counts []string
+	for _, c := range []struct {
+		n     int
+		label string
+		style func(a ...any) string
+	}{
+		{s.Conflicted, "conflicted", activeTheme.Git.Conflict.Color().Sprint},
+		{s.Staged, "staged", activeTheme.Git.Added.Color().Sprint},
+		{s.Modified, "modified", activeTheme.Git.Modified.Color().Sprint},
+		{s.Untracked, "untracked", activeTheme.Git.Untracked.Color().Sprint},
+	} {
+		if c.n > 0 {
+			counts = append(counts, c.style(fmt.Sprintf("%d %s", c.n, c.label)))
+		}
+	}
+	return line + strings.Join(counts, muted.Sprint(", "))
+}
+
+// RenderFilesystems prints on-disk totals per filesystem after a scan that
+// crossed mount points. A scan that stayed on one filesystem prints nothing.
+func RenderFilesystems(list []du.Filesystem) {
+	if len(list) < 2 {
+		return
+	}
+
+	width := 0
+	for _, fs := range list {
+		width = max(width, runeCount(fs.Mount))
+	}
+
+	muted := activeTheme.Muted.Color()
+	fmt.Println()
+	fmt.Println(muted.Sprint("Filesystems"))
+	for _, fs := range list {
+		entries := "entries"
+		if fs.Entries == 1 {
+			entries = "entry"
+		}
+		fmt.Printf("  %s%s  %s\n",
+			activeTheme.Names.Directory.Color().Sprint(fs.Mount),
+			strings.Repeat(" ", width-runeCount(fs.Mount)),
+			muted.Sprintf("%8s on disk, %d %s", humanSize(fs.OnDisk), fs.Entries, entries))
+	}
+}
+
+// RenderDiskUsage prints the --du totals below a table. The apparent size
+// is only shown when hardlinks or followed symlinks made it differ.
+func RenderDiskUsage(usage du.Usage) {
+	line := "Total " + humanSize(usage.Unique)
+	if usage.Apparent != usage.Unique {
+		line += fmt.Sprintf(" unique, %s apparent", humanSize(usage.Apparent))
+	}
+	fmt.Println(activeTheme.Muted.Color().Sprint(line))
+}
+
+// RenderMore prints the footer below a table that --limit cut short.
+func RenderMore(more int) {
+	if more > 0 {
+		fmt.Println(formatMore(more))
+	}
+}
+
+// RenderRetention prints the totals of a retention preview: how many files
+// the policy would delete and how much space that frees, next to what it
+// keeps.
+func RenderRetention(expired []model.FileEntry, kept int, keptBytes int64) {
+	var reclaimed int64
+	for _, file := range expired {
+		reclaimed += file.Size
+	}
+
+	files := "files"
+	if len(expired) == 1 {
+		files = "file"
+	}
+	fmt.Printf("%s %s\n",
+		activeTheme.Warning.Color().Sprintf("%d %s would be deleted, reclaiming %s.", len(expired), files, humanSize(reclaimed)),
+		activeTheme.Muted.Color().Sprintf("%d kept (%s). Nothing was deleted.", kept, humanSize(keptBytes)))
+}
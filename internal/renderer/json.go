@@ -0,0 +1,130 @@
+package renderer
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/ipanardian/lu-hut/internal/model"
+	"github.com/ipanardian/lu-hut/internal/sort"
+)
+
+type jsonEntry struct {
+	Name        string         `json:"name"`
+	Path        string         `json:"path"`
+	Type        string         `json:"type"`
+	Size        int64          `json:"size"`
+	Apparent    int64          `json:"apparent_size,omitempty"`
+	SizeUnknown bool           `json:"size_unknown,omitempty"`
+	Mode        string         `json:"mode"`
+	Modified    time.Time      `json:"modified"`
+	Hidden      bool           `json:"hidden,omitempty"`
+	GitStatus   string         `json:"git_status,omitempty"`
+	GitOrigin   string         `json:"git_origin,omitempty"`
+	GitChanges  map[string]int `json:"git_changes,omitempty"`
+	User        string         `json:"user,omitempty"`
+	Group       string         `json:"group,omitempty"`
+	Media       string         `json:"media,omitempty"`
+	Compress    string         `json:"compressibility,omitempty"`
+	Inode       uint64         `json:"inode,omitempty"`
+	Links       uint64         `json:"links,omitempty"`
+	Mime        string         `json:"mime,omitempty"`
+	Entries     int            `json:"entries,omitempty"`
+	Exec        string         `json:"exec,omitempty"`
+	Target      string         `json:"target,omitempty"`
+	Tags        []string       `json:"tags,omitempty"`
+	Generated   bool           `json:"generated,omitempty"`
+	BrokenLink  bool           `json:"broken_link,omitempty"`
+	NameProblem string         `json:"name_problem,omitempty"`
+	WantPerms   string         `json:"expected_perms,omitempty"`
+	PermNote    string         `json:"perms_note,omitempty"`
+	SortKey     *sort.Key      `json:"sort_key,omitempty"`
+	LastCommit  *jsonCommit    `json:"last_commit,omitempty"`
+	More        int            `json:"more,omitempty"`
+}
+
+type jsonCommit struct {
+	Hash   string    `json:"hash"`
+	Author string    `json:"author"`
+	Time   time.Time `json:"time"`
+}
+
+// RenderJSON writes files as a JSON array, one object per entry. Values are
+// raw (sizes in bytes, RFC 3339 times) so scripts do not have to parse the
+// human-friendly table columns.
+func RenderJSON(files []model.FileEntry) error {
+	return RenderVerboseJSON(files, nil)
+}
+
+// RenderVerboseJSON is RenderJSON with the sort key of each entry, keys[i]
+// belonging to files[i], for --json-verbose.
+func RenderVerboseJSON(files []model.FileEntry, keys []sort.Key) error {
+	entries := make([]jsonEntry, len(files))
+	for i, file := range files {
+		entries[i] = jsonEntry{
+			Name:        file.Name,
+			Path:        file.Path,
+			Type:        entryType(file),
+			Size:        file.Size,
+			Apparent:    file.ApparentSize,
+			SizeUnknown: file.SizeUnknown,
+			Mode:        file.Mode.String(),
+			Modified:    file.ModTime,
+			Hidden:      file.IsHidden,
+			GitStatus:   file.GitStatus,
+			GitOrigin:   file.GitOrigin,
+			GitChanges:  file.GitChanges,
+			User:        file.Author,
+			Group:       file.Group,
+			Media:       file.Media,
+			Compress:    file.Compress,
+			Inode:       file.Inode,
+			Links:       file.Links,
+			Mime:        file.Mime,
+			Entries:     file.Entries,
+			Exec:        file.Exec,
+			Target:      file.Target,
+			Tags:        tagNames(file.Tags),
+			Generated:   file.Generated,
+			BrokenLink:  file.BrokenLink,
+			NameProblem: file.NameProblem,
+			WantPerms:   file.WantPerms,
+			PermNote:    file.PermNote,
+			More:        file.More,
+		}
+		if i < len(keys) {
+			entries[i].SortKey = &keys[i]
+		}
+		if c := file.LastCommit; c.Hash != "" {
+			entries[i].LastCommit = &jsonCommit{Hash: c.Hash, Author: c.Author, Time: c.Time}
+		}
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+func entryType(file model.FileEntry) string {
+	switch {
+	case file.IsDir:
+		return "dir"
+	case file.Mode&fs.ModeSymlink != 0:
+		return "symlink"
+	case file.Mode.IsRegular():
+		return "file"
+	}
+	return "other"
+}
+
+func tagNames(tags []model.Tag) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	return names
+}
@@ -5,18 +5,27 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/fatih/color"
 	"github.com/ipanardian/lu-hut/internal/config"
+	"github.com/ipanardian/lu-hut/internal/icons"
 	"github.com/ipanardian/lu-hut/internal/model"
 	"github.com/ipanardian/lu-hut/internal/table"
 )
 
 type Table struct {
 	config config.Config
+	icons  *icons.Set
+	// widths and nameWidth are grown by Align and then used for every
+	// table instead of measuring each one on its own.
+	widths    []int
+	nameWidth int
 }
 
 func NewTable(cfg config.Config) *Table {
-	return &Table{config: cfg}
+	t := &Table{config: cfg}
+	if cfg.ShowIcons {
+		t.icons = icons.NewSet(cfg.IconOverrides)
+	}
+	return t
 }
 
 func (r *Table) Render(files []model.FileEntry, now time.Time) {
@@ -24,23 +33,76 @@ func (r *Table) Render(files []model.FileEntry, now time.Time) {
 		return
 	}
 
+	if r.widths != nil {
+		r.print(r.buildTableData(files, now, r.nameWidth), r.widths)
+		return
+	}
+
+	data, widths, _, ok := r.layout(files, now)
+	if !ok {
+		fmt.Println("Terminal is too small to display the table. Please widen your terminal window.")
+		return
+	}
+	r.print(data, widths)
+}
+
+// Align widens the columns to fit the entries in files as well as those
+// aligned before, so the sections of a recursive listing, printed as they
+// are read, line up with the ones above them. When the wider columns would
+// not fit the terminal, the columns start over from files alone.
+func (r *Table) Align(files []model.FileEntry, now time.Time) {
+	if len(files) == 0 {
+		return
+	}
+	_, widths, nameWidth, ok := r.layout(files, now)
+	if !ok {
+		return
+	}
+	if len(r.widths) == len(widths) {
+		total := (len(widths)-1)*3 + 2
+		merged := make([]int, len(widths))
+		for i := range widths {
+			merged[i] = max(widths[i], r.widths[i])
+			total += merged[i]
+		}
+		if total <= max(getTerminalWidth(), 40) {
+			widths, nameWidth = merged, max(nameWidth, r.nameWidth)
+		}
+	}
+	r.widths, r.nameWidth = widths, nameWidth
+}
+
+// layout builds the cells for files and measures the columns to fit the
+// terminal. It reports false when even the narrowest layout does not fit.
+func (r *Table) layout(files []model.FileEntry, now time.Time) ([][]string, []int, int, bool) {
 	terminalWidth := max(getTerminalWidth(), 40)
 
 	mins, maxs := r.columnConstraints()
 	nameWidth := 0
 	if len(maxs) > 0 {
 		nameWidth = maxs[0]
+		if r.icons != nil {
+			nameWidth -= iconWidth
+		}
 	}
 
 	data := r.buildTableData(files, now, nameWidth)
 	displayWidths := calculateDisplayWidths(data)
+	clampWidths(displayWidths, mins, maxs)
 
-	for i := range displayWidths {
-		if i < len(mins) && mins[i] > 0 && displayWidths[i] < mins[i] {
-			displayWidths[i] = mins[i]
+	// Names that hit the default cap get whatever width the other columns
+	// leave unused, so wide terminals truncate fewer of them.
+	if len(maxs) > 0 && displayWidths[0] >= maxs[0] {
+		spare := terminalWidth - (len(displayWidths)-1)*3 - 2
+		for _, w := range displayWidths[1:] {
+			spare -= w
 		}
-		if i < len(maxs) && maxs[i] > 0 && displayWidths[i] > maxs[i] {
-			displayWidths[i] = maxs[i]
+		if spare > maxs[0] {
+			nameWidth += spare - maxs[0]
+			maxs[0] = spare
+			data = r.buildTableData(files, now, nameWidth)
+			displayWidths = calculateDisplayWidths(data)
+			clampWidths(displayWidths, mins, maxs)
 		}
 	}
 
@@ -50,8 +112,7 @@ func (r *Table) Render(files []model.FileEntry, now time.Time) {
 	}
 	minBorderWidth := (len(displayWidths)-1)*3 + 2
 	if terminalWidth < minContentWidth+minBorderWidth {
-		fmt.Println("Terminal is too small to display the table. Please widen your terminal window.")
-		return
+		return nil, nil, 0, false
 	}
 
 	totalContentWidth := 0
@@ -64,39 +125,117 @@ func (r *Table) Render(files []model.FileEntry, now time.Time) {
 	if totalWidth > terminalWidth {
 		r.shrinkColumns(displayWidths, mins, totalWidth-terminalWidth)
 	}
+	return data, displayWidths, nameWidth, true
+}
 
-	tbl := table.NewTableWithWidths(data, displayWidths)
-	tbl.SetBorderStyle(0)
+func (r *Table) print(data [][]string, widths []int) {
+	tbl := table.NewTableWithWidths(data, widths)
+	tbl.SetBorderStyle(r.borderStyle())
 	tbl.SetHeaderStyle(1)
-	tbl.SetHeaderColor(color.New(color.FgWhite, color.Bold))
-	tbl.SetBorderColor(color.New(color.FgGreen))
+	tbl.SetHeaderColor(activeTheme.Header.Color())
+	tbl.SetBorderColor(activeTheme.Border.Color())
 	tbl.Print()
 }
 
+func clampWidths(widths, mins, maxs []int) {
+	for i := range widths {
+		if i < len(mins) && mins[i] > 0 && widths[i] < mins[i] {
+			widths[i] = mins[i]
+		}
+		if i < len(maxs) && maxs[i] > 0 && widths[i] > maxs[i] {
+			widths[i] = maxs[i]
+		}
+	}
+}
+
+func (r *Table) borderStyle() int {
+	if r.config.ASCII {
+		return table.StyleASCII
+	}
+	switch r.config.BorderStyle {
+	case "double":
+		return table.StyleDouble
+	case "bold":
+		return table.StyleBold
+	case "ascii":
+		return table.StyleASCII
+	default:
+		return table.StyleSingle
+	}
+}
+
 func (r *Table) buildTableData(files []model.FileEntry, now time.Time, nameWidth int) [][]string {
-	headers := []string{"Name", "Size", "Modified", "Perms"}
+	headers := []string{"Name", "Size", timeHeader(r.config.TimeField), "Perms"}
 	if r.config.ShowGit {
 		headers = append(headers, "Git")
 	}
 	if r.config.ShowUser {
 		headers = append(headers, "User", "Group")
 	}
+	if r.config.GitBlame {
+		headers = append(headers, "Author", "Last Commit")
+	}
+	if r.config.ShowMedia {
+		headers = append(headers, "Media")
+	}
+	if r.config.ShowCompress {
+		headers = append(headers, "Compress")
+	}
+	if r.config.ShowInode {
+		headers = append(headers, "Inode", "Links")
+	}
+	if r.config.ShowTags {
+		headers = append(headers, "Tags")
+	}
+	if r.config.ExplainPerms {
+		headers = append(headers, "Note")
+	}
+	if r.config.Exec != "" {
+		headers = append(headers, "Exec")
+	}
+	if r.config.Watch {
+		headers = append(headers, "Growth")
+	}
 
 	data := make([][]string, len(files)+1)
 	data[0] = headers
 
 	for i, file := range files {
 		row := []string{
-			formatName(file, nameWidth),
-			formatSize(file.Size, file.IsDir),
-			formatModified(file.ModTime, now, r.config.ShowExactTime),
-			formatPermissions(file.Mode, r.config.ShowOctal),
+			formatIcon(file, r.icons) + formatName(file, nameWidthFor(file, nameWidth)) + formatBadges(file),
+			formatEntrySize(file, (file.IsDir && !r.config.DiskUsage) || file.Virtual) + formatEntries(file.Entries),
+			formatModified(shownTime(file, r.config.TimeField), now, r.config.ShowExactTime),
+			formatEntryPermissions(file, r.config.ShowOctal),
 		}
 		if r.config.ShowGit {
-			row = append(row, formatGitStatus(file.GitStatus))
+			row = append(row, formatGitStatus(file.GitStatus)+formatGitOrigin(file.GitOrigin)+formatGitChanges(file.GitChanges))
 		}
 		if r.config.ShowUser {
-			row = append(row, file.Author, file.Group)
+			row = append(row, formatOwner(file.Author), formatOwner(file.Group))
+		}
+		if r.config.GitBlame {
+			row = append(row, formatOwner(file.LastCommit.Author), formatCommit(file.LastCommit, now, r.config.ShowExactTime))
+		}
+		if r.config.ShowMedia {
+			row = append(row, formatMedia(file.Media))
+		}
+		if r.config.ShowCompress {
+			row = append(row, formatCompress(file.Compress))
+		}
+		if r.config.ShowInode {
+			row = append(row, formatCount(file.Inode), formatCount(file.Links))
+		}
+		if r.config.ShowTags {
+			row = append(row, formatTags(file.Tags))
+		}
+		if r.config.ExplainPerms {
+			row = append(row, formatPermNote(file.PermNote))
+		}
+		if r.config.Exec != "" {
+			row = append(row, formatExecStatus(file.Exec))
+		}
+		if r.config.Watch {
+			row = append(row, formatGrowth(file, r.config.GrowthAlertRate()))
 		}
 		data[i+1] = row
 	}
@@ -107,18 +246,53 @@ func (r *Table) buildTableData(files []model.FileEntry, now time.Time, nameWidth
 func (r *Table) columnConstraints() ([]int, []int) {
 	mins := []int{15, 6, 10, 10}
 	maxs := []int{50, 10, 15, 12}
+	if r.config.ShowEntries {
+		maxs[1] = 24
+	}
 	if r.config.ShowExactTime {
 		mins[2] = 16
 		maxs[2] = 17
 	}
 	if r.config.ShowGit {
 		mins = append(mins, 6)
-		maxs = append(maxs, 12)
+		maxs = append(maxs, 32)
 	}
 	if r.config.ShowUser {
 		mins = append(mins, 6, 6)
 		maxs = append(maxs, 12, 12)
 	}
+	if r.config.GitBlame {
+		mins = append(mins, 6, 10)
+		maxs = append(maxs, 16, 26)
+	}
+	if r.config.ShowMedia {
+		mins = append(mins, 6)
+		maxs = append(maxs, 12)
+	}
+	if r.config.ShowCompress {
+		mins = append(mins, 6)
+		maxs = append(maxs, 8)
+	}
+	if r.config.ShowInode {
+		mins = append(mins, 6, 5)
+		maxs = append(maxs, 20, 6)
+	}
+	if r.config.ShowTags {
+		mins = append(mins, 4)
+		maxs = append(maxs, 24)
+	}
+	if r.config.ExplainPerms {
+		mins = append(mins, 4)
+		maxs = append(maxs, 44)
+	}
+	if r.config.Exec != "" {
+		mins = append(mins, 4)
+		maxs = append(maxs, 10)
+	}
+	if r.config.Watch {
+		mins = append(mins, 6)
+		maxs = append(maxs, 12)
+	}
 	return mins, maxs
 }
 
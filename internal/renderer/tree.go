@@ -6,14 +6,22 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/ipanardian/lu-hut/internal/config"
 	"github.com/ipanardian/lu-hut/internal/filter"
+	"github.com/ipanardian/lu-hut/internal/generated"
 	"github.com/ipanardian/lu-hut/internal/git"
+	"github.com/ipanardian/lu-hut/internal/icons"
+	"github.com/ipanardian/lu-hut/internal/inspect"
 	"github.com/ipanardian/lu-hut/internal/model"
+	"github.com/ipanardian/lu-hut/internal/portable"
+	"github.com/ipanardian/lu-hut/internal/pseudofs"
+	"github.com/ipanardian/lu-hut/internal/sniff"
 	"github.com/ipanardian/lu-hut/internal/sort"
+	"github.com/ipanardian/lu-hut/internal/tags"
 	"github.com/ipanardian/lu-hut/pkg/helper"
 )
 
@@ -22,24 +30,52 @@ type Tree struct {
 	gitRepo      *git.Repository
 	sortStrategy sort.Strategy
 	filter       *filter.Filter
+	icons        *icons.Set
+	dirCount     int
+	fileCount    int
+
+	// root and label name the tree being rendered, so --format flat can
+	// print paths relative to it.
+	root  string
+	label string
+
+	glyphs treeGlyphs
+
+	caseConflicts int
+	nameProblems  int
+	// gitErr is the first failure to ask git for ignored files.
+	gitErr error
+	// commitErr is the first failure to ask git for the last commits.
+	commitErr error
 }
 
+type treeGlyphs struct {
+	branch string
+	last   string
+	pipe   string
+	space  string
+}
+
+var (
+	unicodeGlyphs = treeGlyphs{branch: "├── ", last: "└── ", pipe: "│   ", space: "    "}
+	asciiGlyphs   = treeGlyphs{branch: "|-- ", last: "`-- ", pipe: "|   ", space: "    "}
+)
+
 func NewTree(cfg config.Config) *Tree {
-	var sortStrat sort.Strategy
-	if cfg.SortSize {
-		sortStrat = &sort.Size{}
-	} else if cfg.SortExtension {
-		sortStrat = &sort.Extension{}
-	} else if cfg.SortModified {
-		sortStrat = &sort.Time{}
-	} else {
-		sortStrat = &sort.Name{}
-	}
+	sortStrat := sort.New(cfg.SortOrder(), sort.Options{Dirs: cfg.DirPlacement(), CaseSensitive: cfg.CaseSensitive})
 
-	return &Tree{
+	t := &Tree{
 		config:       cfg,
 		sortStrategy: sortStrat,
+		glyphs:       unicodeGlyphs,
+	}
+	if cfg.ASCII {
+		t.glyphs = asciiGlyphs
+	}
+	if cfg.ShowIcons {
+		t.icons = icons.NewSet(cfg.IconOverrides)
 	}
+	return t
 }
 
 func (r *Tree) SetGitRepo(repo *git.Repository) {
@@ -50,11 +86,75 @@ func (r *Tree) SetFilter(f *filter.Filter) {
 	r.filter = f
 }
 
+// CaseConflicts returns how many groups of names differing only by case
+// were found so far.
+func (r *Tree) CaseConflicts() int {
+	return r.caseConflicts
+}
+
+// NameProblems returns how many listed names had invisible, bidi control or
+// non-NFC characters.
+func (r *Tree) NameProblems() int {
+	return r.nameProblems
+}
+
+// GitError returns why git could not list the ignored files, after which
+// --git-ignore hid nothing, or nil.
+func (r *Tree) GitError() error {
+	return r.gitErr
+}
+
+// CommitError returns why git could not tell the last commits for
+// --git-blame in some directory, or nil.
+func (r *Tree) CommitError() error {
+	return r.commitErr
+}
+
+func (r *Tree) RenderRoot(label string) {
+	if r.flat() {
+		r.label = label
+		return
+	}
+	fmt.Println(activeTheme.Names.Directory.Color().Sprint(label))
+}
+
+func (r *Tree) RenderSummary(roots int) {
+	if r.flat() {
+		return
+	}
+	fmt.Printf("\n%s\n", activeTheme.Muted.Color().Sprintf("%d roots, %d directories, %d files", roots, r.dirCount, r.fileCount))
+
+	if r.config.ShowGit {
+		legend := []struct{ status, desc string }{
+			{"?", "untracked"},
+			{"A", "added"},
+			{"M", "modified"},
+			{"D", "deleted"},
+			{"R", "renamed"},
+		}
+		if !r.config.GitIgnore {
+			legend = append(legend, struct{ status, desc string }{"I", "ignored"})
+		}
+		parts := make([]string, 0, len(legend))
+		for _, l := range legend {
+			parts = append(parts, formatGitStatus(l.status)+" "+l.desc)
+		}
+		fmt.Println(strings.Join(parts, "  "))
+	}
+}
+
+// flat reports whether the tree is printed as --format flat lines instead
+// of being drawn.
+func (r *Tree) flat() bool {
+	return r.config.Format == "flat"
+}
+
 func (r *Tree) Render(ctx context.Context, path string, now time.Time) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
+	r.root = path
 	err := r.renderTreeRecursive(ctx, path, "", true, 0, now)
 	if err == context.Canceled {
 		fmt.Println("\nOperation cancelled by user")
@@ -69,38 +169,86 @@ func (r *Tree) renderTreeRecursive(ctx context.Context, path string, prefix stri
 	}
 
 	if r.config.MaxDepth > 0 && level >= r.config.MaxDepth {
-		if level == r.config.MaxDepth {
-			fmt.Printf("%s└── (max depth reached)\n", prefix)
+		if level == r.config.MaxDepth && !r.flat() {
+			fmt.Printf("%s%s(max depth reached)\n", prefix, r.glyphs.last)
 		}
 		return nil
 	}
 
-	entries, err := os.ReadDir(path)
+	entries, err := sort.ReadDir(path, r.config.SortOrder())
 	if err != nil {
-		fmt.Printf("%s├── Error: %v\n", prefix, err)
+		if r.flat() {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return nil
+		}
+		fmt.Printf("%s%sError: %v\n", prefix, r.glyphs.branch, err)
 		return nil
 	}
 
 	files := make([]model.FileEntry, 0, len(entries))
+	virtual := pseudofs.Is(path)
+	var attrs *generated.Attributes
+	if !virtual {
+		attrs = generated.LoadAttributes(path)
+	}
+	var ignores bool
+	if (r.config.GitIgnore || r.config.ShowGit) && r.gitRepo != nil {
+		err := r.gitRepo.Ignored()
+		if err != nil && r.gitErr == nil {
+			r.gitErr = err
+		}
+		ignores = err == nil
+	}
+	var commits map[string]git.Commit
+	if r.config.GitBlame && r.gitRepo != nil {
+		var err error
+		if commits, err = r.gitRepo.LastCommits(path); err != nil && r.commitErr == nil {
+			r.commitErr = err
+		}
+	}
 	for _, entry := range entries {
 		if !r.config.ShowHidden && strings.HasPrefix(entry.Name(), ".") {
 			continue
 		}
+		ignored := ignores && r.gitRepo.IsIgnored(filepath.Join(path, entry.Name()))
+		if r.config.GitIgnore && ignored {
+			continue
+		}
+		if r.config.SkipSpecial && model.IsSpecial(entry.Type()) {
+			continue
+		}
 
 		info, err := entry.Info()
 		if err != nil {
 			continue
 		}
 
-		file := model.FileEntry{
-			Name:     entry.Name(),
-			Path:     filepath.Join(path, entry.Name()),
-			Size:     info.Size(),
-			Mode:     info.Mode(),
-			ModTime:  info.ModTime(),
-			IsDir:    entry.IsDir(),
-			IsHidden: strings.HasPrefix(entry.Name(), "."),
+		file := inspect.Entry(r.config, entry.Name(), filepath.Join(path, entry.Name()), info)
+		file.Virtual = virtual
+		if r.config.ShowGit && ignored {
+			file.GitStatus = "I"
+		}
+		file.LastCommit = model.Commit(commits[entry.Name()])
+		if !virtual {
+			file.Generated = attrs.Detect(file.Path, r.config.GeneratedHeader && info.Mode().IsRegular())
+			if r.config.ShowTags || len(r.config.FilterTags) > 0 {
+				file.Tags = tags.Read(file.Path)
+			}
 		}
+		// Directories stay so tagged files further down can be reached.
+		if len(r.config.FilterTags) > 0 && !file.IsDir && !tags.Has(file.Tags, r.config.FilterTags) {
+			continue
+		}
+		if file.Generated && !r.config.ShowGenerated {
+			continue
+		}
+		if r.config.Conflicts && !r.gitRepo.HasConflicts(file.Path) {
+			continue
+		}
+		if r.config.GitDirty && !r.gitRepo.HasChanges(file.Path) {
+			continue
+		}
+		inspect.Contents(r.config, &file)
 
 		files = append(files, file)
 	}
@@ -110,93 +258,175 @@ func (r *Tree) renderTreeRecursive(ctx context.Context, path string, prefix stri
 	}
 
 	if r.filter != nil {
-		if r.filter.HasIncludePatterns() {
-			var filtered []model.FileEntry
-
-			for _, file := range files {
-				if file.IsDir {
-					if r.hasMatchingDescendants(ctx, file.Path) {
-						filtered = append(filtered, file)
-					}
-				} else {
-					if r.filter.ShouldInclude(file.Name) && !r.filter.ShouldExclude(file.Name) {
-						filtered = append(filtered, file)
-					}
-				}
+		narrows := r.filter.Narrows()
+		var filtered []model.FileEntry
+		for _, file := range files {
+			if r.filter.ShouldExclude(file.Path) {
+				continue
 			}
-
-			files = filtered
-		} else {
-			var filtered []model.FileEntry
-			for _, file := range files {
-				if !r.filter.ShouldExclude(file.Name) {
-					filtered = append(filtered, file)
-				}
+			// Directories stay as structure when something below them
+			// matches, so the matching entries keep their place in the
+			// tree.
+			if r.selected(file) || (file.IsDir && (!narrows || r.hasMatchingDescendants(ctx, file.Path))) {
+				filtered = append(filtered, file)
 			}
-			files = filtered
 		}
+		files = filtered
 	}
 
 	if r.sortStrategy != nil {
 		r.sortStrategy.Sort(files, r.config.Reverse)
 	}
+	r.caseConflicts += len(portable.MarkCaseConflicts(files))
+	r.nameProblems += portable.MarkNameProblems(files)
+
+	more := 0
+	if limit := r.config.Limit; limit > 0 && len(files) > limit {
+		files, more = files[:limit], len(files)-limit
+	}
 
 	for i, file := range files {
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
 
-		isLast := i == len(files)-1
-		connector := "├── "
+		isLast := i == len(files)-1 && more == 0
+		connector := r.glyphs.branch
 		if isLast {
-			connector = "└── "
+			connector = r.glyphs.last
 		}
 
-		line := prefix + connector
-		nameWidth := getTerminalWidth()
-		if nameWidth <= 0 {
-			nameWidth = defaultNameMaxWidth
-		}
-		prefixWidth := runeCount(helper.StripANSI(line))
-		nameWidth -= prefixWidth
-		if nameWidth <= 0 {
-			nameWidth = defaultNameMaxWidth
-		}
-
-		if file.IsDir {
-			dirWidth := nameWidth
-			if dirWidth > 1 {
-				dirWidth--
+		if r.config.ShowGit && r.gitRepo != nil && file.GitStatus == "" {
+			file.GitStatus = r.gitRepo.GetStatus(file.Path)
+			if file.IsDir && file.GitStatus == "" {
+				file.GitChanges = r.gitRepo.DirChanges(file.Path)
+			} else {
+				file.GitOrigin = r.gitRepo.Origin(file.Path)
 			}
-			line += formatName(file, dirWidth) + "/"
-		} else {
-			line += formatName(file, nameWidth)
 		}
 
-		if r.config.ShowGit && r.gitRepo != nil && !file.IsDir {
-			if status := r.gitRepo.GetStatus(file.Path); status != "" {
-				line += " " + formatGitStatus(status)
-			}
+		if r.flat() {
+			r.renderFlat(file, level+1)
+		} else {
+			r.renderLine(file, prefix+connector, now)
 		}
 
-		fmt.Println(line)
-
-		if file.IsDir {
+		if !file.IsDir {
+			r.fileCount++
+		} else {
+			r.dirCount++
 			newPrefix := prefix
 			if isLast {
-				newPrefix += "    "
+				newPrefix += r.glyphs.space
 			} else {
-				newPrefix += "│   "
+				newPrefix += r.glyphs.pipe
 			}
 			if err := r.renderTreeRecursive(ctx, file.Path, newPrefix, true, level+1, now); err != nil {
 				continue
 			}
 		}
 	}
+	if more > 0 && !r.flat() {
+		fmt.Println(prefix + r.glyphs.last + formatMore(more))
+	}
 
 	return nil
 }
 
+// renderLine prints one entry of the drawn tree after prefix, the branch
+// glyphs leading up to it.
+func (r *Tree) renderLine(file model.FileEntry, prefix string, now time.Time) {
+	line := prefix + formatIcon(file, r.icons)
+	nameWidth := getTerminalWidth()
+	if nameWidth <= 0 {
+		nameWidth = defaultNameMaxWidth
+	}
+	prefixWidth := runeCount(helper.StripANSI(line))
+	nameWidth -= prefixWidth
+	if nameWidth <= 0 {
+		nameWidth = defaultNameMaxWidth
+	}
+
+	if file.IsDir {
+		dirWidth := nameWidth
+		if dirWidth > 1 {
+			dirWidth--
+		}
+		line += formatName(file, nameWidthFor(file, dirWidth)) + "/"
+	} else {
+		line += formatName(file, nameWidthFor(file, nameWidth))
+	}
+	line += formatBadges(file)
+
+	if file.GitStatus != "" {
+		line += " " + formatGitStatus(file.GitStatus) + formatGitOrigin(file.GitOrigin)
+	} else if len(file.GitChanges) > 0 {
+		line += " " + formatGitChanges(file.GitChanges)
+	}
+
+	if r.config.ShowTags && len(file.Tags) > 0 {
+		line += " " + formatTags(file.Tags)
+	}
+
+	line += formatEntries(file.Entries)
+
+	if file.Media != "" {
+		line += " " + formatMedia(file.Media)
+	}
+
+	if file.Compress != "" {
+		line += " " + formatCompress(file.Compress)
+	}
+
+	if file.PermNote != "" {
+		line += " " + formatPermNote(file.PermNote)
+	}
+
+	if file.LastCommit.Hash != "" {
+		line += " " + formatCommitSuffix(file.LastCommit, now)
+	}
+
+	fmt.Println(line)
+}
+
+// renderFlat prints one entry as a depth, path, type and size line for
+// --format flat. Paths are relative to the root, or to its label when
+// several roots are listed, and names with tabs or newlines are quoted so
+// every entry stays on one line.
+func (r *Tree) renderFlat(file model.FileEntry, depth int) {
+	path, err := filepath.Rel(r.root, file.Path)
+	if err != nil {
+		path = file.Path
+	}
+	if r.label != "" {
+		path = filepath.Join(r.label, path)
+	}
+	path = filepath.ToSlash(path)
+	if strings.ContainsAny(path, "\t\n\r") {
+		path = strconv.Quote(path)
+	}
+
+	size := "-"
+	if !file.IsDir && !file.Virtual {
+		size = strconv.FormatInt(file.Size, 10)
+	}
+	fmt.Printf("%d\t%s\t%s\t%s\n", depth, path, entryType(file), size)
+}
+
+// selected reports whether file is one the filters pick out: a file that
+// passes all of them, or a directory when --only asks for directories.
+func (r *Tree) selected(file model.FileEntry) bool {
+	if file.IsDir {
+		return r.filter.SelectsDirs() && r.filter.Matches(file)
+	}
+	if r.filter.HasIncludePatterns() && !r.filter.ShouldInclude(file.Path) {
+		return false
+	}
+	return r.filter.MatchesSize(file) && r.filter.Matches(file)
+}
+
+// hasMatchingDescendants reports whether anything the filters select lies
+// below dirPath, looking at most five levels down.
 func (r *Tree) hasMatchingDescendants(ctx context.Context, dirPath string) bool {
 	var result bool
 
@@ -213,6 +443,10 @@ func (r *Tree) hasMatchingDescendants(ctx context.Context, dirPath string) bool
 			return filepath.SkipDir
 		}
 
+		if path == dirPath {
+			return nil
+		}
+
 		if !r.config.ShowHidden && strings.HasPrefix(d.Name(), ".") {
 			if d.IsDir() {
 				return filepath.SkipDir
@@ -220,11 +454,30 @@ func (r *Tree) hasMatchingDescendants(ctx context.Context, dirPath string) bool
 			return nil
 		}
 
-		if !d.IsDir() {
-			if r.filter.ShouldInclude(d.Name()) && !r.filter.ShouldExclude(d.Name()) {
-				result = true
-				return filepath.SkipAll
+		if r.filter.ShouldExclude(path) {
+			if d.IsDir() {
+				return filepath.SkipDir
 			}
+			return nil
+		}
+		if d.IsDir() && !r.filter.SelectsDirs() {
+			return nil
+		}
+		if r.filter.HasIncludePatterns() && !d.IsDir() && !r.filter.ShouldInclude(path) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		file := inspect.Entry(r.config, d.Name(), path, info)
+		if len(r.config.Mime) > 0 && info.Mode().IsRegular() {
+			file.Mime = sniff.Type(path)
+		}
+		if r.selected(file) {
+			result = true
+			return filepath.SkipAll
 		}
 
 		return nil
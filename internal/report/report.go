@@ -0,0 +1,110 @@
+// Package report runs a batch of listings described in a TOML file, for
+// cron-driven storage audits that would otherwise need a shell script.
+package report
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ipanardian/lu-hut/internal/config"
+)
+
+// Listing is one [[listing]] table: the paths to list, where to write the
+// result, and any config key (format, exclude, recursive, ...) that should
+// differ from [defaults].
+type Listing struct {
+	Name   string   `toml:"name"`
+	Paths  []string `toml:"paths"`
+	Output string   `toml:"output"`
+	config.Config
+}
+
+type Report struct {
+	Listings []Listing
+}
+
+// Load reads a report file. Every listing starts from the built-in defaults
+// overlaid with the file's [defaults] table; the user's own config file and
+// LU_* variables are ignored so scheduled runs do not depend on who runs
+// them. Relative paths are resolved against the report file's directory.
+func Load(path string) (*Report, error) {
+	var raw struct {
+		Defaults toml.Primitive   `toml:"defaults"`
+		Listings []toml.Primitive `toml:"listing"`
+	}
+	md, err := toml.DecodeFile(path, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid report %s: %w", path, err)
+	}
+
+	// Each listing decodes the defaults afresh: a copy would share their
+	// maps and slices, and one listing's icon_map would leak into the next.
+	defaults := func() (config.Config, error) {
+		cfg := config.NewDefaultConfig()
+		cfg.ColorMode = "never"
+		return cfg, md.PrimitiveDecode(raw.Defaults, &cfg)
+	}
+	if _, err := defaults(); err != nil {
+		return nil, fmt.Errorf("invalid report %s: %w", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	r := &Report{}
+	for i, prim := range raw.Listings {
+		cfg, err := defaults()
+		if err != nil {
+			return nil, fmt.Errorf("invalid report %s: %w", path, err)
+		}
+		listing := Listing{Config: cfg}
+		if err := md.PrimitiveDecode(prim, &listing); err != nil {
+			return nil, fmt.Errorf("invalid report %s: listing %d: %w", path, i+1, err)
+		}
+		if listing.Name == "" {
+			listing.Name = fmt.Sprintf("listing %d", i+1)
+		}
+		if len(listing.Paths) == 0 {
+			return nil, fmt.Errorf("invalid report %s: %s has no paths", path, listing.Name)
+		}
+		for j, p := range listing.Paths {
+			listing.Paths[j] = resolve(dir, p)
+		}
+		listing.Output = resolve(dir, listing.Output)
+		if strings.HasSuffix(listing.Theme, ".toml") {
+			listing.Theme = resolve(dir, listing.Theme)
+		}
+		listing.Config.Output = listing.Output
+		if err := listing.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid report %s: %s: %w", path, listing.Name, err)
+		}
+		r.Listings = append(r.Listings, listing)
+	}
+
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, key := range undecoded {
+			keys[i] = key.String()
+		}
+		sort.Strings(keys)
+		return nil, fmt.Errorf("invalid report %s: unknown key %s", path, strings.Join(keys, ", "))
+	}
+	if len(r.Listings) == 0 {
+		return nil, fmt.Errorf("invalid report %s: no [[listing]] entries", path)
+	}
+	return r, nil
+}
+
+func resolve(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return filepath.Join(dir, path)
+}
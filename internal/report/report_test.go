@@ -0,0 +1,115 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeReport(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "report.toml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeReport(t, `
+[defaults]
+hidden = true
+exclude = ["*.tmp"]
+
+[[listing]]
+name = "uploads"
+paths = ["/srv/uploads"]
+output = "out/uploads.json"
+format = "json"
+recursive = true
+
+[[listing]]
+paths = ["data"]
+hidden = false
+`)
+
+	r, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(r.Listings) != 2 {
+		t.Fatalf("Load() listings = %d, want 2", len(r.Listings))
+	}
+
+	dir := filepath.Dir(path)
+	first, second := r.Listings[0], r.Listings[1]
+	if first.Name != "uploads" || first.Format != "json" || !first.Recursive || !first.ShowHidden {
+		t.Errorf("first listing = %+v", first)
+	}
+	if want := filepath.Join(dir, "out", "uploads.json"); first.Output != want || first.Config.Output != want {
+		t.Errorf("Output = %q / %q, want %q", first.Output, first.Config.Output, want)
+	}
+	if !reflect.DeepEqual(first.ExcludePatterns, []string{"*.tmp"}) || first.MaxDepth != 30 || first.ColorMode != "never" {
+		t.Errorf("defaults not applied: %+v", first.Config)
+	}
+
+	if second.Name != "listing 2" || second.ShowHidden || second.Recursive {
+		t.Errorf("second listing = %+v", second)
+	}
+	if !reflect.DeepEqual(second.Paths, []string{filepath.Join(dir, "data")}) {
+		t.Errorf("Paths = %v", second.Paths)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "no listings", content: "[defaults]\nhidden = true\n", want: "no [[listing]] entries"},
+		{name: "no paths", content: "[[listing]]\nname = \"x\"\n", want: "x has no paths"},
+		{name: "unknown key", content: "[[listing]]\npaths = [\".\"]\nrecursiv = true\n", want: "unknown key listing.recursiv"},
+		{name: "exec rejected", content: "[[listing]]\npaths = [\".\"]\nexec = \"rm {}\"\n", want: "unknown key listing.exec"},
+		{name: "invalid format", content: "[[listing]]\npaths = [\".\"]\nformat = \"xml\"\n", want: "invalid format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeReport(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadListingsIndependent(t *testing.T) {
+	path := writeReport(t, `
+[defaults]
+icon_map = { ".go" = "G" }
+include = ["*.go"]
+
+[[listing]]
+paths = ["a"]
+icon_map = { ".md" = "M" }
+include = ["*.md"]
+
+[[listing]]
+paths = ["b"]
+`)
+
+	r, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	second := r.Listings[1]
+	if want := map[string]string{".go": "G"}; !reflect.DeepEqual(second.IconOverrides, want) {
+		t.Errorf("second listing icon_map = %v, want %v", second.IconOverrides, want)
+	}
+	if want := []string{"*.go"}; !reflect.DeepEqual(second.IncludePatterns, want) {
+		t.Errorf("second listing include = %v, want %v", second.IncludePatterns, want)
+	}
+}
@@ -0,0 +1,289 @@
+// Package shortcut resolves launcher files that point somewhere else:
+// freedesktop .desktop entries, Windows .lnk shortcuts and macOS aliases.
+package shortcut
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+)
+
+// maxAliasSize bounds how large a file without an extension may be before
+// it is no longer considered a candidate macOS alias.
+const maxAliasSize = 1 << 20
+
+// Resolve returns the target of the shortcut at path. Files are only opened
+// when their name or size makes them a plausible shortcut.
+func Resolve(path string, size int64) (string, bool) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".desktop":
+		f, err := os.Open(path)
+		if err != nil {
+			return "", false
+		}
+		defer f.Close()
+		return parseDesktop(f)
+	case ".lnk":
+		data, err := readAtMost(path, maxAliasSize)
+		if err != nil {
+			return "", false
+		}
+		return parseLnk(data)
+	case "":
+		if size <= 0 || size > maxAliasSize {
+			return "", false
+		}
+		data, err := readAtMost(path, maxAliasSize)
+		if err != nil {
+			return "", false
+		}
+		return parseBookmark(data)
+	}
+	return "", false
+}
+
+func readAtMost(path string, limit int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(io.LimitReader(f, limit))
+}
+
+// parseDesktop returns the program of an Application entry or the URL of a
+// Link entry, ignoring Exec field codes such as %U.
+func parseDesktop(r io.Reader) (string, bool) {
+	var (
+		inEntry   bool
+		exec, url string
+	)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inEntry = line == "[Desktop Entry]"
+			continue
+		}
+		if !inEntry {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "Exec":
+			exec = strings.TrimSpace(value)
+		case "URL":
+			url = strings.TrimSpace(value)
+		}
+	}
+
+	if url != "" {
+		return url, true
+	}
+	if exec == "" {
+		return "", false
+	}
+
+	var args []string
+	for _, field := range strings.Fields(exec) {
+		if len(field) == 2 && field[0] == '%' {
+			continue
+		}
+		args = append(args, strings.Trim(field, `"`))
+	}
+	if len(args) == 0 {
+		return "", false
+	}
+	return strings.Join(args, " "), true
+}
+
+const (
+	lnkHeaderSize     = 0x4c
+	lnkHasIDList      = 0x01
+	lnkHasLinkInfo    = 0x02
+	lnkHasName        = 0x04
+	lnkHasRelPath     = 0x08
+	lnkIsUnicode      = 0x80
+	lnkInfoLocalPath  = 0x01
+	lnkInfoHeaderSize = 0x1c
+)
+
+// parseLnk reads the target path of a Shell Link (.lnk) file, preferring
+// the absolute path in LinkInfo over the relative path string.
+func parseLnk(data []byte) (string, bool) {
+	if len(data) < lnkHeaderSize || binary.LittleEndian.Uint32(data) != lnkHeaderSize {
+		return "", false
+	}
+
+	flags := binary.LittleEndian.Uint32(data[0x14:])
+	pos := lnkHeaderSize
+
+	if flags&lnkHasIDList != 0 {
+		if pos+2 > len(data) {
+			return "", false
+		}
+		pos += 2 + int(binary.LittleEndian.Uint16(data[pos:]))
+	}
+
+	if flags&lnkHasLinkInfo != 0 {
+		if pos+lnkInfoHeaderSize > len(data) {
+			return "", false
+		}
+		info := data[pos:]
+		size := int(binary.LittleEndian.Uint32(info))
+		if size < lnkInfoHeaderSize || size > len(info) {
+			return "", false
+		}
+		info = info[:size]
+
+		if binary.LittleEndian.Uint32(info[8:])&lnkInfoLocalPath != 0 {
+			base := cString(info, int(binary.LittleEndian.Uint32(info[0x10:])))
+			suffix := cString(info, int(binary.LittleEndian.Uint32(info[0x18:])))
+			if base != "" {
+				return base + suffix, true
+			}
+		}
+		pos += size
+	}
+
+	if flags&lnkHasRelPath == 0 {
+		return "", false
+	}
+
+	unicode := flags&lnkIsUnicode != 0
+	if flags&lnkHasName != 0 {
+		if _, next, ok := lnkString(data, pos, unicode); ok {
+			pos = next
+		} else {
+			return "", false
+		}
+	}
+	rel, _, ok := lnkString(data, pos, unicode)
+	return rel, ok && rel != ""
+}
+
+func cString(data []byte, offset int) string {
+	if offset <= 0 || offset >= len(data) {
+		return ""
+	}
+	end := bytes.IndexByte(data[offset:], 0)
+	if end < 0 {
+		return ""
+	}
+	return string(data[offset : offset+end])
+}
+
+func lnkString(data []byte, pos int, unicode bool) (string, int, bool) {
+	if pos+2 > len(data) {
+		return "", pos, false
+	}
+	count := int(binary.LittleEndian.Uint16(data[pos:]))
+	pos += 2
+
+	if !unicode {
+		if pos+count > len(data) {
+			return "", pos, false
+		}
+		return string(data[pos : pos+count]), pos + count, true
+	}
+
+	if pos+2*count > len(data) {
+		return "", pos, false
+	}
+	units := make([]uint16, count)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(data[pos+2*i:])
+	}
+	return string(utf16.Decode(units)), pos + 2*count, true
+}
+
+const (
+	bookmarkPathKey     = 0x1004
+	bookmarkTypeString  = 0x0101
+	bookmarkTypeArray   = 0x0601
+	bookmarkTOCMagic    = 0xfffffffe
+	bookmarkMinimumSize = 48
+)
+
+// parseBookmark reads the path components stored in a macOS alias, which
+// modern Finder writes as bookmark data ("book" ... "mark").
+func parseBookmark(data []byte) (string, bool) {
+	if len(data) < bookmarkMinimumSize || string(data[0:4]) != "book" || string(data[8:12]) != "mark" {
+		return "", false
+	}
+
+	headerSize := int(binary.LittleEndian.Uint32(data[12:]))
+	if headerSize < 16 || headerSize+4 > len(data) {
+		return "", false
+	}
+	body := data[headerSize:]
+
+	u32 := func(off int) (int, bool) {
+		if off < 0 || off+4 > len(body) {
+			return 0, false
+		}
+		return int(binary.LittleEndian.Uint32(body[off:])), true
+	}
+
+	// A TOC pointing back at one already read would loop forever.
+	seen := make(map[int]bool)
+	tocOffset, ok := u32(0)
+	for ok && tocOffset > 0 && !seen[tocOffset] {
+		seen[tocOffset] = true
+		magic, _ := u32(tocOffset + 4)
+		count, countOK := u32(tocOffset + 16)
+		if magic != bookmarkTOCMagic || !countOK {
+			return "", false
+		}
+
+		for i := 0; i < count; i++ {
+			entry := tocOffset + 20 + 12*i
+			key, keyOK := u32(entry)
+			itemOffset, itemOK := u32(entry + 4)
+			if !keyOK || !itemOK {
+				return "", false
+			}
+			if key == bookmarkPathKey {
+				return bookmarkPath(body, itemOffset, u32)
+			}
+		}
+		tocOffset, ok = u32(tocOffset + 12)
+	}
+	return "", false
+}
+
+func bookmarkPath(body []byte, offset int, u32 func(int) (int, bool)) (string, bool) {
+	length, ok := u32(offset)
+	kind, kindOK := u32(offset + 4)
+	if !ok || !kindOK || kind != bookmarkTypeArray {
+		return "", false
+	}
+
+	var parts []string
+	for i := 0; i < length/4; i++ {
+		item, ok := u32(offset + 8 + 4*i)
+		if !ok {
+			return "", false
+		}
+		size, sizeOK := u32(item)
+		itemKind, itemKindOK := u32(item + 4)
+		if !sizeOK || !itemKindOK || itemKind != bookmarkTypeString || size < 0 || size > len(body)-item-8 {
+			return "", false
+		}
+		parts = append(parts, string(body[item+8:item+8+size]))
+	}
+	if len(parts) == 0 {
+		return "", false
+	}
+	return "/" + strings.Join(parts, "/"), true
+}
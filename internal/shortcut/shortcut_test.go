@@ -0,0 +1,183 @@
+package shortcut
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+func TestParseDesktop(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+		ok       bool
+	}{
+		{
+			name:     "application",
+			content:  "[Desktop Entry]\nType=Application\nName=Firefox\nExec=/usr/bin/firefox %u\n",
+			expected: "/usr/bin/firefox",
+			ok:       true,
+		},
+		{
+			name:     "arguments kept",
+			content:  "[Desktop Entry]\nExec=\"/opt/my app/run\" --safe %F\n",
+			expected: "/opt/my app/run --safe",
+			ok:       true,
+		},
+		{
+			name:     "link",
+			content:  "[Desktop Entry]\nType=Link\nURL=https://example.com\n",
+			expected: "https://example.com",
+			ok:       true,
+		},
+		{
+			name:    "action section ignored",
+			content: "[Desktop Entry]\nName=X\n[Desktop Action new]\nExec=/usr/bin/x --new\n",
+			ok:      false,
+		},
+		{name: "empty", content: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok := parseDesktop(strings.NewReader(tt.content))
+			if ok != tt.ok || result != tt.expected {
+				t.Errorf("parseDesktop() = %q, %v; want %q, %v", result, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func lnkHeader(flags uint32) []byte {
+	data := make([]byte, lnkHeaderSize)
+	binary.LittleEndian.PutUint32(data, lnkHeaderSize)
+	binary.LittleEndian.PutUint32(data[0x14:], flags)
+	return data
+}
+
+func TestParseLnkLinkInfo(t *testing.T) {
+	data := lnkHeader(lnkHasIDList | lnkHasLinkInfo)
+	data = append(data, 2, 0, 0xaa, 0xbb)
+
+	base := "C:\\Program Files\\App\\app.exe\x00"
+	info := make([]byte, lnkInfoHeaderSize)
+	info = append(info, base...)
+	info = append(info, 0)
+	binary.LittleEndian.PutUint32(info, uint32(len(info)))
+	binary.LittleEndian.PutUint32(info[4:], lnkInfoHeaderSize)
+	binary.LittleEndian.PutUint32(info[8:], lnkInfoLocalPath)
+	binary.LittleEndian.PutUint32(info[0x10:], lnkInfoHeaderSize)
+	binary.LittleEndian.PutUint32(info[0x18:], uint32(len(info)-1))
+	data = append(data, info...)
+
+	result, ok := parseLnk(data)
+	if !ok || result != `C:\Program Files\App\app.exe` {
+		t.Errorf("parseLnk() = %q, %v", result, ok)
+	}
+}
+
+func TestParseLnkRelativePath(t *testing.T) {
+	data := lnkHeader(lnkHasName | lnkHasRelPath | lnkIsUnicode)
+	for _, s := range []string{"My App", `..\bin\app.exe`} {
+		units := utf16.Encode([]rune(s))
+		data = binary.LittleEndian.AppendUint16(data, uint16(len(units)))
+		for _, u := range units {
+			data = binary.LittleEndian.AppendUint16(data, u)
+		}
+	}
+
+	result, ok := parseLnk(data)
+	if !ok || result != `..\bin\app.exe` {
+		t.Errorf("parseLnk() = %q, %v", result, ok)
+	}
+
+	if _, ok := parseLnk([]byte("not a shortcut")); ok {
+		t.Errorf("parseLnk() accepted garbage")
+	}
+}
+
+func TestParseBookmark(t *testing.T) {
+	const headerSize = 48
+	var body []byte
+	item := func(kind uint32, payload []byte) int {
+		offset := len(body)
+		body = binary.LittleEndian.AppendUint32(body, uint32(len(payload)))
+		body = binary.LittleEndian.AppendUint32(body, kind)
+		body = append(body, payload...)
+		for len(body)%4 != 0 {
+			body = append(body, 0)
+		}
+		return offset
+	}
+
+	body = make([]byte, 4)
+	var offsets []byte
+	for _, part := range []string{"Users", "me", "Projects"} {
+		offsets = binary.LittleEndian.AppendUint32(offsets, uint32(item(bookmarkTypeString, []byte(part))))
+	}
+	pathItem := item(bookmarkTypeArray, offsets)
+
+	toc := len(body)
+	binary.LittleEndian.PutUint32(body, uint32(toc))
+	for _, v := range []uint32{0, bookmarkTOCMagic, 1, 0, 1, bookmarkPathKey, uint32(pathItem), 0} {
+		body = binary.LittleEndian.AppendUint32(body, v)
+	}
+
+	data := make([]byte, headerSize)
+	copy(data, "book")
+	copy(data[8:], "mark")
+	binary.LittleEndian.PutUint32(data[12:], headerSize)
+	data = append(data, body...)
+
+	result, ok := parseBookmark(data)
+	if !ok || result != "/Users/me/Projects" {
+		t.Errorf("parseBookmark() = %q, %v", result, ok)
+	}
+
+	if _, ok := parseBookmark(data[:40]); ok {
+		t.Errorf("parseBookmark() accepted truncated data")
+	}
+}
+
+func TestParseLnkShortLinkInfo(t *testing.T) {
+	// A LinkInfo claiming fewer bytes than its own header.
+	data := lnkHeader(lnkHasLinkInfo)
+	info := make([]byte, lnkInfoHeaderSize)
+	binary.LittleEndian.PutUint32(info, 4)
+	data = append(data, info...)
+
+	if result, ok := parseLnk(data); ok {
+		t.Errorf("parseLnk() = %q, want rejected", result)
+	}
+}
+
+func TestParseBookmarkTOCCycle(t *testing.T) {
+	const headerSize = 48
+	body := binary.LittleEndian.AppendUint32(nil, 4)
+	// One TOC whose next TOC is itself, without the path key.
+	for _, v := range []uint32{0, bookmarkTOCMagic, 1, 4, 1, 0x2000, 0, 0} {
+		body = binary.LittleEndian.AppendUint32(body, v)
+	}
+	data := make([]byte, headerSize)
+	copy(data, "book")
+	copy(data[8:], "mark")
+	binary.LittleEndian.PutUint32(data[12:], headerSize)
+	data = append(data, body...)
+
+	done := make(chan bool, 1)
+	go func() {
+		_, ok := parseBookmark(data)
+		done <- ok
+	}()
+	select {
+	case ok := <-done:
+		if ok {
+			t.Error("parseBookmark() accepted a cyclic TOC")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("parseBookmark() loops on a cyclic TOC")
+	}
+}
@@ -0,0 +1,107 @@
+// Package snapshot remembers what a directory looked like the last time it
+// was listed with --changed-since-last, so the next run can show only what
+// is new.
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+
+	"github.com/ipanardian/lu-hut/internal/model"
+	"github.com/ipanardian/lu-hut/internal/state"
+)
+
+type Entry struct {
+	Size    int64 `json:"size"`
+	ModTime int64 `json:"mtime"`
+}
+
+// Snapshot maps entry names of one directory to their size and mtime.
+type Snapshot map[string]Entry
+
+// Take records files as they are now.
+func Take(files []model.FileEntry) Snapshot {
+	s := make(Snapshot, len(files))
+	for _, file := range files {
+		s[file.Name] = Entry{Size: file.Size, ModTime: file.ModTime.UnixNano()}
+	}
+	return s
+}
+
+// Changed reports whether file was added or modified since s was taken.
+// Directories only count as modified when their own mtime moved, which
+// happens when entries are added to or removed from them.
+func (s Snapshot) Changed(file model.FileEntry) bool {
+	prev, ok := s[file.Name]
+	if !ok {
+		return true
+	}
+	if prev.ModTime != file.ModTime.UnixNano() {
+		return true
+	}
+	return !file.IsDir && prev.Size != file.Size
+}
+
+// Removed counts entries in s that are missing from now.
+func (s Snapshot) Removed(now Snapshot) int {
+	removed := 0
+	for name := range s {
+		if _, ok := now[name]; !ok {
+			removed++
+		}
+	}
+	return removed
+}
+
+// Load returns the snapshot stored for dir. The second result is false when
+// the directory has never been snapshotted.
+func Load(dir string) (Snapshot, bool, error) {
+	path, err := location(dir)
+	if err != nil {
+		return nil, false, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	var stored struct {
+		Entries Snapshot `json:"entries"`
+	}
+	if err := json.Unmarshal(data, &stored); err != nil {
+		// A corrupt snapshot is treated like a missing one.
+		return nil, false, nil
+	}
+	return stored.Entries, true, nil
+}
+
+// Save stores s as the snapshot of dir, replacing the previous one.
+func Save(dir string, s Snapshot) error {
+	path, err := location(dir)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(struct {
+		Dir     string   `json:"dir"`
+		Entries Snapshot `json:"entries"`
+	}{dir, s})
+	if err != nil {
+		return err
+	}
+
+	return state.WriteFile(path, data, 0o644)
+}
+
+// location names the snapshot file after a hash of the absolute directory
+// path, so any directory maps to a flat, filesystem-safe file name.
+func location(dir string) (string, error) {
+	sum := sha256.Sum256([]byte(dir))
+	return state.CachePath("snapshots", hex.EncodeToString(sum[:16])+".json")
+}
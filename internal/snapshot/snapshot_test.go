@@ -0,0 +1,62 @@
+package snapshot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ipanardian/lu-hut/internal/model"
+)
+
+func TestChanged(t *testing.T) {
+	then := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := Take([]model.FileEntry{
+		{Name: "same.txt", Size: 10, ModTime: then},
+		{Name: "grown.log", Size: 10, ModTime: then},
+		{Name: "touched.txt", Size: 10, ModTime: then},
+		{Name: "dir", Size: 4096, ModTime: then, IsDir: true},
+		{Name: "gone.txt", Size: 1, ModTime: then},
+	})
+
+	tests := []struct {
+		file    model.FileEntry
+		changed bool
+	}{
+		{file: model.FileEntry{Name: "same.txt", Size: 10, ModTime: then}, changed: false},
+		{file: model.FileEntry{Name: "grown.log", Size: 20, ModTime: then}, changed: true},
+		{file: model.FileEntry{Name: "touched.txt", Size: 10, ModTime: then.Add(time.Second)}, changed: true},
+		{file: model.FileEntry{Name: "dir", Size: 8192, ModTime: then, IsDir: true}, changed: false},
+		{file: model.FileEntry{Name: "new.txt", Size: 1, ModTime: then}, changed: true},
+	}
+
+	var now []model.FileEntry
+	for _, tt := range tests {
+		now = append(now, tt.file)
+		t.Run(tt.file.Name, func(t *testing.T) {
+			if got := s.Changed(tt.file); got != tt.changed {
+				t.Errorf("Changed(%s) = %v, want %v", tt.file.Name, got, tt.changed)
+			}
+		})
+	}
+
+	if removed := s.Removed(Take(now)); removed != 1 {
+		t.Errorf("Removed() = %d, want 1", removed)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", "")
+
+	if _, ok, err := Load("/srv/data"); ok || err != nil {
+		t.Fatalf("Load() before Save = %v, %v; want no snapshot", ok, err)
+	}
+
+	want := Snapshot{"a.txt": {Size: 3, ModTime: 42}}
+	if err := Save("/srv/data", want); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	got, ok, err := Load("/srv/data")
+	if !ok || err != nil || got["a.txt"] != want["a.txt"] {
+		t.Errorf("Load() = %v, %v, %v; want %v", got, ok, err, want)
+	}
+}
@@ -0,0 +1,53 @@
+// Package sniff detects a file's MIME type from its first bytes, for
+// --mime, so files without an extension, or with one that lies, are still
+// recognized.
+package sniff
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+)
+
+// SampleSize is how much of each file is read, all the content detection
+// algorithm looks at.
+const SampleSize = 512
+
+// Type returns the MIME type of the file at path without parameters, e.g.
+// "image/png" or "text/plain", or "" when the file cannot be read.
+func Type(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	buf := make([]byte, SampleSize)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return ""
+	}
+	return Detect(buf[:n])
+}
+
+// Detect returns the MIME type of data without parameters.
+func Detect(data []byte) string {
+	kind, _, _ := strings.Cut(http.DetectContentType(data), ";")
+	return strings.TrimSpace(kind)
+}
+
+// Match reports whether kind matches any of the patterns, which are globs
+// such as "image/*" or exact types such as "application/pdf".
+func Match(patterns []string, kind string) bool {
+	if kind == "" {
+		return false
+	}
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, kind); matched {
+			return true
+		}
+	}
+	return false
+}
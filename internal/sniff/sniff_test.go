@@ -0,0 +1,50 @@
+package sniff
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestType(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]struct {
+		content  string
+		expected string
+	}{
+		"photo.txt": {"\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR", "image/png"},
+		"README":    {"plain words\n", "text/plain"},
+		"doc":       {"%PDF-1.7\n", "application/pdf"},
+	}
+	for name, f := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(f.content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if got := Type(path); got != f.expected {
+			t.Errorf("Type(%s) = %q, want %q", name, got, f.expected)
+		}
+	}
+
+	if got := Type(filepath.Join(dir, "missing")); got != "" {
+		t.Errorf("Type(missing) = %q, want empty", got)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		kind     string
+		expected bool
+	}{
+		{[]string{"image/*"}, "image/png", true},
+		{[]string{"image/*"}, "text/plain", false},
+		{[]string{"text/*", "application/pdf"}, "application/pdf", true},
+		{[]string{"*"}, "", false},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.patterns, tt.kind); got != tt.expected {
+			t.Errorf("Match(%v, %q) = %v, want %v", tt.patterns, tt.kind, got, tt.expected)
+		}
+	}
+}
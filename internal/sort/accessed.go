@@ -0,0 +1,20 @@
+package sort
+
+import (
+	"sort"
+
+	"github.com/ipanardian/lu-hut/internal/model"
+)
+
+// Accessed orders entries by last access time, most recently read first,
+// so reversing it puts the files nobody has opened in months on top.
+type Accessed struct{}
+
+func (s *Accessed) Sort(files []model.FileEntry, reverse bool) {
+	sort.Slice(files, func(i, j int) bool {
+		if reverse {
+			return files[i].Accessed.Before(files[j].Accessed)
+		}
+		return files[i].Accessed.After(files[j].Accessed)
+	})
+}
@@ -0,0 +1,20 @@
+package sort
+
+import (
+	"sort"
+
+	"github.com/ipanardian/lu-hut/internal/model"
+)
+
+// Created orders entries by creation time, newest first. Entries whose
+// filesystem keeps no creation time carry their modification time instead.
+type Created struct{}
+
+func (s *Created) Sort(files []model.FileEntry, reverse bool) {
+	sort.Slice(files, func(i, j int) bool {
+		if reverse {
+			return files[i].Created.Before(files[j].Created)
+		}
+		return files[i].Created.After(files[j].Created)
+	})
+}
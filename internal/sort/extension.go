@@ -3,23 +3,26 @@ package sort
 import (
 	"path/filepath"
 	"sort"
-	"strings"
 
 	"github.com/ipanardian/lu-hut/internal/model"
 )
 
-type Extension struct{}
+type Extension struct {
+	// Mixed sorts directories among the files instead of before them.
+	Mixed bool
+	// CaseSensitive compares names byte by byte, like ls in the C locale.
+	CaseSensitive bool
+}
 
 func (s *Extension) Sort(files []model.FileEntry, reverse bool) {
 	sort.Slice(files, func(i, j int) bool {
-		if files[i].IsDir != files[j].IsDir {
-			return files[i].IsDir
+		if less, ok := groupDirs(files[i], files[j], s.Mixed); ok {
+			return less
 		}
-		extI := strings.ToLower(filepath.Ext(files[i].Name))
-		extJ := strings.ToLower(filepath.Ext(files[j].Name))
+		result := compareNames(filepath.Ext(files[i].Name), filepath.Ext(files[j].Name), s.CaseSensitive)
 		if reverse {
-			return extI > extJ
+			return result > 0
 		}
-		return extI < extJ
+		return result < 0
 	})
 }
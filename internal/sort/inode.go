@@ -0,0 +1,33 @@
+package sort
+
+import (
+	"sort"
+
+	"github.com/ipanardian/lu-hut/internal/model"
+)
+
+// Inode orders entries by inode number, lowest first, which groups files
+// created together and lines up hard links to the same file.
+type Inode struct{}
+
+func (s *Inode) Sort(files []model.FileEntry, reverse bool) {
+	sort.SliceStable(files, func(i, j int) bool {
+		if reverse {
+			return files[i].Inode > files[j].Inode
+		}
+		return files[i].Inode < files[j].Inode
+	})
+}
+
+// Links orders entries by hard-link count, most first, so files that are
+// hard-linked from elsewhere stand out.
+type Links struct{}
+
+func (s *Links) Sort(files []model.FileEntry, reverse bool) {
+	sort.SliceStable(files, func(i, j int) bool {
+		if reverse {
+			return files[i].Links < files[j].Links
+		}
+		return files[i].Links > files[j].Links
+	})
+}
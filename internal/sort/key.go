@@ -0,0 +1,142 @@
+package sort
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/ipanardian/lu-hut/internal/model"
+)
+
+// Key is what an order compares for one entry, shown with --json-verbose
+// so other tools can reproduce lu's ordering.
+type Key struct {
+	// Order is the sort order, Reverse whether it was flipped.
+	Order   string `json:"order"`
+	Reverse bool   `json:"reverse,omitempty"`
+	// Group is 0 for entries in the first block and 1 for those after it:
+	// files after directories, or directories after files with DirsLast.
+	// Orders that mix the two keep everything in 0.
+	Group int `json:"group"`
+	// Value is what the order compares within a group: the normalized name
+	// or extension, the size in bytes, a time, an inode or link count, or
+	// for natural the name with every number zero-padded, so it sorts the
+	// same as plain text. None compares nothing and leaves it null.
+	Value any `json:"value"`
+	// Name is the name as compared, lowercased unless sorting is case
+	// sensitive. The owner and group orders fall back to it on ties.
+	Name string `json:"name"`
+	Ext  string `json:"ext,omitempty"`
+	// Size is the size the size order saw, the directory total with --du.
+	Size int64 `json:"size"`
+	// Index is the entry's position among its siblings in the output.
+	Index int `json:"index"`
+}
+
+// Keys returns the Key of every entry in files, which New(order, opts)
+// has already sorted.
+func Keys(files []model.FileEntry, order string, opts Options, reverse bool) []Key {
+	order = keyOrder(order)
+	keys := make([]Key, len(files))
+	for i, file := range files {
+		name, ext := file.Name, filepath.Ext(file.Name)
+		if !opts.CaseSensitive {
+			name, ext = strings.ToLower(name), strings.ToLower(ext)
+		}
+		keys[i] = Key{
+			Order:   order,
+			Reverse: reverse,
+			Group:   placement(file, order, opts),
+			Value:   keyValue(file, order, name, ext),
+			Name:    name,
+			Ext:     ext,
+			Size:    file.Size,
+			Index:   i,
+		}
+	}
+	return keys
+}
+
+// keyOrder maps order to the one New picks for it.
+func keyOrder(order string) string {
+	switch order {
+	case "size", "ext", "time", "created", "accessed", "natural", "owner", "group", "inode", "links", "none":
+		return order
+	}
+	return "name"
+}
+
+// placement mirrors groupDirs and dirsLast.
+func placement(file model.FileEntry, order string, opts Options) int {
+	switch opts.Dirs {
+	case DirsLast:
+		if file.IsDir {
+			return 1
+		}
+		return 0
+	case DirsMixed:
+		return 0
+	}
+	switch order {
+	case "time", "created", "accessed", "inode", "links", "none":
+		return 0
+	}
+	if file.IsDir {
+		return 0
+	}
+	return 1
+}
+
+func keyValue(file model.FileEntry, order, name, ext string) any {
+	switch order {
+	case "size":
+		return file.Size
+	case "ext":
+		return ext
+	case "time":
+		return file.ModTime
+	case "created":
+		return file.Created
+	case "accessed":
+		return file.Accessed
+	case "natural":
+		return naturalKey(name)
+	case "owner":
+		return strings.ToLower(file.Author)
+	case "group":
+		return strings.ToLower(file.Group)
+	case "inode":
+		return file.Inode
+	case "links":
+		return file.Links
+	case "none":
+		return nil
+	}
+	return name
+}
+
+// naturalWidth is how far naturalKey pads numbers. Longer numbers still
+// compare by value in lu, but not in the padded key.
+const naturalWidth = 20
+
+// naturalKey pads every run of digits in name to naturalWidth, without its
+// leading zeros, so comparing keys as text orders them like compareNatural.
+func naturalKey(name string) string {
+	var b strings.Builder
+	for i := 0; i < len(name); {
+		if !isDigit(name[i]) {
+			b.WriteByte(name[i])
+			i++
+			continue
+		}
+		start := i
+		for i < len(name) && isDigit(name[i]) {
+			i++
+		}
+		num := strings.TrimLeft(name[start:i], "0")
+		if pad := naturalWidth - len(num); pad > 0 {
+			b.WriteString(strings.Repeat("0", pad))
+		}
+		b.WriteString(num)
+	}
+	return b.String()
+}
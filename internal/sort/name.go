@@ -7,17 +7,31 @@ import (
 	"github.com/ipanardian/lu-hut/internal/model"
 )
 
-type Name struct{}
+type Name struct {
+	// Mixed sorts directories among the files instead of before them.
+	Mixed bool
+	// CaseSensitive compares names byte by byte, like ls in the C locale.
+	CaseSensitive bool
+}
 
 func (s *Name) Sort(files []model.FileEntry, reverse bool) {
 	sort.Slice(files, func(i, j int) bool {
-		if files[i].IsDir != files[j].IsDir {
-			return files[i].IsDir
+		if less, ok := groupDirs(files[i], files[j], s.Mixed); ok {
+			return less
 		}
-		result := strings.Compare(strings.ToLower(files[i].Name), strings.ToLower(files[j].Name))
+		result := compareNames(files[i].Name, files[j].Name, s.CaseSensitive)
 		if reverse {
 			return result > 0
 		}
 		return result < 0
 	})
 }
+
+// compareNames compares two names ignoring case, or byte by byte when
+// caseSensitive is set, which puts "Zebra" before "apple" as POSIX ls does.
+func compareNames(a, b string, caseSensitive bool) int {
+	if caseSensitive {
+		return strings.Compare(a, b)
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
@@ -0,0 +1,93 @@
+package sort
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ipanardian/lu-hut/internal/model"
+)
+
+// Natural orders names the way people read them: runs of digits compare by
+// their numeric value, so file2 comes before file10 and v1.9 before v1.10.
+type Natural struct {
+	// Mixed sorts directories among the files instead of before them.
+	Mixed bool
+	// CaseSensitive compares names byte by byte, like ls in the C locale.
+	CaseSensitive bool
+}
+
+func (s *Natural) Sort(files []model.FileEntry, reverse bool) {
+	sort.Slice(files, func(i, j int) bool {
+		if less, ok := groupDirs(files[i], files[j], s.Mixed); ok {
+			return less
+		}
+		a, b := files[i].Name, files[j].Name
+		if !s.CaseSensitive {
+			a, b = strings.ToLower(a), strings.ToLower(b)
+		}
+		result := compareNatural(a, b)
+		// Names that only differ in case still need a fixed order.
+		if result == 0 {
+			result = strings.Compare(files[i].Name, files[j].Name)
+		}
+		if reverse {
+			return result > 0
+		}
+		return result < 0
+	})
+}
+
+// compareNatural compares a and b chunk by chunk, numerically where both
+// have digits. Numbers that only differ in leading zeros fall back to a
+// plain comparison so the order stays total.
+func compareNatural(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if !isDigit(a[i]) || !isDigit(b[j]) {
+			if a[i] != b[j] {
+				return compareBytes(a[i], b[j])
+			}
+			i++
+			j++
+			continue
+		}
+
+		startA, startB := i, j
+		for i < len(a) && isDigit(a[i]) {
+			i++
+		}
+		for j < len(b) && isDigit(b[j]) {
+			j++
+		}
+		numA := strings.TrimLeft(a[startA:i], "0")
+		numB := strings.TrimLeft(b[startB:j], "0")
+		if len(numA) != len(numB) {
+			if len(numA) < len(numB) {
+				return -1
+			}
+			return 1
+		}
+		if result := strings.Compare(numA, numB); result != 0 {
+			return result
+		}
+	}
+
+	switch {
+	case len(a)-i < len(b)-j:
+		return -1
+	case len(a)-i > len(b)-j:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func compareBytes(a, b byte) int {
+	if a < b {
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,17 @@
+package sort
+
+import (
+	"slices"
+
+	"github.com/ipanardian/lu-hut/internal/model"
+)
+
+// None keeps entries in the order the directory was read, like ls -U.
+// Reversing still flips that order.
+type None struct{}
+
+func (s *None) Sort(files []model.FileEntry, reverse bool) {
+	if reverse {
+		slices.Reverse(files)
+	}
+}
@@ -0,0 +1,50 @@
+package sort
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ipanardian/lu-hut/internal/model"
+)
+
+// Owner groups entries by the user owning them, then by name within each
+// owner, so shared directories can be reviewed one person at a time.
+type Owner struct {
+	// Mixed sorts directories among the files instead of before them.
+	Mixed bool
+	// CaseSensitive compares names byte by byte, like ls in the C locale.
+	CaseSensitive bool
+}
+
+func (s *Owner) Sort(files []model.FileEntry, reverse bool) {
+	sortByOwnership(files, reverse, s.Mixed, s.CaseSensitive, func(file model.FileEntry) string { return file.Author })
+}
+
+// Group is Owner for the owning group.
+type Group struct {
+	// Mixed sorts directories among the files instead of before them.
+	Mixed bool
+	// CaseSensitive compares names byte by byte, like ls in the C locale.
+	CaseSensitive bool
+}
+
+func (s *Group) Sort(files []model.FileEntry, reverse bool) {
+	sortByOwnership(files, reverse, s.Mixed, s.CaseSensitive, func(file model.FileEntry) string { return file.Group })
+}
+
+// sortByOwnership orders by the key, then by name. reverse only flips the
+// key order, so names stay alphabetical within each owner.
+func sortByOwnership(files []model.FileEntry, reverse, mixed, caseSensitive bool, key func(model.FileEntry) string) {
+	sort.SliceStable(files, func(i, j int) bool {
+		if less, ok := groupDirs(files[i], files[j], mixed); ok {
+			return less
+		}
+		if result := strings.Compare(strings.ToLower(key(files[i])), strings.ToLower(key(files[j]))); result != 0 {
+			if reverse {
+				return result > 0
+			}
+			return result < 0
+		}
+		return compareNames(files[i].Name, files[j].Name, caseSensitive) < 0
+	})
+}
@@ -0,0 +1,18 @@
+package sort
+
+import "os"
+
+// ReadDir reads a directory for listing in order. Entries come back in name
+// order, except for none, which keeps the order the filesystem returns and
+// so saves the sort os.ReadDir would do on huge directories.
+func ReadDir(path, order string) ([]os.DirEntry, error) {
+	if order != "none" {
+		return os.ReadDir(path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return f.ReadDir(-1)
+}
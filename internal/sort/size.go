@@ -6,12 +6,15 @@ import (
 	"github.com/ipanardian/lu-hut/internal/model"
 )
 
-type Size struct{}
+type Size struct {
+	// Mixed sorts directories among the files instead of before them.
+	Mixed bool
+}
 
 func (s *Size) Sort(files []model.FileEntry, reverse bool) {
 	sort.Slice(files, func(i, j int) bool {
-		if files[i].IsDir != files[j].IsDir {
-			return files[i].IsDir
+		if less, ok := groupDirs(files[i], files[j], s.Mixed); ok {
+			return less
 		}
 		if reverse {
 			return files[i].Size < files[j].Size
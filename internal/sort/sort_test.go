@@ -1,6 +1,10 @@
 package sort
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
 	"testing"
 	"time"
 
@@ -86,3 +90,296 @@ func TestExtensionSortStrategy(t *testing.T) {
 		}
 	}
 }
+
+func TestNaturalSortStrategy(t *testing.T) {
+	strategy := &Natural{}
+
+	files := []model.FileEntry{
+		{Name: "file10.txt", IsDir: false},
+		{Name: "v1.10", IsDir: true},
+		{Name: "file2.txt", IsDir: false},
+		{Name: "v1.9", IsDir: true},
+		{Name: "File1.txt", IsDir: false},
+		{Name: "file02.txt", IsDir: false},
+	}
+
+	strategy.Sort(files, false)
+
+	expected := []string{"v1.9", "v1.10", "File1.txt", "file02.txt", "file2.txt", "file10.txt"}
+	for i, f := range files {
+		if f.Name != expected[i] {
+			t.Errorf("expected %s at index %d, got %s", expected[i], i, f.Name)
+		}
+	}
+}
+
+func TestNaturalSortCaseTieBreak(t *testing.T) {
+	// Whatever order they come in, names equal but for case end up the
+	// same way round.
+	for _, names := range [][]string{{"readme", "README", "ReadMe"}, {"ReadMe", "readme", "README"}} {
+		files := make([]model.FileEntry, len(names))
+		for i, name := range names {
+			files[i] = model.FileEntry{Name: name}
+		}
+		(&Natural{}).Sort(files, false)
+		var got []string
+		for _, f := range files {
+			got = append(got, f.Name)
+		}
+		if want := []string{"README", "ReadMe", "readme"}; !slices.Equal(got, want) {
+			t.Errorf("Sort(%v) = %v, want %v", names, got, want)
+		}
+	}
+}
+
+func TestCompareNatural(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"file2", "file10", -1},
+		{"file10", "file2", 1},
+		{"a", "a1", -1},
+		{"img007", "img7", -1},
+		{"v2.0.0", "v2.0.0", 0},
+		{"report-2024-01", "report-2023-12", 1},
+		{"abc", "abd", -1},
+	}
+
+	for _, tt := range tests {
+		if result := compareNatural(tt.a, tt.b); result != tt.expected {
+			t.Errorf("compareNatural(%q, %q) = %d, want %d", tt.a, tt.b, result, tt.expected)
+		}
+	}
+}
+
+func TestCreatedSortStrategy(t *testing.T) {
+	strategy := &Created{}
+
+	now := time.Now()
+	files := []model.FileEntry{
+		{Name: "old.txt", Created: now.Add(-24 * time.Hour), ModTime: now},
+		{Name: "newest.txt", Created: now, ModTime: now.Add(-48 * time.Hour)},
+		{Name: "new.txt", Created: now.Add(-1 * time.Hour)},
+	}
+
+	strategy.Sort(files, false)
+
+	expected := []string{"newest.txt", "new.txt", "old.txt"}
+	for i, f := range files {
+		if f.Name != expected[i] {
+			t.Errorf("expected %s at index %d, got %s", expected[i], i, f.Name)
+		}
+	}
+}
+
+func TestAccessedSortStrategy(t *testing.T) {
+	strategy := &Accessed{}
+
+	now := time.Now()
+	files := []model.FileEntry{
+		{Name: "recent.txt", Accessed: now},
+		{Name: "stale.txt", Accessed: now.Add(-90 * 24 * time.Hour)},
+		{Name: "week.txt", Accessed: now.Add(-7 * 24 * time.Hour)},
+	}
+
+	strategy.Sort(files, true)
+
+	expected := []string{"stale.txt", "week.txt", "recent.txt"}
+	for i, f := range files {
+		if f.Name != expected[i] {
+			t.Errorf("expected %s at index %d, got %s", expected[i], i, f.Name)
+		}
+	}
+}
+
+func TestOwnerSortStrategy(t *testing.T) {
+	strategy := &Owner{}
+
+	files := []model.FileEntry{
+		{Name: "b.txt", Author: "root", Group: "staff"},
+		{Name: "notes", Author: "zoe", IsDir: true},
+		{Name: "c.txt", Author: "alice", Group: "wheel"},
+		{Name: "a.txt", Author: "root", Group: "adm"},
+	}
+
+	strategy.Sort(files, false)
+	expected := []string{"notes", "c.txt", "a.txt", "b.txt"}
+	for i, f := range files {
+		if f.Name != expected[i] {
+			t.Errorf("expected %s at index %d, got %s", expected[i], i, f.Name)
+		}
+	}
+
+	strategy.Sort(files, true)
+	expected = []string{"notes", "a.txt", "b.txt", "c.txt"}
+	for i, f := range files {
+		if f.Name != expected[i] {
+			t.Errorf("reverse: expected %s at index %d, got %s", expected[i], i, f.Name)
+		}
+	}
+
+	(&Group{}).Sort(files, false)
+	expected = []string{"notes", "a.txt", "b.txt", "c.txt"}
+	for i, f := range files {
+		if f.Name != expected[i] {
+			t.Errorf("group: expected %s at index %d, got %s", expected[i], i, f.Name)
+		}
+	}
+}
+
+func TestDirPlacement(t *testing.T) {
+	entries := func() []model.FileEntry {
+		return []model.FileEntry{
+			{Name: "b.txt"},
+			{Name: "c", IsDir: true},
+			{Name: "a", IsDir: true},
+			{Name: "d.txt"},
+		}
+	}
+
+	tests := []struct {
+		order    string
+		dirs     string
+		expected []string
+	}{
+		{order: "name", dirs: DirsFirst, expected: []string{"a", "c", "b.txt", "d.txt"}},
+		{order: "name", dirs: DirsLast, expected: []string{"b.txt", "d.txt", "a", "c"}},
+		{order: "name", dirs: DirsMixed, expected: []string{"a", "b.txt", "c", "d.txt"}},
+		{order: "none", dirs: DirsLast, expected: []string{"b.txt", "d.txt", "c", "a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.order+"/"+tt.dirs, func(t *testing.T) {
+			files := entries()
+			New(tt.order, Options{Dirs: tt.dirs}).Sort(files, false)
+			for i, f := range files {
+				if f.Name != tt.expected[i] {
+					t.Errorf("expected %s at index %d, got %s", tt.expected[i], i, f.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestCaseSensitiveSort(t *testing.T) {
+	names := func(files []model.FileEntry) []string {
+		var result []string
+		for _, f := range files {
+			result = append(result, f.Name)
+		}
+		return result
+	}
+	entries := func() []model.FileEntry {
+		return []model.FileEntry{{Name: "main.go"}, {Name: "README.md"}, {Name: "go.mod"}, {Name: "Makefile"}}
+	}
+
+	files := entries()
+	New("name", Options{}).Sort(files, false)
+	if got, want := names(files), []string{"go.mod", "main.go", "Makefile", "README.md"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("case-insensitive: got %v, want %v", got, want)
+	}
+
+	files = entries()
+	New("name", Options{CaseSensitive: true}).Sort(files, false)
+	if got, want := names(files), []string{"Makefile", "README.md", "go.mod", "main.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("case-sensitive: got %v, want %v", got, want)
+	}
+}
+
+func TestInodeAndLinksSort(t *testing.T) {
+	entries := func() []model.FileEntry {
+		return []model.FileEntry{
+			{Name: "b", Inode: 30, Links: 1},
+			{Name: "dir", IsDir: true, Inode: 20, Links: 2},
+			{Name: "a", Inode: 10, Links: 3},
+		}
+	}
+	names := func(files []model.FileEntry) []string {
+		var result []string
+		for _, f := range files {
+			result = append(result, f.Name)
+		}
+		return result
+	}
+
+	files := entries()
+	New("inode", Options{}).Sort(files, false)
+	if got, want := names(files), []string{"a", "dir", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("inode: got %v, want %v", got, want)
+	}
+
+	files = entries()
+	New("links", Options{}).Sort(files, false)
+	if got, want := names(files), []string{"a", "dir", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("links: got %v, want %v", got, want)
+	}
+
+	files = entries()
+	New("links", Options{}).Sort(files, true)
+	if got, want := names(files), []string{"b", "dir", "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("links reversed: got %v, want %v", got, want)
+	}
+}
+
+func TestReadDir(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"c", "a", "b"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	names := func(order string) []string {
+		entries, err := ReadDir(dir, order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var result []string
+		for _, e := range entries {
+			result = append(result, e.Name())
+		}
+		return result
+	}
+
+	if got, want := names("size"), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("size: got %v, want %v", got, want)
+	}
+	// The filesystem decides the order for none; only the entries are fixed.
+	got := names("none")
+	slices.Sort(got)
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("none: got %v, want %v", got, want)
+	}
+}
+
+func TestKeys(t *testing.T) {
+	files := []model.FileEntry{
+		{Name: "File10.txt"},
+		{Name: "src", IsDir: true},
+		{Name: "file9.TXT", Size: 5},
+		{Name: "file009b.txt"},
+	}
+	New("natural", Options{}).Sort(files, false)
+	keys := Keys(files, "natural", Options{}, false)
+	for i := 1; i < len(keys); i++ {
+		prev, cur := keys[i-1], keys[i]
+		if prev.Group > cur.Group || prev.Group == cur.Group && prev.Value.(string) > cur.Value.(string) {
+			t.Errorf("keys %d and %d are out of order: %+v, %+v", i-1, i, prev, cur)
+		}
+		if cur.Index != i {
+			t.Errorf("keys[%d].Index = %d", i, cur.Index)
+		}
+	}
+	if keys[0].Group != 0 || keys[0].Name != "src" || keys[1].Group != 1 {
+		t.Errorf("directories should be in group 0 and files in 1: %+v", keys)
+	}
+
+	keys = Keys([]model.FileEntry{{Name: "A.TXT", Size: 42}}, "", Options{Dirs: DirsLast}, true)
+	if k := keys[0]; k.Order != "name" || !k.Reverse || k.Value != "a.txt" || k.Ext != ".txt" || k.Size != 42 || k.Group != 0 {
+		t.Errorf("Keys() = %+v", k)
+	}
+	keys = Keys([]model.FileEntry{{Name: "A.TXT", Size: 42}}, "size", Options{CaseSensitive: true}, false)
+	if k := keys[0]; k.Value != int64(42) || k.Name != "A.TXT" {
+		t.Errorf("Keys() = %+v", k)
+	}
+}
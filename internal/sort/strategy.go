@@ -1,8 +1,104 @@
 // Package sort provides strategies for sorting file entries.
 package sort
 
-import "github.com/ipanardian/lu-hut/internal/model"
+import (
+	"slices"
+
+	"github.com/ipanardian/lu-hut/internal/model"
+)
 
 type Strategy interface {
 	Sort(files []model.FileEntry, reverse bool)
 }
+
+// Options adjust how New's strategies order entries.
+type Options struct {
+	// Dirs places directories: DirsFirst, DirsLast or DirsMixed.
+	Dirs string
+	// CaseSensitive compares names byte by byte instead of ignoring case.
+	CaseSensitive bool
+}
+
+// Directory placements for Options.Dirs.
+const (
+	// DirsFirst keeps each order's usual placement: directories before
+	// files, except for the time, inode and link orders and none, which
+	// mix them.
+	DirsFirst = ""
+	// DirsLast puts directories after files.
+	DirsLast = "last"
+	// DirsMixed sorts directories among the files.
+	DirsMixed = "mixed"
+)
+
+// New returns the strategy for a canonical sort order as reported by
+// config.Config.SortOrder, adjusted by opts. Unknown orders sort by name.
+func New(order string, opts Options) Strategy {
+	mixed, cs := opts.Dirs == DirsMixed, opts.CaseSensitive
+
+	var strategy Strategy
+	switch order {
+	case "size":
+		strategy = &Size{Mixed: mixed}
+	case "ext":
+		strategy = &Extension{Mixed: mixed, CaseSensitive: cs}
+	case "time":
+		strategy = &Time{}
+	case "created":
+		strategy = &Created{}
+	case "accessed":
+		strategy = &Accessed{}
+	case "natural":
+		strategy = &Natural{Mixed: mixed, CaseSensitive: cs}
+	case "owner":
+		strategy = &Owner{Mixed: mixed, CaseSensitive: cs}
+	case "group":
+		strategy = &Group{Mixed: mixed, CaseSensitive: cs}
+	case "inode":
+		strategy = &Inode{}
+	case "links":
+		strategy = &Links{}
+	case "none":
+		strategy = &None{}
+	default:
+		strategy = &Name{Mixed: mixed, CaseSensitive: cs}
+	}
+
+	if opts.Dirs == DirsLast {
+		return &dirsLast{strategy}
+	}
+	return strategy
+}
+
+// groupDirs orders a directory before a file unless mixed is set. ok is
+// false when that does not decide, leaving the comparison to the caller.
+func groupDirs(a, b model.FileEntry, mixed bool) (less, ok bool) {
+	if mixed || a.IsDir == b.IsDir {
+		return false, false
+	}
+	return a.IsDir, true
+}
+
+// dirsLast runs another strategy and then moves the directories after the
+// files, keeping the order within each.
+type dirsLast struct {
+	Strategy
+}
+
+func (s *dirsLast) Sort(files []model.FileEntry, reverse bool) {
+	s.Strategy.Sort(files, reverse)
+	sorted := slices.Clone(files)
+	n := 0
+	for _, file := range sorted {
+		if !file.IsDir {
+			files[n] = file
+			n++
+		}
+	}
+	for _, file := range sorted {
+		if file.IsDir {
+			files[n] = file
+			n++
+		}
+	}
+}
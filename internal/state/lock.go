@@ -0,0 +1,60 @@
+package state
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// ErrLocked is returned by Lock when another lu process holds the lock.
+var ErrLocked = errors.New("another lu process is busy with it")
+
+// staleLock is how old a lock file may get before it is taken to belong to
+// a process that died without removing it. It outlasts the longest thing
+// done under a lock, downloading an update.
+const staleLock = 10 * time.Minute
+
+// lockPoll is how often a waiting Lock checks whether the lock was freed.
+const lockPoll = 20 * time.Millisecond
+
+// Lock takes the lock called name, shared by every lu process the user
+// runs, waiting up to wait for its holder to let go. Zero gives up at once.
+// The returned function releases it.
+func Lock(name string, wait time.Duration) (func(), error) {
+	dir, err := StateDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := mkdirAll(dir); err != nil {
+		return nil, err
+	}
+	return lockIn(dir, name, wait)
+}
+
+// lockIn takes the lock as a file in dir that only one process can create.
+func lockIn(dir, name string, wait time.Duration) (func(), error) {
+	path := filepath.Join(dir, name+".lock")
+	deadline := time.Now().Add(wait)
+	for {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if err == nil {
+			_, _ = f.WriteString(strconv.Itoa(os.Getpid()) + "\n")
+			f.Close()
+			adopt(path)
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleLock {
+			os.Remove(path)
+			continue
+		}
+		if !time.Now().Before(deadline) {
+			return nil, ErrLocked
+		}
+		time.Sleep(lockPoll)
+	}
+}
@@ -0,0 +1,33 @@
+//go:build !windows
+
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// adopt gives path to the owner of the directory it is in when lu runs as
+// root there, as under sudo with the caller's HOME, so the files do not end
+// up owned by root in a home that is not root's.
+func adopt(path string) {
+	if os.Geteuid() != 0 {
+		return
+	}
+	info, err := os.Stat(filepath.Dir(path))
+	if err != nil {
+		return
+	}
+	_ = MatchOwner(path, info)
+}
+
+// MatchOwner makes path owned by the user and group that own like. Only
+// root may give files away, so for anyone else it does nothing.
+func MatchOwner(path string, like os.FileInfo) error {
+	stat, ok := like.Sys().(*syscall.Stat_t)
+	if !ok || os.Geteuid() != 0 || stat.Uid == 0 {
+		return nil
+	}
+	return os.Lchown(path, int(stat.Uid), int(stat.Gid))
+}
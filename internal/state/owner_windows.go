@@ -0,0 +1,14 @@
+//go:build windows
+
+package state
+
+import "os"
+
+// adopt has nothing to do on Windows, where new files inherit their
+// access rights from the directory they are created in.
+func adopt(string) {}
+
+// MatchOwner does nothing on Windows, for the same reason.
+func MatchOwner(string, os.FileInfo) error {
+	return nil
+}
@@ -0,0 +1,177 @@
+// Package state manages the directories where lu keeps files between runs:
+// the cache, for data it can always rebuild such as the update check
+// timestamp, --changed-since-last snapshots and crash reports, and the
+// state directory, for lock files shared by concurrent lu processes.
+//
+// Both follow the XDG base directory layout on every platform, like the
+// config file does. Directories are created private to the user, files are
+// written atomically with the mode the caller asks for less the umask, and
+// when lu runs as root in someone else's home, new files are handed to the
+// owner of the directory they land in so the next unprivileged run can
+// still replace them.
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const appDir = "lu-hut"
+
+// legacyDir is where versions before the XDG layout kept their cache,
+// relative to the home directory.
+const legacyDir = ".lu-hut"
+
+var migrateOnce sync.Once
+
+// CacheDir returns $XDG_CACHE_HOME/lu-hut, falling back to ~/.cache/lu-hut.
+// The first call in a process moves anything left in ~/.lu-hut over.
+func CacheDir() (string, error) {
+	dir, err := baseDir("XDG_CACHE_HOME", ".cache")
+	if err != nil {
+		return "", err
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		migrateOnce.Do(func() { migrate(filepath.Join(home, legacyDir), dir) })
+	}
+	return dir, nil
+}
+
+// StateDir returns $XDG_STATE_HOME/lu-hut, falling back to
+// ~/.local/state/lu-hut.
+func StateDir() (string, error) {
+	return baseDir("XDG_STATE_HOME", filepath.Join(".local", "state"))
+}
+
+// CachePath returns the location of a cache file, joining elem onto
+// CacheDir, and creates the directory that will hold it.
+func CachePath(elem ...string) (string, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return within(dir, elem)
+}
+
+// StatePath is CachePath for the state directory.
+func StatePath(elem ...string) (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return within(dir, elem)
+}
+
+// baseDir returns the lu-hut directory under the base named by env, or
+// under fallback in the home directory when env is unset. Relative values
+// are ignored, as the XDG specification asks.
+func baseDir(env, fallback string) (string, error) {
+	base := os.Getenv(env)
+	if !filepath.IsAbs(base) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		base = filepath.Join(home, fallback)
+	}
+	return filepath.Join(base, appDir), nil
+}
+
+func within(dir string, elem []string) (string, error) {
+	path := filepath.Join(append([]string{dir}, elem...)...)
+	if err := mkdirAll(filepath.Dir(path)); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// mkdirAll creates dir and any missing parents with mode 0700, each owned
+// like the directory it is created in.
+func mkdirAll(dir string) error {
+	if info, err := os.Stat(dir); err == nil {
+		if !info.IsDir() {
+			return fmt.Errorf("%s is not a directory", dir)
+		}
+		return nil
+	}
+	if parent := filepath.Dir(dir); parent != dir {
+		if err := mkdirAll(parent); err != nil {
+			return err
+		}
+	}
+	if err := os.Mkdir(dir, 0o700); err != nil && !os.IsExist(err) {
+		return err
+	}
+	adopt(dir)
+	return nil
+}
+
+// WriteFile replaces the file at path with data. The new file is written
+// next to it and renamed into place, so readers see either the old or the
+// new contents, and is created with perm less the umask.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	f, err := createTemp(path, perm)
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	adopt(tmp)
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// createTemp is os.CreateTemp without its fixed 0600 mode, which would
+// ignore both perm and the umask.
+func createTemp(path string, perm os.FileMode) (*os.File, error) {
+	dir, base := filepath.Split(path)
+	for try := 0; ; try++ {
+		name := filepath.Join(dir, fmt.Sprintf(".%s.tmp-%d-%d", base, os.Getpid(), time.Now().UnixNano()))
+		f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+		if os.IsExist(err) && try < 10 {
+			continue
+		}
+		return f, err
+	}
+}
+
+// migrate moves the entries of the legacy directory into dir, keeping any
+// that already exist there, and removes the legacy directory once it is
+// empty. Failures are ignored: everything in it can be recreated. Another
+// lu migrating at the same moment makes this one skip it.
+func migrate(legacy, dir string) {
+	entries, err := os.ReadDir(legacy)
+	if err != nil {
+		return
+	}
+	if err := mkdirAll(dir); err != nil {
+		return
+	}
+	unlock, err := lockIn(dir, "migrate", 0)
+	if err != nil {
+		return
+	}
+	defer unlock()
+	for _, entry := range entries {
+		target := filepath.Join(dir, entry.Name())
+		if _, err := os.Lstat(target); err == nil {
+			continue
+		}
+		_ = os.Rename(filepath.Join(legacy, entry.Name()), target)
+	}
+	_ = os.Remove(legacy)
+}
@@ -0,0 +1,147 @@
+package state
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestMigrate(t *testing.T) {
+	home := t.TempDir()
+	legacy := filepath.Join(home, legacyDir)
+	dir := filepath.Join(home, ".cache", "lu-hut")
+
+	write := func(path, content string) {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(legacy, "last_check"), "old")
+	write(filepath.Join(legacy, "snapshots", "abc.json"), "{}")
+	write(filepath.Join(legacy, "kept"), "old")
+	write(filepath.Join(dir, "kept"), "new")
+
+	migrate(legacy, dir)
+
+	for path, want := range map[string]string{
+		filepath.Join(dir, "last_check"):            "old",
+		filepath.Join(dir, "snapshots", "abc.json"): "{}",
+		filepath.Join(dir, "kept"):                  "new",
+	} {
+		data, err := os.ReadFile(path)
+		if err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v; want %q", path, data, err, want)
+		}
+	}
+
+	// The entry that could not move keeps the legacy directory around.
+	if _, err := os.Stat(filepath.Join(legacy, "kept")); err != nil {
+		t.Errorf("conflicting legacy entry was removed: %v", err)
+	}
+
+	os.Remove(filepath.Join(legacy, "kept"))
+	migrate(legacy, dir)
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("empty legacy directory was not removed: %v", err)
+	}
+}
+
+func TestMigrateWithoutLegacy(t *testing.T) {
+	home := t.TempDir()
+	dir := filepath.Join(home, ".cache", "lu-hut")
+
+	migrate(filepath.Join(home, legacyDir), dir)
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("cache directory created without anything to migrate: %v", err)
+	}
+}
+
+func TestBaseDirs(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("XDG_STATE_HOME", "relative/state")
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, "xdg-cache"))
+
+	if dir, err := StateDir(); err != nil || dir != filepath.Join(home, ".local", "state", appDir) {
+		t.Errorf("StateDir() = %q, %v; want the home fallback for a relative XDG_STATE_HOME", dir, err)
+	}
+	path, err := CachePath("snapshots", "x.json")
+	if err != nil || path != filepath.Join(home, "xdg-cache", appDir, "snapshots", "x.json") {
+		t.Fatalf("CachePath() = %q, %v", path, err)
+	}
+	info, err := os.Stat(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0o700 {
+		t.Errorf("cache directory mode = %v, want 0700", info.Mode().Perm())
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "last_check")
+	for _, content := range []string{"old", "new"} {
+		if err := WriteFile(path, []byte(content), 0o640); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "new" {
+		t.Errorf("ReadFile() = %q, %v; want new", data, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&^0o640 != 0 {
+		t.Errorf("mode = %v, want at most 0640", info.Mode().Perm())
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("%d entries left next to the file, want only the file", len(entries))
+	}
+}
+
+func TestLock(t *testing.T) {
+	dir := t.TempDir()
+	unlock, err := lockIn(dir, "update", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lockIn(dir, "update", 50*time.Millisecond); !errors.Is(err, ErrLocked) {
+		t.Errorf("second lock error = %v, want ErrLocked", err)
+	}
+	if other, err := lockIn(dir, "update-check", 0); err != nil {
+		t.Errorf("lock with another name error = %v", err)
+	} else {
+		other()
+	}
+	unlock()
+
+	unlock, err = lockIn(dir, "update", 0)
+	if err != nil {
+		t.Fatalf("lock after release error = %v", err)
+	}
+	unlock()
+
+	// A lock left behind by a process that died is broken once stale.
+	path := filepath.Join(dir, "update.lock")
+	if err := os.WriteFile(path, []byte("1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * staleLock)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	unlock, err = lockIn(dir, "update", 0)
+	if err != nil {
+		t.Fatalf("stale lock was not broken: %v", err)
+	}
+	unlock()
+}
@@ -0,0 +1,90 @@
+// Package summary computes a small, fast overview of a directory for use in
+// shell prompts.
+package summary
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ipanardian/lu-hut/internal/git"
+)
+
+type Summary struct {
+	Entries int  `json:"entries"`
+	Dirs    int  `json:"dirs"`
+	Files   int  `json:"files"`
+	Dirty   int  `json:"dirty"`
+	InRepo  bool `json:"in_repo"`
+	// GitError is why Dirty is unknown, when git status failed.
+	GitError    string `json:"git_error,omitempty"`
+	Largest     string `json:"largest,omitempty"`
+	LargestSize int64  `json:"largest_size,omitempty"`
+}
+
+// Collect reads only dir itself: no recursion, no content probes. Git is
+// asked once for the working tree status when withGit is set; when that
+// fails, GitError says why.
+func Collect(dir string, showHidden, withGit bool) (Summary, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	var s Summary
+	for _, entry := range entries {
+		if !showHidden && strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		s.Entries++
+		if entry.IsDir() {
+			s.Dirs++
+			continue
+		}
+		s.Files++
+
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		if info.Size() > s.LargestSize {
+			s.Largest, s.LargestSize = entry.Name(), info.Size()
+		}
+	}
+
+	if withGit {
+		if repo, err := git.NewRepository(dir); err == nil {
+			s.InRepo = true
+			if s.Dirty, err = repo.DirtyCount(dir); err != nil {
+				s.GitError = err.Error()
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// String renders the summary as space separated key=value pairs, which is
+// easy to split in shell scripts. File names are quoted when needed.
+func (s Summary) String() string {
+	parts := []string{
+		fmt.Sprintf("entries=%d", s.Entries),
+		fmt.Sprintf("dirs=%d", s.Dirs),
+		fmt.Sprintf("files=%d", s.Files),
+	}
+	switch {
+	case s.InRepo && s.GitError != "":
+		parts = append(parts, "dirty=?")
+	case s.InRepo:
+		parts = append(parts, fmt.Sprintf("dirty=%d", s.Dirty))
+	}
+	if s.Largest != "" {
+		name := s.Largest
+		if strings.ContainsAny(name, " \t\"'=\\") {
+			name = fmt.Sprintf("%q", name)
+		}
+		parts = append(parts, "largest="+name, fmt.Sprintf("largest_size=%d", s.LargestSize))
+	}
+	return strings.Join(parts, " ")
+}
@@ -14,6 +14,7 @@ const (
 	StyleSingle = iota
 	StyleDouble
 	StyleBold
+	StyleASCII
 )
 
 type Table struct {
@@ -110,6 +111,21 @@ func (t *Table) getBorderChars() borderChars {
 			rightTee:    "┫",
 			cross:       "╋",
 		}
+	case StyleASCII:
+		return borderChars{
+			horizontal:  "-",
+			vertical:    "|",
+			topLeft:     "+",
+			topRight:    "+",
+			bottomLeft:  "+",
+			bottomRight: "+",
+			middle:      "+",
+			topTee:      "+",
+			bottomTee:   "+",
+			leftTee:     "+",
+			rightTee:    "+",
+			cross:       "+",
+		}
 	default:
 		return borderChars{
 			horizontal:  "─",
@@ -0,0 +1,187 @@
+// Package tags reads the free-form tags users attach to files: Finder tags
+// on macOS and the freedesktop user.xdg.tags attribute on Linux.
+package tags
+
+import (
+	"encoding/binary"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/ipanardian/lu-hut/internal/model"
+)
+
+const (
+	finderAttr = "com.apple.metadata:_kMDItemUserTags"
+	xdgAttr    = "user.xdg.tags"
+)
+
+// Read returns the tags of the file at path, or nil when it has none or the
+// platform does not support extended attributes.
+func Read(path string) []model.Tag {
+	if data, ok := getxattr(path, finderAttr); ok {
+		if tags, ok := parseFinder(data); ok {
+			return tags
+		}
+	}
+	if data, ok := getxattr(path, xdgAttr); ok {
+		return parseXDG(data)
+	}
+	return nil
+}
+
+// Has reports whether tags contains any of names, ignoring case.
+func Has(tags []model.Tag, names []string) bool {
+	for _, tag := range tags {
+		for _, name := range names {
+			if strings.EqualFold(tag.Name, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseXDG reads the comma-separated list stored in user.xdg.tags.
+func parseXDG(data []byte) []model.Tag {
+	var tags []model.Tag
+	for _, name := range strings.Split(string(data), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			tags = append(tags, model.Tag{Name: name})
+		}
+	}
+	return tags
+}
+
+// parseFinder reads the binary property list Finder stores: an array of
+// strings of the form "Name" or "Name\nColor", where Color is Finder's
+// label index (1 gray, 2 green, 3 purple, 4 blue, 5 yellow, 6 red, 7 orange).
+func parseFinder(data []byte) ([]model.Tag, bool) {
+	strs, ok := parseStringArray(data)
+	if !ok {
+		return nil, false
+	}
+
+	tags := make([]model.Tag, 0, len(strs))
+	for _, s := range strs {
+		name, index, _ := strings.Cut(s, "\n")
+		tag := model.Tag{Name: name}
+		tag.Color, _ = strconv.Atoi(index)
+		if name != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, true
+}
+
+const bplistTrailerSize = 32
+
+// parseStringArray decodes a bplist00 document whose top object is an array
+// of strings, which is all Finder tags need.
+func parseStringArray(data []byte) ([]string, bool) {
+	if len(data) < 8+bplistTrailerSize || string(data[:8]) != "bplist00" {
+		return nil, false
+	}
+
+	trailer := data[len(data)-bplistTrailerSize:]
+	offsetSize := int(trailer[6])
+	refSize := int(trailer[7])
+	numObjects := binary.BigEndian.Uint64(trailer[8:])
+	top := binary.BigEndian.Uint64(trailer[16:])
+	tableOffset := binary.BigEndian.Uint64(trailer[24:])
+	// Written as divisions so values from the file cannot overflow them.
+	if offsetSize < 1 || offsetSize > 8 || refSize < 1 || refSize > 8 || top >= numObjects ||
+		tableOffset > uint64(len(data)) || numObjects > (uint64(len(data))-tableOffset)/uint64(offsetSize) {
+		return nil, false
+	}
+
+	offset := func(object uint64) (int, bool) {
+		if object >= numObjects {
+			return 0, false
+		}
+		pos := tableOffset + object*uint64(offsetSize)
+		off := readUint(data[pos : pos+uint64(offsetSize)])
+		return int(off), off < uint64(len(data))
+	}
+
+	pos, ok := offset(top)
+	if !ok || data[pos]>>4 != 0xA {
+		return nil, false
+	}
+	count, pos, ok := objectLength(data, pos)
+	if !ok || count > (len(data)-pos)/refSize {
+		return nil, false
+	}
+
+	strs := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		ref := readUint(data[pos+i*refSize : pos+(i+1)*refSize])
+		objPos, ok := offset(ref)
+		if !ok {
+			return nil, false
+		}
+		s, ok := readString(data, objPos)
+		if !ok {
+			return nil, false
+		}
+		strs = append(strs, s)
+	}
+	return strs, true
+}
+
+// objectLength returns the element count encoded in the marker at pos and
+// the position of the object's contents. A low nibble of 0xF means the
+// count follows as an integer object. Counts larger than data itself are
+// rejected, so callers can multiply them without overflowing.
+func objectLength(data []byte, pos int) (int, int, bool) {
+	length := int(data[pos] & 0x0F)
+	pos++
+	if length != 0x0F {
+		return length, pos, true
+	}
+	if pos >= len(data) || data[pos]>>4 != 0x1 {
+		return 0, 0, false
+	}
+	size := 1 << (data[pos] & 0x0F)
+	if size > 8 || size > len(data)-pos-1 {
+		return 0, 0, false
+	}
+	count := readUint(data[pos+1 : pos+1+size])
+	if count > uint64(len(data)) {
+		return 0, 0, false
+	}
+	return int(count), pos + 1 + size, true
+}
+
+func readString(data []byte, pos int) (string, bool) {
+	marker := data[pos] >> 4
+	length, pos, ok := objectLength(data, pos)
+	if !ok {
+		return "", false
+	}
+	switch marker {
+	case 0x5:
+		if length > len(data)-pos {
+			return "", false
+		}
+		return string(data[pos : pos+length]), true
+	case 0x6:
+		if length > (len(data)-pos)/2 {
+			return "", false
+		}
+		units := make([]uint16, length)
+		for i := range units {
+			units[i] = binary.BigEndian.Uint16(data[pos+2*i:])
+		}
+		return string(utf16.Decode(units)), true
+	}
+	return "", false
+}
+
+func readUint(b []byte) uint64 {
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
@@ -0,0 +1,126 @@
+package tags
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/ipanardian/lu-hut/internal/model"
+)
+
+// finderPlist builds the binary plist Finder writes for a list of tags.
+func finderPlist(values ...string) []byte {
+	data := []byte("bplist00")
+	var offsets []int
+
+	offsets = append(offsets, len(data))
+	data = append(data, 0xA0|byte(len(values)))
+	for i := range values {
+		data = append(data, byte(i+1))
+	}
+
+	for _, v := range values {
+		offsets = append(offsets, len(data))
+		ascii := true
+		for _, r := range v {
+			ascii = ascii && r < 0x80
+		}
+		if ascii {
+			data = append(data, 0x50|byte(len(v)))
+			data = append(data, v...)
+			continue
+		}
+		units := utf16.Encode([]rune(v))
+		data = append(data, 0x60|byte(len(units)))
+		for _, u := range units {
+			data = binary.BigEndian.AppendUint16(data, u)
+		}
+	}
+
+	tableOffset := len(data)
+	for _, off := range offsets {
+		data = append(data, byte(off))
+	}
+
+	trailer := make([]byte, bplistTrailerSize)
+	trailer[6], trailer[7] = 1, 1
+	binary.BigEndian.PutUint64(trailer[8:], uint64(len(offsets)))
+	binary.BigEndian.PutUint64(trailer[24:], uint64(tableOffset))
+	return append(data, trailer...)
+}
+
+func TestParseFinder(t *testing.T) {
+	tags, ok := parseFinder(finderPlist("Work\n6", "Zürich", "Home\n4"))
+	want := []model.Tag{{Name: "Work", Color: 6}, {Name: "Zürich"}, {Name: "Home", Color: 4}}
+	if !ok || !reflect.DeepEqual(tags, want) {
+		t.Errorf("parseFinder() = %v, %v; want %v", tags, ok, want)
+	}
+
+	for name, data := range map[string][]byte{
+		"not a plist": []byte("work,home"),
+		"truncated":   finderPlist("Work")[:20],
+	} {
+		if _, ok := parseFinder(data); ok {
+			t.Errorf("parseFinder(%s) accepted invalid data", name)
+		}
+	}
+}
+
+func TestParseXDG(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected []model.Tag
+	}{
+		{"work", []model.Tag{{Name: "work"}}},
+		{"work, urgent,,", []model.Tag{{Name: "work"}, {Name: "urgent"}}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		if got := parseXDG([]byte(tt.value)); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("parseXDG(%q) = %v, want %v", tt.value, got, tt.expected)
+		}
+	}
+}
+
+func TestHas(t *testing.T) {
+	tags := []model.Tag{{Name: "Work"}, {Name: "urgent"}}
+	if !Has(tags, []string{"home", "work"}) {
+		t.Errorf("Has() missed a tag differing only by case")
+	}
+	if Has(tags, []string{"home"}) || Has(nil, []string{"work"}) {
+		t.Errorf("Has() matched a tag that is not there")
+	}
+}
+
+func TestParseFinderCrafted(t *testing.T) {
+	huge := func(data []byte, at int) []byte {
+		data = append([]byte(nil), data...)
+		binary.BigEndian.PutUint64(data[len(data)-bplistTrailerSize+at:], 1<<63+1)
+		return data
+	}
+	longString := []byte("bplist00\xA1\x01\x5F\x13\xff\xff\xff\xff\xff\xff\xff\xff\x08\x0a")
+	trailer := make([]byte, bplistTrailerSize)
+	trailer[6], trailer[7] = 1, 1
+	binary.BigEndian.PutUint64(trailer[8:], 2)
+	binary.BigEndian.PutUint64(trailer[24:], uint64(len(longString)-2))
+	longString = append(longString, trailer...)
+
+	for name, data := range map[string][]byte{
+		"object count overflows": huge(finderPlist("Work"), 8),
+		"table past the end":     huge(finderPlist("Work"), 24),
+		"string length wraps":    longString,
+	} {
+		if _, ok := parseFinder(data); ok {
+			t.Errorf("parseFinder(%s) accepted invalid data", name)
+		}
+	}
+}
+
+func FuzzParseFinder(f *testing.F) {
+	f.Add(finderPlist("Work\n6", "Zürich"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		parseFinder(data)
+	})
+}
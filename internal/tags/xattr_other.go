@@ -0,0 +1,7 @@
+//go:build !linux && !darwin
+
+package tags
+
+func getxattr(path, name string) ([]byte, bool) {
+	return nil, false
+}
@@ -0,0 +1,18 @@
+//go:build linux || darwin
+
+package tags
+
+import "golang.org/x/sys/unix"
+
+func getxattr(path, name string) ([]byte, bool) {
+	size, err := unix.Getxattr(path, name, nil)
+	if err != nil || size <= 0 {
+		return nil, false
+	}
+	buf := make([]byte, size)
+	n, err := unix.Getxattr(path, name, buf)
+	if err != nil {
+		return nil, false
+	}
+	return buf[:n], true
+}
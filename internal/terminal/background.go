@@ -0,0 +1,104 @@
+package terminal
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+const backgroundQueryTimeout = 100 * time.Millisecond
+
+// LightBackground reports whether the terminal background is light. It
+// trusts COLORFGBG when set and otherwise, with query set, asks the
+// terminal with an OSC 11 query. The second result is false when the
+// background could not be determined, in which case callers should assume
+// a dark background.
+func LightBackground(query bool) (bool, bool) {
+	if value := os.Getenv("COLORFGBG"); value != "" {
+		return parseColorFGBG(value)
+	}
+	if !query {
+		return false, false
+	}
+	return queryBackground()
+}
+
+// parseColorFGBG reads the "fg;bg" (or "fg;default;bg") value set by rxvt,
+// Konsole and others. Backgrounds 7 (white) and 9-15 except 8 are light.
+func parseColorFGBG(value string) (bool, bool) {
+	parts := strings.Split(value, ";")
+	bg, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil || bg < 0 || bg > 15 {
+		return false, false
+	}
+	return bg == 7 || bg > 8, true
+}
+
+func queryBackground() (bool, bool) {
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		return false, false
+	}
+
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return false, false
+	}
+	defer tty.Close()
+
+	state, err := term.MakeRaw(int(tty.Fd()))
+	if err != nil {
+		return false, false
+	}
+	defer term.Restore(int(tty.Fd()), state)
+
+	if err := tty.SetReadDeadline(time.Now().Add(backgroundQueryTimeout)); err != nil {
+		return false, false
+	}
+	if _, err := tty.WriteString("\x1b]11;?\x07"); err != nil {
+		return false, false
+	}
+
+	var reply []byte
+	buf := make([]byte, 64)
+	for len(reply) < 64 {
+		n, err := tty.Read(buf)
+		reply = append(reply, buf[:n]...)
+		if err != nil || strings.ContainsAny(string(reply), "\x07\\") {
+			break
+		}
+	}
+	return parseOSC11(string(reply))
+}
+
+// parseOSC11 parses a reply such as "\x1b]11;rgb:ffff/ffff/ffff\x07" and
+// classifies the color by its relative luminance.
+func parseOSC11(reply string) (bool, bool) {
+	_, spec, ok := strings.Cut(reply, "rgb:")
+	if !ok {
+		return false, false
+	}
+	spec = strings.TrimRight(spec, "\x07\x1b\\")
+
+	channels := strings.Split(spec, "/")
+	if len(channels) != 3 {
+		return false, false
+	}
+
+	var rgb [3]float64
+	for i, channel := range channels {
+		if channel == "" || len(channel) > 4 {
+			return false, false
+		}
+		v, err := strconv.ParseUint(channel, 16, 16)
+		if err != nil {
+			return false, false
+		}
+		rgb[i] = float64(v) / float64(uint64(1)<<(4*len(channel))-1)
+	}
+
+	luminance := 0.2126*rgb[0] + 0.7152*rgb[1] + 0.0722*rgb[2]
+	return luminance > 0.5, true
+}
@@ -0,0 +1,53 @@
+package terminal
+
+import "testing"
+
+func TestParseColorFGBG(t *testing.T) {
+	tests := []struct {
+		value string
+		light bool
+		ok    bool
+	}{
+		{value: "15;0", light: false, ok: true},
+		{value: "0;15", light: true, ok: true},
+		{value: "0;7", light: true, ok: true},
+		{value: "7;8", light: false, ok: true},
+		{value: "0;default;15", light: true, ok: true},
+		{value: "default", light: false, ok: false},
+		{value: "0;42", light: false, ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			light, ok := parseColorFGBG(tt.value)
+			if light != tt.light || ok != tt.ok {
+				t.Errorf("parseColorFGBG(%q) = %v, %v; want %v, %v", tt.value, light, ok, tt.light, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseOSC11(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply string
+		light bool
+		ok    bool
+	}{
+		{name: "white bel", reply: "\x1b]11;rgb:ffff/ffff/ffff\x07", light: true, ok: true},
+		{name: "black st", reply: "\x1b]11;rgb:0000/0000/0000\x1b\\", light: false, ok: true},
+		{name: "solarized light", reply: "\x1b]11;rgb:fdfd/f6f6/e3e3\x07", light: true, ok: true},
+		{name: "two digit channels", reply: "\x1b]11;rgb:28/2a/36\x07", light: false, ok: true},
+		{name: "no reply", reply: "", light: false, ok: false},
+		{name: "garbage", reply: "\x1b]11;rgb:zz/00\x07", light: false, ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			light, ok := parseOSC11(tt.reply)
+			if light != tt.light || ok != tt.ok {
+				t.Errorf("parseOSC11(%q) = %v, %v; want %v, %v", tt.reply, light, ok, tt.light, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package terminal
+
+import (
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// SupportsTruecolor reports whether the terminal advertises 24-bit color
+// through COLORTERM, which is how most modern terminal emulators signal it.
+func SupportsTruecolor() bool {
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return true
+	}
+	return false
+}
+
+// ColorEnabled resolves a --color mode into a yes/no decision. "always" and
+// "never" are explicit; anything else means auto, which disables color when
+// NO_COLOR is set, TERM is "dumb", or stdout is not a terminal.
+func ColorEnabled(mode string) bool {
+	return colorEnabled(mode, term.IsTerminal(int(os.Stdout.Fd())))
+}
+
+// TTYColorEnabled is ColorEnabled for output drawn on the terminal itself,
+// such as the interactive picker, where stdout may be captured.
+func TTYColorEnabled(mode string) bool {
+	return colorEnabled(mode, true)
+}
+
+func colorEnabled(mode string, tty bool) bool {
+	switch mode {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return tty
+}
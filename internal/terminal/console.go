@@ -0,0 +1,37 @@
+package terminal
+
+import (
+	"os"
+	"strings"
+)
+
+// SupportsUnicode reports whether the box-drawing and tree characters used
+// by the table and tree views can be displayed. On Windows the console code
+// page decides; elsewhere an explicitly non-UTF-8 locale does.
+func SupportsUnicode() bool {
+	if ok, known := consoleUnicode(); known {
+		return ok
+	}
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			return localeUnicode(value)
+		}
+	}
+	return true
+}
+
+// localeUnicode reads a POSIX locale such as "en_US.UTF-8". Only locales
+// that name another charset count as non-Unicode; "C" and "POSIX" are
+// common defaults in containers whose terminals still render UTF-8.
+func localeUnicode(locale string) bool {
+	_, charset, ok := strings.Cut(locale, ".")
+	if !ok {
+		return true
+	}
+	charset, _, _ = strings.Cut(charset, "@")
+	switch strings.ToLower(strings.ReplaceAll(charset, "-", "")) {
+	case "utf8":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package terminal
+
+// EnableVirtualTerminal is a no-op outside Windows, where terminals
+// interpret ANSI escapes natively.
+func EnableVirtualTerminal() bool {
+	return true
+}
+
+func consoleUnicode() (bool, bool) {
+	return false, false
+}
@@ -0,0 +1,26 @@
+package terminal
+
+import "testing"
+
+func TestLocaleUnicode(t *testing.T) {
+	tests := []struct {
+		locale  string
+		unicode bool
+	}{
+		{locale: "en_US.UTF-8", unicode: true},
+		{locale: "de_DE.utf8", unicode: true},
+		{locale: "sr_RS.UTF-8@latin", unicode: true},
+		{locale: "C", unicode: true},
+		{locale: "POSIX", unicode: true},
+		{locale: "en_US.ISO-8859-1", unicode: false},
+		{locale: "ja_JP.eucJP", unicode: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			if got := localeUnicode(tt.locale); got != tt.unicode {
+				t.Errorf("localeUnicode(%q) = %v, want %v", tt.locale, got, tt.unicode)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+//go:build windows
+
+package terminal
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+const utf8CodePage = 65001
+
+// EnableVirtualTerminal turns on ANSI escape processing for the console
+// attached to stdout. It returns false only when stdout is a console that
+// refuses the mode, such as conhost before Windows 10; redirected output
+// passes escapes through untouched and needs nothing.
+func EnableVirtualTerminal() bool {
+	handle := windows.Handle(os.Stdout.Fd())
+
+	var mode uint32
+	if err := windows.GetConsoleMode(handle, &mode); err != nil {
+		return true
+	}
+	if mode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING != 0 {
+		return true
+	}
+	return windows.SetConsoleMode(handle, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING) == nil
+}
+
+// consoleUnicode checks the console output code page. Legacy code pages
+// such as 437 or 1252 lack most box-drawing characters, so only UTF-8
+// counts. Without a console (e.g. output captured by CI) it has no opinion.
+func consoleUnicode() (bool, bool) {
+	cp, err := windows.GetConsoleOutputCP()
+	if err != nil || cp == 0 {
+		return false, false
+	}
+	return cp == utf8CodePage, true
+}
@@ -9,6 +9,94 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// flagHelp describes the listing flags, shared by the main help page and
+// lu help flags.
+var flagHelp = []struct {
+	flag, desc string
+}{
+	{"--sort", "sort order: name, natural, time, created, accessed, size, ext, owner, group, inode, links or none"},
+	{"-t, --sort-modified", "sort by modified time (newest first)"},
+	{"-S, --sort-size", "sort by file size (largest first)"},
+	{"-X, --sort-extension", "sort by file extension"},
+	{"--sort-natural", "sort names with numbers in numeric order (file2 before file10)"},
+	{"-r, --reverse", "reverse sort order"},
+	{"--case-sensitive", "sort names by byte order (Zebra before apple), like POSIX ls"},
+	{"--dirs-last", "list directories after files instead of before them"},
+	{"--no-group-dirs", "sort directories among the files instead of grouping them"},
+	{"-g, --git", "show git status inline"},
+	{"--conflicts", "only list paths with merge conflicts, e.g. in the middle of a rebase"},
+	{"--git-dirty", "only list modified, staged or untracked paths, like a scoped git status"},
+	{"--git-ignore", "hide files git ignores, including info/exclude and core.excludesFile"},
+	{"--git-blame", "show the author, date and hash of the last commit touching each entry"},
+	{"-h, --hidden", "show hidden files"},
+	{"--skip-special", "omit device nodes, FIFOs and sockets"},
+	{"-u, --user", "show user and group ownership metadata."},
+	{"-T, --exact-time", "show exact modification time instead of relative"},
+	{"--align-units", "right-align sizes so their units (B, KB, MB, ...) line up"},
+	{"--time", "timestamp in the time column: modified (default), accessed or created"},
+	{"-F, --tree", "display directory structure in a tree format."},
+	{"-R, --recursive", "list subdirectories recursively"},
+	{"-L, --max-depth", "maximum recursion depth (0 = no limit, default: 30)"},
+	{"--changed-since-last", "only list what was added or modified since the last such run"},
+	{"--pick", "choose entries interactively and print their paths, e.g. vim $(lu --pick)"},
+	{"--print0", "with --pick, separate the printed paths with NUL (for xargs -0)"},
+	{"--session-log", "with --pick, append what was visited, marked and picked to a JSON lines file"},
+	{"--du", "show the total size of each directory's contents"},
+	{"--follow", "with --du, follow symlinks and count shared content once"},
+	{"--throttle", "limit output to N lines per second for slow terminals"},
+	{"--watch", "redraw the listing of a directory, or the row of a single file, every --interval"},
+	{"--interval", "with --watch, time between refreshes (default: 2s)"},
+	{"--growth-alert", "with --watch, highlight entries growing at least this fast per second (default: 1M, 0 = off)"},
+	{"--limit", "only show the first N entries of each directory after sorting"},
+	{"--timeout", "give up on git, owner lookups and --du after this long (default: 10s, --du unlimited)"},
+	{"--debug", "print the detected terminal, color depth, width, locale and git setup to stderr"},
+	{"--format", "output format: table (default), json, or flat with --tree"},
+	{"--json-verbose", "with --format json, add the key each entry was sorted by"},
+	{"--output", "write the listing to a file, replaced atomically when complete"},
+	{"--tee", "with --output, also print the listing to the terminal"},
+	{"-i, --include", "include files matching glob patterns (quote the pattern)"},
+	{"-x, --exclude", "exclude files matching glob patterns (quote the pattern)"},
+	{"--no-junk", "exclude well-known noise such as node_modules, .DS_Store and __pycache__"},
+	{"--min-size", "only show files of at least this size (e.g. 10M)"},
+	{"--max-size", "only show files of at most this size (e.g. 1G)"},
+	{"--only", "only list entries of these types: dirs, files, links, exec"},
+	{"--mime", "only list files whose content is of these MIME types, e.g. image/*"},
+	{"--perm", "only list entries whose mode matches, find-style: 4000 exactly, -o+w all bits, /6000 any"},
+	{"-o, --octal", "show file permissions in octal format"},
+	{"--color", "color output mode: always, auto or never (auto honors NO_COLOR)"},
+	{"--border-color", "table border color, e.g. blue or #5f87af (overrides the theme)"},
+	{"--header-color", "table header color, e.g. 'hi-white bold' (overrides the theme)"},
+	{"--border-style", "table border characters (single|double|bold|ascii)"},
+	{"--no-<flag>", "turn off a boolean flag enabled by the config, e.g. --no-git"},
+	{"--profile", "apply a named profile from the config file"},
+	{"--theme", "color theme (default, light, cb-friendly, monochrome, solarized, dracula), auto or theme file"},
+	{"--icons", "show Nerd Font icons next to names"},
+	{"--media", "show image dimensions and audio/video duration"},
+	{"--compressibility", "estimate how well each file would compress (experimental)"},
+	{"--inode", "show inode numbers and hard-link counts"},
+	{"--archive-count", "show the number of entries in zip and tar archives"},
+	{"--project", "show project name and version from go.mod, package.json, Cargo.toml, ..."},
+	{"--shortcuts", "show where .desktop, .lnk and macOS alias files point"},
+	{"--tags", "show Finder and user.xdg.tags tags as colored chips in a Tags column"},
+	{"--tag", "only list entries carrying one of these tags, e.g. --tag work"},
+	{"--owner", "only list entries owned by one of these users, e.g. --owner alice"},
+	{"--group", "only list entries belonging to one of these groups, e.g. --group docker"},
+	{"--no-generated", "hide lockfiles and generated code instead of dimming them"},
+	{"--generated-headers", "also open source files to find generated-code headers such as DO NOT EDIT"},
+	{"--highlight", "emphasize names matching glob patterns, keeping the rest visible"},
+	{"--badges", "show text markers like [exec], [link], [big] and [M] next to names"},
+	{"--ascii", "draw tables and trees with plain ASCII characters only (auto-detected when unset)"},
+	{"--expect-perms", "highlight entries whose mode differs, e.g. 0644:files,0755:dirs"},
+	{"--strict", "with --expect-perms, exit non-zero when any entry differs"},
+	{"--explain-perms", "describe unusual modes in plain words, e.g. setuid root binary"},
+	{"--strict-names", "warn about names with invisible, bidi control or non-NFC characters"},
+	{"--color-rule", "color names matching a pattern, e.g. '*.sql=magenta bold'"},
+	{"--color-spec", "override name colors in dircolors syntax, e.g. 'di=34:*.go=32'"},
+	{"--exec", "run a command for each listed entry, {} is replaced by the path"},
+	{"-y, --yes", "skip confirmation for destructive --exec commands"},
+	{"--icon-map", "override icons by name, extension or kind (dir, file, link, exec)"},
+}
+
 func ShowColoredHelp(cmd *cobra.Command) {
 	fmt.Printf("\n%s %s\n\n",
 		color.New(color.FgCyan, color.Bold).Sprint("lu-hut "+constants.Version),
@@ -17,7 +105,7 @@ func ShowColoredHelp(cmd *cobra.Command) {
 	fmt.Printf("%s\n\n", color.New(color.FgHiBlack).Sprint("GitHub: https://github.com/ipanardian/lu-hut"))
 
 	fmt.Printf("%s\n\n", color.New(color.FgWhite).Sprint("USAGE:"))
-	fmt.Printf("  lu [path] [flags]\n")
+	fmt.Printf("  lu [path...] [flags]\n")
 	fmt.Printf("  lu [command]\n\n")
 
 	fmt.Printf("%s\n", color.New(color.FgWhite, color.Bold).Sprint("COMMANDS:"))
@@ -26,7 +114,7 @@ func ShowColoredHelp(cmd *cobra.Command) {
 	}{
 		{"update", "update lu to the latest version"},
 		{"version", "show version information"},
-		{"help", "show this help message"},
+		{"help [topic]", "show this help, or a page on flags, formats or themes"},
 	}
 
 	for _, c := range commands {
@@ -39,31 +127,7 @@ func ShowColoredHelp(cmd *cobra.Command) {
 
 	fmt.Printf("%s\n", color.New(color.FgWhite, color.Bold).Sprint("FLAGS:"))
 
-	flags := []struct {
-		flag, desc string
-	}{
-		{"-t, --sort-modified", "sort by modified time (newest first)"},
-		{"-S, --sort-size", "sort by file size (largest first)"},
-		{"-X, --sort-extension", "sort by file extension"},
-		{"-r, --reverse", "reverse sort order"},
-		{"-g, --git", "show git status inline"},
-		{"-h, --hidden", "show hidden files"},
-		{"-u, --user", "show user and group ownership metadata."},
-		{"-T, --exact-time", "show exact modification time instead of relative"},
-		{"-F, --tree", "display directory structure in a tree format."},
-		{"-R, --recursive", "list subdirectories recursively"},
-		{"-L, --max-depth", "maximum recursion depth (0 = no limit, default: 30)"},
-		{"-i, --include", "include files matching glob patterns (quote the pattern)"},
-		{"-x, --exclude", "exclude files matching glob patterns (quote the pattern)"},
-		{"-o, --octal", "show file permissions in octal format"},
-	}
-
-	for _, f := range flags {
-		fmt.Printf("  %s\t%s\n",
-			color.New(color.FgCyan, color.Bold).Sprintf("%-20s", f.flag),
-			color.New(color.FgHiWhite).Sprint(f.desc),
-		)
-	}
+	printFlags()
 
 	fmt.Printf("\n%s\n", color.New(color.FgWhite, color.Bold).Sprint("EXAMPLES:"))
 	examples := []string{
@@ -73,11 +137,13 @@ func ShowColoredHelp(cmd *cobra.Command) {
 		"lu -g",
 		"lu -S",
 		"lu -F",
+		"lu -F src pkg cmd",
 		"lu -i '*.go'",
 		"lu -x '*.tambang'",
 		"lu -hut (Lord's mode)",
 		"",
 		"lu help",
+		"lu help formats",
 		"lu version",
 		"lu version --check",
 		"lu update",
@@ -89,3 +155,12 @@ func ShowColoredHelp(cmd *cobra.Command) {
 
 	fmt.Println()
 }
+
+func printFlags() {
+	for _, f := range flagHelp {
+		fmt.Printf("  %s\t%s\n",
+			color.New(color.FgCyan, color.Bold).Sprintf("%-20s", f.flag),
+			color.New(color.FgHiWhite).Sprint(f.desc),
+		)
+	}
+}
@@ -0,0 +1,104 @@
+package terminal
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/fatih/color"
+)
+
+// ansiEscape matches SGR color sequences and OSC strings such as hyperlinks.
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;:]*[A-Za-z]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+
+// RedirectOutput sends everything written to stdout into the file at path,
+// and also to the original stdout when tee is set. The file is written to a
+// temporary name next to path and only renamed into place by the returned
+// function when keep is true, so readers never see a half-written report.
+// With plain set, escape sequences are stripped from the file copy.
+func RedirectOutput(path string, tee, plain bool) (func(keep bool) error, error) {
+	if path == "" {
+		return func(bool) error { return nil }, nil
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return nil, err
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, err
+	}
+
+	stdout, colorOutput := os.Stdout, color.Output
+	os.Stdout, color.Output = w, w
+
+	var console io.Writer
+	if tee {
+		console = stdout
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- copyOutput(r, tmp, console, plain)
+	}()
+
+	return func(keep bool) error {
+		w.Close()
+		copyErr := <-done
+		r.Close()
+		os.Stdout, color.Output = stdout, colorOutput
+
+		closeErr := tmp.Close()
+		if !keep || copyErr != nil || closeErr != nil {
+			os.Remove(tmp.Name())
+			if copyErr != nil {
+				return copyErr
+			}
+			return closeErr
+		}
+		if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+			os.Remove(tmp.Name())
+			return err
+		}
+		if err := os.Rename(tmp.Name(), path); err != nil {
+			os.Remove(tmp.Name())
+			return err
+		}
+		return nil
+	}, nil
+}
+
+func copyOutput(r io.Reader, file, console io.Writer, plain bool) error {
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(file)
+	var writeErr error
+
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			if console != nil {
+				console.Write(line)
+			}
+			if plain {
+				line = ansiEscape.ReplaceAll(line, nil)
+			}
+			if _, werr := bw.Write(line); werr != nil && writeErr == nil {
+				writeErr = werr
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	if err := bw.Flush(); err != nil && writeErr == nil {
+		writeErr = err
+	}
+	return writeErr
+}
@@ -0,0 +1,30 @@
+package terminal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCopyOutput(t *testing.T) {
+	input := "\x1b[32m┌──┐\x1b[0m\n\x1b]8;;file:///tmp/a\x1b\\a\x1b]8;;\x1b\\ \x1b[37;1mb\x1b[0;22m\nlast"
+
+	var file, console bytes.Buffer
+	if err := copyOutput(strings.NewReader(input), &file, &console, true); err != nil {
+		t.Fatalf("copyOutput() error = %v", err)
+	}
+	if want := "┌──┐\na b\nlast"; file.String() != want {
+		t.Errorf("file = %q, want %q", file.String(), want)
+	}
+	if console.String() != input {
+		t.Errorf("console = %q, want the unmodified input", console.String())
+	}
+
+	file.Reset()
+	if err := copyOutput(strings.NewReader(input), &file, nil, false); err != nil {
+		t.Fatalf("copyOutput() error = %v", err)
+	}
+	if file.String() != input {
+		t.Errorf("file = %q, want escapes kept", file.String())
+	}
+}
@@ -0,0 +1,20 @@
+package terminal
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// Reset puts an attached terminal back into a usable state after lu stopped
+// halfway through drawing: colors and attributes are cleared and the cursor
+// is shown again. Output that is not a terminal is left alone.
+func Reset() {
+	for _, f := range []*os.File{os.Stderr, os.Stdout} {
+		if term.IsTerminal(int(f.Fd())) && EnableVirtualTerminal() {
+			fmt.Fprint(f, "\x1b[0m\x1b[?25h")
+			return
+		}
+	}
+}
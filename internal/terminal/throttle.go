@@ -0,0 +1,61 @@
+package terminal
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// Throttle paces everything written to stdout to at most lines per second,
+// for serial consoles and slow links that drop or lag on large bursts. Once
+// ctx is cancelled the remaining queued output is discarded so Ctrl-C stops
+// the stream immediately. The returned function flushes and restores stdout.
+func Throttle(ctx context.Context, lines int) (func(), error) {
+	if lines <= 0 {
+		return func() {}, nil
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stdout, colorOutput := os.Stdout, color.Output
+	os.Stdout, color.Output = w, w
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		pace(ctx, bufio.NewReader(r), stdout, time.Second/time.Duration(lines))
+	}()
+
+	return func() {
+		w.Close()
+		<-done
+		r.Close()
+		os.Stdout, color.Output = stdout, colorOutput
+	}, nil
+}
+
+func pace(ctx context.Context, r *bufio.Reader, out io.Writer, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 && ctx.Err() == nil {
+			select {
+			case <-ticker.C:
+				out.Write(line)
+			case <-ctx.Done():
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
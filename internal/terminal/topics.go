@@ -0,0 +1,123 @@
+package terminal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/ipanardian/lu-hut/internal/theme"
+)
+
+// HelpTopics are the pages lu help TOPIC can show.
+var HelpTopics = []string{"flags", "formats", "themes"}
+
+// ShowHelpTopic prints the help page for topic and reports whether there
+// is one.
+func ShowHelpTopic(topic string) bool {
+	switch strings.ToLower(topic) {
+	case "flags":
+		topicHeader("flags", "every listing flag")
+		printFlags()
+		fmt.Println()
+		printNote("Every boolean flag also has a --no- form that turns a configured default off.")
+	case "formats":
+		showFormats()
+	case "themes":
+		showThemes()
+	default:
+		return false
+	}
+	fmt.Println()
+	return true
+}
+
+func topicHeader(topic, desc string) {
+	fmt.Println()
+	color.Cyan("lu help %s - %s", topic, desc)
+	fmt.Println()
+}
+
+func printSection(title string) {
+	fmt.Printf("%s\n", color.New(color.FgWhite, color.Bold).Sprint(title))
+}
+
+func printRows(rows [][2]string) {
+	for _, row := range rows {
+		fmt.Printf("  %s\t%s\n",
+			color.New(color.FgCyan, color.Bold).Sprintf("%-20s", row[0]),
+			color.New(color.FgHiWhite).Sprint(row[1]),
+		)
+	}
+}
+
+func printNote(note string) {
+	fmt.Printf("  %s\n", color.New(color.FgHiBlack).Sprint(note))
+}
+
+func showFormats() {
+	topicHeader("formats", "output formats and where output goes")
+
+	printSection("FORMATS (--format):")
+	printRows([][2]string{
+		{"table", "box-drawn table, the default; -F draws a tree instead"},
+		{"json", "one array of entries with raw sizes and timestamps, also with -R"},
+		{"flat", "with -F, one depth<TAB>path<TAB>type<TAB>size line per entry"},
+	})
+	fmt.Println()
+
+	printSection("OUTPUT:")
+	printRows([][2]string{
+		{"--output FILE", "write the listing to FILE, replaced atomically when complete"},
+		{"--tee", "with --output, also print the listing to the terminal"},
+		{"--color", "always, auto or never; json and flat are never colored"},
+		{"--ascii", "plain ASCII borders and tree glyphs"},
+		{"--border-style", "single, double, bold or ascii table borders"},
+		{"--align-units", "right-align sizes so their units line up"},
+	})
+	fmt.Println()
+
+	printSection("EXAMPLES:")
+	for _, ex := range []string{
+		"lu --format json | jq '.[] | select(.size > 1e6)'",
+		"lu -F --format flat | awk -F'\\t' '$3 == \"file\"'",
+		"lu -R --output listing.txt",
+	} {
+		fmt.Printf("  %s\n", color.New(color.FgGreen).Sprint(ex))
+	}
+}
+
+func showThemes() {
+	topicHeader("themes", "colors, presets and theme files")
+
+	printSection("PRESETS (--theme NAME):")
+	fmt.Printf("  %s\n", color.New(color.FgHiWhite).Sprint(strings.Join(theme.Presets(), ", ")))
+	printNote("Without --theme, light is picked when COLORFGBG reports a light background;")
+	printNote("--theme auto also asks the terminal itself (OSC 11).")
+	fmt.Println()
+
+	printSection("THEME FILES (--theme FILE.toml):")
+	printRows([][2]string{
+		{"border, header", "table border and header styles"},
+		{"[names]", "file, directory, symlink, executable, generated, ..."},
+		{"[extensions]", "styles by extension, e.g. \".sql\" = \"magenta bold\""},
+		{"[[rules]]", "pattern and style, checked before names and extensions"},
+		{"[age], [git_symbols]", "Modified column colors and git status symbols"},
+	})
+	printNote("A theme file only sets what it changes; the rest comes from the default theme.")
+	fmt.Println()
+
+	printSection("STYLES:")
+	printRows([][2]string{
+		{"colors", "green, hi-red, bg-blue, #ff79c6, 38;5;208"},
+		{"attributes", "bold, dim, italic, underline"},
+	})
+	fmt.Println()
+
+	printSection("RELATED:")
+	printRows([][2]string{
+		{"--color-rule", "color names matching a pattern, e.g. '*.sql=magenta bold'"},
+		{"--color-spec", "override name colors in dircolors syntax, e.g. 'di=34:*.go=32'"},
+		{"LS_COLORS", "used for names when set, like GNU ls"},
+		{"lu config import-dircolors", "convert a dircolors database into a theme file"},
+	})
+}
@@ -0,0 +1,146 @@
+package theme
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var dircolorsNames = map[string]string{
+	"NORMAL":  "file",
+	"NORM":    "file",
+	"FILE":    "file",
+	"FI":      "file",
+	"DIR":     "directory",
+	"DI":      "directory",
+	"LINK":    "symlink",
+	"LNK":     "symlink",
+	"SYMLINK": "symlink",
+	"LN":      "symlink",
+	"EXEC":    "executable",
+	"EX":      "executable",
+}
+
+// dircolorsIgnored are directives that only configure the dircolors tool
+// itself and have nothing to convert.
+var dircolorsIgnored = map[string]bool{
+	"TERM":      true,
+	"COLORTERM": true,
+	"COLOR":     true,
+	"OPTIONS":   true,
+	"EIGHTBIT":  true,
+	"RESET":     true,
+	"RS":        true,
+	"LEFTCODE":  true,
+	"RIGHTCODE": true,
+	"ENDCODE":   true,
+}
+
+// ConvertDircolors reads a dircolors database (as used by GNU ls) and writes
+// the equivalent theme file. Simple extensions become [extensions] entries,
+// other suffix patterns become [[rules]], and file types lu cannot color
+// separately are kept as comments.
+func ConvertDircolors(r io.Reader, w io.Writer) error {
+	names := make(map[string]string)
+	extensions := make(map[string]string)
+	var (
+		rules       [][2]string
+		unsupported []string
+	)
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		fields := strings.Fields(scanner.Text())
+		for i, field := range fields {
+			if strings.HasPrefix(field, "#") {
+				fields = fields[:i]
+				break
+			}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		key := fields[0]
+		if dircolorsIgnored[strings.ToUpper(key)] {
+			continue
+		}
+		if len(fields) < 2 {
+			return fmt.Errorf("line %d: missing style for %s", lineNo, key)
+		}
+
+		value := fields[1]
+		if _, err := ParseStyle(value); err != nil {
+			return fmt.Errorf("line %d: %w", lineNo, err)
+		}
+
+		switch {
+		case strings.HasPrefix(key, "."):
+			extensions[strings.ToLower(key)] = value
+		case strings.HasPrefix(key, "*"):
+			suffix := key[1:]
+			if strings.HasPrefix(suffix, ".") && !strings.ContainsAny(suffix[1:], ".*?[") {
+				extensions[strings.ToLower(suffix)] = value
+			} else {
+				rules = append(rules, [2]string{key, value})
+			}
+		default:
+			if name, ok := dircolorsNames[strings.ToUpper(key)]; ok {
+				names[name] = value
+			} else {
+				unsupported = append(unsupported, key+" "+value)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	// dircolors picks the longest matching suffix while lu uses the first
+	// matching rule, so longer patterns go first.
+	sort.SliceStable(rules, func(i, j int) bool { return len(rules[i][0]) > len(rules[j][0]) })
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "# Converted from a dircolors database by lu config import-dircolors.")
+	fmt.Fprintln(bw, "gradient = false")
+	fmt.Fprintln(bw, "replace_extensions = true")
+
+	if len(names) > 0 {
+		fmt.Fprintln(bw, "\n[names]")
+		for _, key := range sortedKeys(names) {
+			fmt.Fprintf(bw, "%s = %s\n", key, strconv.Quote(names[key]))
+		}
+	}
+
+	if len(extensions) > 0 {
+		fmt.Fprintln(bw, "\n[extensions]")
+		for _, key := range sortedKeys(extensions) {
+			fmt.Fprintf(bw, "%s = %s\n", strconv.Quote(key), strconv.Quote(extensions[key]))
+		}
+	}
+
+	for _, rule := range rules {
+		fmt.Fprintf(bw, "\n[[rules]]\npattern = %s\nstyle = %s\n", strconv.Quote(rule[0]), strconv.Quote(rule[1]))
+	}
+
+	if len(unsupported) > 0 {
+		fmt.Fprintln(bw, "\n# Not supported by lu themes:")
+		for _, entry := range unsupported {
+			fmt.Fprintf(bw, "# %s\n", entry)
+		}
+	}
+
+	return bw.Flush()
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,534 @@
+package theme
+
+import "github.com/fatih/color"
+
+var presets = map[string]func() *Theme{
+	"default":     Default,
+	"monochrome":  Monochrome,
+	"solarized":   Solarized,
+	"dracula":     Dracula,
+	"light":       Light,
+	"cb-friendly": ColorBlind,
+}
+
+func c256(n int) []color.Attribute {
+	return []color.Attribute{38, 5, color.Attribute(n)}
+}
+
+func bg256(n int) []color.Attribute {
+	return []color.Attribute{48, 5, color.Attribute(n)}
+}
+
+func style(attrs ...[]color.Attribute) Style {
+	var s Style
+	for _, a := range attrs {
+		s = append(s, a...)
+	}
+	return s
+}
+
+func attr(a ...color.Attribute) []color.Attribute {
+	return a
+}
+
+func Default() *Theme {
+	return &Theme{
+		Name:      "default",
+		Gradient:  true,
+		Border:    Style{color.FgGreen},
+		Header:    Style{color.FgWhite, color.Bold},
+		Size:      Style{color.FgHiWhite},
+		DirSize:   Style{color.FgCyan},
+		Muted:     Style{color.FgHiBlack},
+		Accent:    Style{color.FgCyan},
+		Warning:   Style{color.FgYellow, color.Bold},
+		Highlight: Style{color.BgHiBlack, color.Bold},
+		Names: Names{
+			Directory:  Style{color.FgBlue, color.Bold},
+			Symlink:    Style{color.FgMagenta, color.Bold},
+			Broken:     Style{color.FgRed},
+			Target:     Style{color.FgHiBlack},
+			Executable: Style{color.FgRed},
+			Hidden:     Style{color.FgYellow},
+			Generated:  Style{color.FgHiBlack},
+			File:       Style{color.FgWhite},
+		},
+		Extensions: map[string]Style{
+			".go":   {color.FgGreen},
+			".rs":   {color.FgGreen},
+			".py":   {color.FgGreen},
+			".js":   {color.FgGreen},
+			".ts":   {color.FgGreen},
+			".jsx":  {color.FgGreen},
+			".tsx":  {color.FgGreen},
+			".md":   {color.FgYellow},
+			".txt":  {color.FgYellow},
+			".rst":  {color.FgYellow},
+			".yml":  {color.FgMagenta},
+			".yaml": {color.FgMagenta},
+			".json": {color.FgMagenta},
+			".toml": {color.FgMagenta},
+			".ini":  {color.FgMagenta},
+		},
+		Permissions: Permissions{
+			Directory: Style{color.FgCyan, color.Bold},
+			Symlink:   Style{color.FgMagenta, color.Bold},
+			Device:    Style{color.FgYellow, color.Bold},
+			File:      Style{color.FgCyan},
+			Read:      Style{color.FgGreen, color.Bold},
+			Write:     Style{color.FgYellow, color.Bold},
+			Exec:      Style{color.FgRed, color.Bold},
+			Setid:     Style{color.FgMagenta, color.Bold},
+			Sticky:    Style{color.FgRed, color.Bold},
+			None:      Style{color.FgHiBlack},
+			Octal:     Style{color.FgHiWhite},
+		},
+		Age: Age{
+			Future:  Style{color.FgBlue},
+			Seconds: Style{color.FgGreen},
+			Minutes: Style{color.FgGreen},
+			Hours:   Style{color.FgYellow},
+			Days:    Style{color.FgHiYellow},
+			Weeks:   Style{color.FgRed},
+			Months:  Style{color.FgHiRed},
+			Years:   Style{color.FgHiBlack},
+			Exact:   Style{color.FgHiWhite},
+		},
+		Git: Git{
+			Untracked: Style{color.FgRed, color.Bold},
+			Added:     Style{color.FgGreen, color.Bold},
+			Modified:  Style{color.FgYellow, color.Bold},
+			Deleted:   Style{color.FgRed},
+			Renamed:   Style{color.FgCyan, color.Bold},
+			Other:     Style{color.FgYellow},
+			Conflict:  Style{color.BgRed, color.FgHiWhite, color.Bold},
+		},
+	}
+}
+
+// Monochrome keeps emphasis (bold, dim, underline) but uses no colors.
+func Monochrome() *Theme {
+	bold := Style{color.Bold}
+	dim := Style{color.Faint}
+	plain := Style{}
+
+	return &Theme{
+		Name:       "monochrome",
+		Border:     plain,
+		Header:     bold,
+		Size:       plain,
+		DirSize:    dim,
+		Muted:      dim,
+		Accent:     plain,
+		Warning:    Style{color.Bold, color.ReverseVideo},
+		Highlight:  Style{color.ReverseVideo},
+		Extensions: map[string]Style{},
+		Names: Names{
+			Directory:  bold,
+			Symlink:    Style{color.Underline},
+			Broken:     Style{color.CrossedOut},
+			Target:     dim,
+			Executable: Style{color.Bold, color.Underline},
+			Hidden:     dim,
+			Generated:  dim,
+			File:       plain,
+		},
+		Permissions: Permissions{
+			Directory: bold,
+			Symlink:   bold,
+			Device:    bold,
+			File:      plain,
+			Read:      plain,
+			Write:     plain,
+			Exec:      bold,
+			Setid:     Style{color.Bold, color.Underline},
+			Sticky:    Style{color.Bold, color.Underline},
+			None:      dim,
+			Octal:     plain,
+		},
+		Age: Age{
+			Future:  bold,
+			Seconds: bold,
+			Minutes: bold,
+			Hours:   plain,
+			Days:    plain,
+			Weeks:   plain,
+			Months:  dim,
+			Years:   dim,
+			Exact:   plain,
+		},
+		Git: Git{
+			Untracked: bold,
+			Added:     bold,
+			Modified:  bold,
+			Deleted:   Style{color.Bold, color.Underline},
+			Renamed:   bold,
+			Other:     plain,
+			Conflict:  Style{color.ReverseVideo, color.Bold},
+		},
+	}
+}
+
+func Solarized() *Theme {
+	var (
+		base01  = c256(240)
+		base0   = c256(244)
+		base1   = c256(245)
+		yellow  = c256(136)
+		orange  = c256(166)
+		red     = c256(160)
+		magenta = c256(125)
+		violet  = c256(61)
+		blue    = c256(33)
+		cyan    = c256(37)
+		green   = c256(64)
+		bold    = attr(color.Bold)
+	)
+
+	return &Theme{
+		Name:      "solarized",
+		Border:    style(base01),
+		Header:    style(base1, bold),
+		Size:      style(base1),
+		DirSize:   style(base01),
+		Muted:     style(base01),
+		Accent:    style(cyan),
+		Warning:   style(orange, bold),
+		Highlight: style(bg256(235), bold),
+		Names: Names{
+			Directory:  style(blue, bold),
+			Symlink:    style(violet, bold),
+			Broken:     style(red),
+			Target:     style(base01),
+			Executable: style(red),
+			Hidden:     style(base01),
+			Generated:  style(base01),
+			File:       style(base0),
+		},
+		Extensions: map[string]Style{
+			".go":   style(green),
+			".rs":   style(green),
+			".py":   style(green),
+			".js":   style(green),
+			".ts":   style(green),
+			".jsx":  style(green),
+			".tsx":  style(green),
+			".md":   style(yellow),
+			".txt":  style(yellow),
+			".rst":  style(yellow),
+			".yml":  style(magenta),
+			".yaml": style(magenta),
+			".json": style(magenta),
+			".toml": style(magenta),
+			".ini":  style(magenta),
+		},
+		Permissions: Permissions{
+			Directory: style(blue, bold),
+			Symlink:   style(violet, bold),
+			Device:    style(yellow, bold),
+			File:      style(base01),
+			Read:      style(green),
+			Write:     style(yellow),
+			Exec:      style(red),
+			Setid:     style(magenta, bold),
+			Sticky:    style(orange, bold),
+			None:      style(base01),
+			Octal:     style(base1),
+		},
+		Age: Age{
+			Future:  style(blue),
+			Seconds: style(green),
+			Minutes: style(green),
+			Hours:   style(cyan),
+			Days:    style(yellow),
+			Weeks:   style(orange),
+			Months:  style(red),
+			Years:   style(base01),
+			Exact:   style(base1),
+		},
+		Git: Git{
+			Untracked: style(red, bold),
+			Added:     style(green, bold),
+			Modified:  style(yellow, bold),
+			Deleted:   style(red),
+			Renamed:   style(cyan, bold),
+			Other:     style(orange),
+			Conflict:  style(bg256(160), c256(230), bold),
+		},
+	}
+}
+
+func Dracula() *Theme {
+	var (
+		comment = c256(61)
+		fg      = c256(255)
+		cyan    = c256(117)
+		green   = c256(84)
+		orange  = c256(215)
+		pink    = c256(212)
+		purple  = c256(141)
+		red     = c256(203)
+		yellow  = c256(228)
+		bold    = attr(color.Bold)
+	)
+
+	return &Theme{
+		Name:      "dracula",
+		Border:    style(purple),
+		Header:    style(pink, bold),
+		Size:      style(fg),
+		DirSize:   style(comment),
+		Muted:     style(comment),
+		Accent:    style(cyan),
+		Warning:   style(orange, bold),
+		Highlight: style(bg256(238), bold),
+		Names: Names{
+			Directory:  style(purple, bold),
+			Symlink:    style(cyan, bold),
+			Broken:     style(red),
+			Target:     style(comment),
+			Executable: style(green, bold),
+			Hidden:     style(comment),
+			Generated:  style(comment),
+			File:       style(fg),
+		},
+		Extensions: map[string]Style{
+			".go":   style(cyan),
+			".rs":   style(orange),
+			".py":   style(yellow),
+			".js":   style(yellow),
+			".ts":   style(cyan),
+			".jsx":  style(cyan),
+			".tsx":  style(cyan),
+			".md":   style(pink),
+			".txt":  style(fg),
+			".rst":  style(pink),
+			".yml":  style(orange),
+			".yaml": style(orange),
+			".json": style(orange),
+			".toml": style(orange),
+			".ini":  style(orange),
+		},
+		Permissions: Permissions{
+			Directory: style(purple, bold),
+			Symlink:   style(cyan, bold),
+			Device:    style(yellow, bold),
+			File:      style(comment),
+			Read:      style(green),
+			Write:     style(yellow),
+			Exec:      style(red),
+			Setid:     style(pink, bold),
+			Sticky:    style(orange, bold),
+			None:      style(comment),
+			Octal:     style(fg),
+		},
+		Age: Age{
+			Future:  style(purple),
+			Seconds: style(green),
+			Minutes: style(green),
+			Hours:   style(cyan),
+			Days:    style(yellow),
+			Weeks:   style(orange),
+			Months:  style(red),
+			Years:   style(comment),
+			Exact:   style(fg),
+		},
+		Git: Git{
+			Untracked: style(red, bold),
+			Added:     style(green, bold),
+			Modified:  style(orange, bold),
+			Deleted:   style(red),
+			Renamed:   style(purple, bold),
+			Other:     style(yellow),
+			Conflict:  style(bg256(203), c256(235), bold),
+		},
+	}
+}
+
+// Light is tuned for white or light backgrounds, where the default theme's
+// white and yellow styles are hard to read.
+func Light() *Theme {
+	var (
+		gray    = c256(244)
+		dark    = c256(235)
+		yellow  = c256(130)
+		orange  = c256(166)
+		red     = c256(124)
+		magenta = c256(90)
+		blue    = c256(25)
+		cyan    = c256(30)
+		green   = c256(28)
+		bold    = attr(color.Bold)
+	)
+
+	return &Theme{
+		Name:      "light",
+		Border:    style(gray),
+		Header:    style(dark, bold),
+		Size:      style(dark),
+		DirSize:   style(cyan),
+		Muted:     style(gray),
+		Accent:    style(cyan),
+		Warning:   style(orange, bold),
+		Highlight: style(bg256(254), bold),
+		Names: Names{
+			Directory:  style(blue, bold),
+			Symlink:    style(magenta, bold),
+			Broken:     style(red),
+			Target:     style(gray),
+			Executable: style(red),
+			Hidden:     style(gray),
+			Generated:  style(gray),
+			File:       style(dark),
+		},
+		Extensions: map[string]Style{
+			".go":   style(green),
+			".rs":   style(green),
+			".py":   style(green),
+			".js":   style(green),
+			".ts":   style(green),
+			".jsx":  style(green),
+			".tsx":  style(green),
+			".md":   style(yellow),
+			".txt":  style(yellow),
+			".rst":  style(yellow),
+			".yml":  style(magenta),
+			".yaml": style(magenta),
+			".json": style(magenta),
+			".toml": style(magenta),
+			".ini":  style(magenta),
+		},
+		Permissions: Permissions{
+			Directory: style(blue, bold),
+			Symlink:   style(magenta, bold),
+			Device:    style(yellow, bold),
+			File:      style(cyan),
+			Read:      style(green, bold),
+			Write:     style(yellow, bold),
+			Exec:      style(red, bold),
+			Setid:     style(magenta, bold),
+			Sticky:    style(red, bold),
+			None:      style(gray),
+			Octal:     style(dark),
+		},
+		Age: Age{
+			Future:  style(blue),
+			Seconds: style(green),
+			Minutes: style(green),
+			Hours:   style(yellow),
+			Days:    style(orange),
+			Weeks:   style(red),
+			Months:  style(magenta),
+			Years:   style(gray),
+			Exact:   style(dark),
+		},
+		Git: Git{
+			Untracked: style(red, bold),
+			Added:     style(green, bold),
+			Modified:  style(yellow, bold),
+			Deleted:   style(red),
+			Renamed:   style(cyan, bold),
+			Other:     style(orange),
+			Conflict:  style(bg256(124), c256(255), bold),
+		},
+	}
+}
+
+// ColorBlind uses the Okabe-Ito palette, which stays distinguishable with
+// deuteranopia and protanopia. Red/green pairs become blue/orange, and git
+// states get symbols so they never depend on hue alone.
+func ColorBlind() *Theme {
+	var (
+		gray       = c256(245)
+		white      = c256(255)
+		orange     = c256(214)
+		skyBlue    = c256(74)
+		bluish     = c256(36)
+		yellow     = c256(227)
+		blue       = c256(33)
+		vermillion = c256(166)
+		purple     = c256(175)
+		bold       = attr(color.Bold)
+		underline  = attr(color.Underline)
+	)
+
+	return &Theme{
+		Name:      "cb-friendly",
+		Border:    style(skyBlue),
+		Header:    style(white, bold),
+		Size:      style(white),
+		DirSize:   style(skyBlue),
+		Muted:     style(gray),
+		Accent:    style(skyBlue),
+		Warning:   style(orange, bold),
+		Highlight: style(bg256(238), bold),
+		Names: Names{
+			Directory:  style(blue, bold),
+			Symlink:    style(purple, bold),
+			Broken:     style(vermillion, bold),
+			Target:     style(gray),
+			Executable: style(vermillion, underline),
+			Hidden:     style(gray),
+			Generated:  style(gray),
+			File:       style(white),
+		},
+		Extensions: map[string]Style{
+			".go":   style(skyBlue),
+			".rs":   style(skyBlue),
+			".py":   style(skyBlue),
+			".js":   style(skyBlue),
+			".ts":   style(skyBlue),
+			".jsx":  style(skyBlue),
+			".tsx":  style(skyBlue),
+			".md":   style(yellow),
+			".txt":  style(yellow),
+			".rst":  style(yellow),
+			".yml":  style(purple),
+			".yaml": style(purple),
+			".json": style(purple),
+			".toml": style(purple),
+			".ini":  style(purple),
+		},
+		Permissions: Permissions{
+			Directory: style(blue, bold),
+			Symlink:   style(purple, bold),
+			Device:    style(yellow, bold),
+			File:      style(skyBlue),
+			Read:      style(blue, bold),
+			Write:     style(orange, bold),
+			Exec:      style(vermillion, bold, underline),
+			Setid:     style(purple, bold),
+			Sticky:    style(purple, bold),
+			None:      style(gray),
+			Octal:     style(white),
+		},
+		Age: Age{
+			Future:  style(purple),
+			Seconds: style(skyBlue),
+			Minutes: style(skyBlue),
+			Hours:   style(bluish),
+			Days:    style(yellow),
+			Weeks:   style(orange),
+			Months:  style(vermillion),
+			Years:   style(gray),
+			Exact:   style(white),
+		},
+		Git: Git{
+			Untracked: style(purple, bold),
+			Added:     style(blue, bold),
+			Modified:  style(orange, bold),
+			Deleted:   style(vermillion, bold),
+			Renamed:   style(skyBlue, bold),
+			Other:     style(yellow),
+			Conflict:  style(bg256(166), c256(255), bold),
+		},
+		GitSymbols: GitSymbols{
+			Untracked: "?",
+			Added:     "+",
+			Modified:  "~",
+			Deleted:   "-",
+			Renamed:   ">",
+			Conflict:  "!",
+		},
+	}
+}
@@ -0,0 +1,390 @@
+// Package theme defines the color themes used by the renderers.
+package theme
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/fatih/color"
+)
+
+// Style is a list of SGR attributes. In theme files it is written as a
+// space separated list of names ("hi-red bold", "bg-blue"), raw SGR codes
+// ("38;5;208") or a truecolor hex value ("#ff79c6").
+type Style []color.Attribute
+
+func (s Style) Color() *color.Color {
+	return color.New(s...)
+}
+
+func (s *Style) UnmarshalText(text []byte) error {
+	parsed, err := ParseStyle(string(text))
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
+var colorNames = map[string]color.Attribute{
+	"black":   color.FgBlack,
+	"red":     color.FgRed,
+	"green":   color.FgGreen,
+	"yellow":  color.FgYellow,
+	"blue":    color.FgBlue,
+	"magenta": color.FgMagenta,
+	"cyan":    color.FgCyan,
+	"white":   color.FgWhite,
+}
+
+var attributeNames = map[string]color.Attribute{
+	"bold":      color.Bold,
+	"dim":       color.Faint,
+	"faint":     color.Faint,
+	"italic":    color.Italic,
+	"underline": color.Underline,
+	"blink":     color.BlinkSlow,
+	"reverse":   color.ReverseVideo,
+	"none":      color.Reset,
+}
+
+func ParseStyle(spec string) (Style, error) {
+	var style Style
+
+	for _, token := range strings.FieldsFunc(spec, func(r rune) bool { return r == ' ' || r == ',' }) {
+		token = strings.ToLower(token)
+
+		if attr, ok := attributeNames[token]; ok {
+			style = append(style, attr)
+			continue
+		}
+
+		if hex, ok := strings.CutPrefix(token, "#"); ok {
+			rgb, err := strconv.ParseUint(hex, 16, 32)
+			if err != nil || len(hex) != 6 {
+				return nil, fmt.Errorf("invalid hex color: %s", token)
+			}
+			style = append(style, 38, 2, color.Attribute(rgb>>16), color.Attribute(rgb>>8&0xff), color.Attribute(rgb&0xff))
+			continue
+		}
+
+		name := token
+		base := color.Attribute(0)
+		if rest, ok := strings.CutPrefix(name, "bg-"); ok {
+			name = rest
+			base = color.BgBlack - color.FgBlack
+		}
+		if rest, ok := strings.CutPrefix(name, "hi-"); ok {
+			name = rest
+			base += color.FgHiBlack - color.FgBlack
+		}
+		if attr, ok := colorNames[name]; ok {
+			style = append(style, attr+base)
+			continue
+		}
+
+		codes := strings.Split(token, ";")
+		for _, code := range codes {
+			n, err := strconv.Atoi(code)
+			if err != nil || n < 0 || n > 255 {
+				return nil, fmt.Errorf("invalid style %q in %q", token, spec)
+			}
+			style = append(style, color.Attribute(n))
+		}
+	}
+
+	return style, nil
+}
+
+type Names struct {
+	Directory  Style `toml:"directory"`
+	Symlink    Style `toml:"symlink"`
+	Broken     Style `toml:"broken"`
+	Target     Style `toml:"target"`
+	Executable Style `toml:"executable"`
+	Hidden     Style `toml:"hidden"`
+	Generated  Style `toml:"generated"`
+	File       Style `toml:"file"`
+}
+
+type Permissions struct {
+	Directory Style `toml:"directory"`
+	Symlink   Style `toml:"symlink"`
+	Device    Style `toml:"device"`
+	File      Style `toml:"file"`
+	Read      Style `toml:"read"`
+	Write     Style `toml:"write"`
+	Exec      Style `toml:"exec"`
+	Setid     Style `toml:"setid"`
+	Sticky    Style `toml:"sticky"`
+	None      Style `toml:"none"`
+	Octal     Style `toml:"octal"`
+}
+
+type Age struct {
+	Future  Style `toml:"future"`
+	Seconds Style `toml:"seconds"`
+	Minutes Style `toml:"minutes"`
+	Hours   Style `toml:"hours"`
+	Days    Style `toml:"days"`
+	Weeks   Style `toml:"weeks"`
+	Months  Style `toml:"months"`
+	Years   Style `toml:"years"`
+	Exact   Style `toml:"exact"`
+}
+
+type Git struct {
+	Untracked Style `toml:"untracked"`
+	Added     Style `toml:"added"`
+	Modified  Style `toml:"modified"`
+	Deleted   Style `toml:"deleted"`
+	Renamed   Style `toml:"renamed"`
+	Other     Style `toml:"other"`
+	Conflict  Style `toml:"conflict"`
+}
+
+// GitSymbols replaces the git status letters, so states stay distinct
+// without relying on color. Empty symbols keep the letters.
+type GitSymbols struct {
+	Untracked string `toml:"untracked"`
+	Added     string `toml:"added"`
+	Modified  string `toml:"modified"`
+	Deleted   string `toml:"deleted"`
+	Renamed   string `toml:"renamed"`
+	Conflict  string `toml:"conflict"`
+}
+
+// Rule colors names matching a glob pattern, overriding the built-in
+// name and extension styles.
+type Rule struct {
+	Pattern string `toml:"pattern"`
+	Style   Style  `toml:"style"`
+}
+
+// ParseRule parses a rule written as "pattern=style", e.g. "*.sql=magenta bold".
+func ParseRule(spec string) (Rule, error) {
+	pattern, styleSpec, ok := strings.Cut(spec, "=")
+	pattern = strings.TrimSpace(pattern)
+	if !ok || pattern == "" {
+		return Rule{}, fmt.Errorf("invalid color rule %q (expected pattern=style)", spec)
+	}
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return Rule{}, fmt.Errorf("invalid color rule pattern %q: %w", pattern, err)
+	}
+
+	style, err := ParseStyle(styleSpec)
+	if err != nil {
+		return Rule{}, err
+	}
+	return Rule{Pattern: pattern, Style: style}, nil
+}
+
+// Duration is a time span written with a unit suffix: s, m, h, d, w or y
+// ("90d", "6h", "1y").
+type Duration time.Duration
+
+var durationUnits = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+	"y": 365 * 24 * time.Hour,
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	spec := strings.TrimSpace(string(text))
+	i := strings.IndexFunc(spec, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if i <= 0 {
+		return fmt.Errorf("invalid duration %q (e.g. 90d)", spec)
+	}
+
+	n, err := strconv.ParseFloat(spec[:i], 64)
+	unit, ok := durationUnits[strings.ToLower(spec[i:])]
+	if err != nil || !ok {
+		return fmt.Errorf("invalid duration %q (e.g. 90d)", spec)
+	}
+	*d = Duration(n * float64(unit))
+	return nil
+}
+
+// ByteSize is a size written with an optional binary unit suffix: B, KB,
+// MB, GB or TB, or just K, M, G or T ("512KB", "1.5GB", "10M"). Units are
+// powers of 1024, matching the Size column.
+type ByteSize int64
+
+var sizeUnits = map[string]float64{
+	"":   1,
+	"b":  1,
+	"kb": 1 << 10,
+	"mb": 1 << 20,
+	"gb": 1 << 30,
+	"tb": 1 << 40,
+	"k":  1 << 10,
+	"m":  1 << 20,
+	"g":  1 << 30,
+	"t":  1 << 40,
+}
+
+func (s *ByteSize) UnmarshalText(text []byte) error {
+	spec := strings.TrimSpace(string(text))
+	i := strings.IndexFunc(spec, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if i < 0 {
+		i = len(spec)
+	}
+
+	n, err := strconv.ParseFloat(spec[:i], 64)
+	unit, ok := sizeUnits[strings.ToLower(strings.TrimSpace(spec[i:]))]
+	if err != nil || !ok {
+		return fmt.Errorf("invalid size %q (e.g. 100MB)", spec)
+	}
+	*s = ByteSize(n * unit)
+	return nil
+}
+
+// AgeThreshold colors the Modified column for entries at least After old.
+type AgeThreshold struct {
+	After Duration `toml:"after"`
+	Style Style    `toml:"style"`
+}
+
+// SizeThreshold colors the Size column for files of at least Above bytes.
+type SizeThreshold struct {
+	Above ByteSize `toml:"above"`
+	Style Style    `toml:"style"`
+}
+
+type Theme struct {
+	Name        string           `toml:"name"`
+	Gradient    bool             `toml:"gradient"`
+	Border      Style            `toml:"border"`
+	Header      Style            `toml:"header"`
+	Size        Style            `toml:"size"`
+	DirSize     Style            `toml:"dir_size"`
+	Muted       Style            `toml:"muted"`
+	Accent      Style            `toml:"accent"`
+	Warning     Style            `toml:"warning"`
+	Highlight   Style            `toml:"highlight"`
+	Names       Names            `toml:"names"`
+	Extensions  map[string]Style `toml:"extensions"`
+	Permissions Permissions      `toml:"permissions"`
+	Age         Age              `toml:"age"`
+	Git         Git              `toml:"git"`
+	GitSymbols  GitSymbols       `toml:"git_symbols"`
+	Rules       []Rule           `toml:"rules"`
+
+	AgeThresholds  []AgeThreshold  `toml:"age_thresholds"`
+	SizeThresholds []SizeThreshold `toml:"size_thresholds"`
+
+	// ReplaceExtensions drops the default extension colors instead of
+	// layering the file's [extensions] over them.
+	ReplaceExtensions bool `toml:"replace_extensions"`
+}
+
+// AgeStyle returns the style of the largest age threshold that age reaches.
+// It reports false when no thresholds are configured or none is reached.
+func (t *Theme) AgeStyle(age time.Duration) (Style, bool) {
+	var (
+		best  Style
+		limit = time.Duration(-1)
+	)
+	for _, th := range t.AgeThresholds {
+		if after := time.Duration(th.After); age >= after && after > limit {
+			best, limit = th.Style, after
+		}
+	}
+	return best, limit >= 0
+}
+
+// SizeStyle returns the style of the largest size threshold that size reaches.
+func (t *Theme) SizeStyle(size int64) (Style, bool) {
+	var (
+		best  Style
+		limit = int64(-1)
+	)
+	for _, th := range t.SizeThresholds {
+		if above := int64(th.Above); size >= above && above > limit {
+			best, limit = th.Style, above
+		}
+	}
+	return best, limit >= 0
+}
+
+// Match returns the style of the first rule whose pattern matches name.
+func (t *Theme) Match(name string) (Style, bool) {
+	for _, rule := range t.Rules {
+		if matched, _ := filepath.Match(rule.Pattern, name); matched {
+			return rule.Style, true
+		}
+	}
+	return nil, false
+}
+
+// Extension returns the style for a lower-cased extension including its
+// leading dot, falling back to the plain file style.
+func (t *Theme) Extension(ext string) Style {
+	if style, ok := t.Extensions[ext]; ok {
+		return style
+	}
+	return t.Names.File
+}
+
+func Presets() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Auto is the theme name that asks the terminal for its background color
+// and picks light or default to match. Callers resolve it before Load,
+// which treats it as the default theme.
+const Auto = "auto"
+
+// Load returns the preset with the given name, or reads a TOML theme file
+// from path. Theme files only need to set the styles they change; anything
+// left out keeps the default theme's value.
+func Load(nameOrPath string) (*Theme, error) {
+	if nameOrPath == "" || nameOrPath == Auto {
+		return Default(), nil
+	}
+
+	if preset, ok := presets[nameOrPath]; ok {
+		return preset(), nil
+	}
+
+	data, err := os.ReadFile(nameOrPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("unknown theme %q (available: %s)", nameOrPath, strings.Join(Presets(), ", "))
+		}
+		return nil, err
+	}
+
+	t := Default()
+	if _, err := toml.Decode(string(data), t); err != nil {
+		return nil, fmt.Errorf("invalid theme %s: %w", nameOrPath, err)
+	}
+
+	if t.ReplaceExtensions {
+		t.Extensions = map[string]Style{}
+		var only struct {
+			Extensions map[string]Style `toml:"extensions"`
+		}
+		if _, err := toml.Decode(string(data), &only); err != nil {
+			return nil, fmt.Errorf("invalid theme %s: %w", nameOrPath, err)
+		}
+		for ext, style := range only.Extensions {
+			t.Extensions[ext] = style
+		}
+	}
+	return t, nil
+}
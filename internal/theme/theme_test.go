@@ -0,0 +1,288 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+func TestParseStyle(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		expected Style
+		wantErr  bool
+	}{
+		{name: "empty", spec: "", expected: nil},
+		{name: "named color", spec: "green", expected: Style{color.FgGreen}},
+		{name: "bright with attribute", spec: "hi-red bold", expected: Style{color.FgHiRed, color.Bold}},
+		{name: "background", spec: "bg-blue", expected: Style{color.BgBlue}},
+		{name: "bright background", spec: "bg-hi-yellow", expected: Style{color.BgHiYellow}},
+		{name: "raw codes", spec: "38;5;208", expected: Style{38, 5, 208}},
+		{name: "hex", spec: "#ff79c6", expected: Style{38, 2, 255, 121, 198}},
+		{name: "comma separated", spec: "cyan,underline", expected: Style{color.FgCyan, color.Underline}},
+		{name: "unknown name", spec: "purple", wantErr: true},
+		{name: "bad hex", spec: "#ff", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseStyle(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseStyle(%q) expected error", tt.spec)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseStyle(%q) unexpected error: %v", tt.spec, err)
+			}
+			if len(result) != len(tt.expected) {
+				t.Fatalf("ParseStyle(%q) = %v, want %v", tt.spec, result, tt.expected)
+			}
+			for i := range result {
+				if result[i] != tt.expected[i] {
+					t.Errorf("ParseStyle(%q) = %v, want %v", tt.spec, result, tt.expected)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestLoadThemeFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mine.toml")
+	content := `
+border = "blue"
+
+[extensions]
+".sql" = "magenta bold"
+
+[age]
+years = "red"
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	th, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+
+	if !th.Border.Color().Equals(color.New(color.FgBlue)) {
+		t.Errorf("border was not overridden")
+	}
+	if !th.Extension(".sql").Color().Equals(color.New(color.FgMagenta, color.Bold)) {
+		t.Errorf("extension .sql was not added")
+	}
+	if !th.Extension(".go").Color().Equals(color.New(color.FgGreen)) {
+		t.Errorf("default extension .go was lost")
+	}
+	if !th.Header.Color().Equals(Default().Header.Color()) {
+		t.Errorf("unset header should keep the default")
+	}
+}
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		pattern string
+		style   Style
+		wantErr bool
+	}{
+		{name: "glob", spec: "*.sql=magenta bold", pattern: "*.sql", style: Style{color.FgMagenta, color.Bold}},
+		{name: "spaces around pattern", spec: " Makefile =yellow", pattern: "Makefile", style: Style{color.FgYellow}},
+		{name: "missing style separator", spec: "*.sql", wantErr: true},
+		{name: "empty pattern", spec: "=red", wantErr: true},
+		{name: "bad pattern", spec: "[a-=red", wantErr: true},
+		{name: "bad style", spec: "*.sql=purple", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRule(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseRule(%q) expected error", tt.spec)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRule(%q) unexpected error: %v", tt.spec, err)
+			}
+			if rule.Pattern != tt.pattern {
+				t.Errorf("ParseRule(%q) pattern = %q, want %q", tt.spec, rule.Pattern, tt.pattern)
+			}
+			if !rule.Style.Color().Equals(tt.style.Color()) {
+				t.Errorf("ParseRule(%q) style = %v, want %v", tt.spec, rule.Style, tt.style)
+			}
+		})
+	}
+}
+
+func TestMatchFirstRuleWins(t *testing.T) {
+	th := Default()
+	th.Rules = []Rule{
+		{Pattern: "schema.sql", Style: Style{color.FgRed}},
+		{Pattern: "*.sql", Style: Style{color.FgMagenta}},
+	}
+
+	if style, ok := th.Match("schema.sql"); !ok || !style.Color().Equals(color.New(color.FgRed)) {
+		t.Errorf("Match(schema.sql) = %v, %v; want red", style, ok)
+	}
+	if style, ok := th.Match("data.sql"); !ok || !style.Color().Equals(color.New(color.FgMagenta)) {
+		t.Errorf("Match(data.sql) = %v, %v; want magenta", style, ok)
+	}
+	if _, ok := th.Match("main.go"); ok {
+		t.Errorf("Match(main.go) should not match")
+	}
+}
+
+func TestLoadThresholds(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "thresholds.toml")
+	content := `
+[[age_thresholds]]
+after = "0s"
+style = "green"
+
+[[age_thresholds]]
+after = "90d"
+style = "red"
+
+[[age_thresholds]]
+after = "1w"
+style = "yellow"
+
+[[size_thresholds]]
+above = "100MB"
+style = "red bold"
+
+[[size_thresholds]]
+above = "1.5KB"
+style = "yellow"
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	th, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+
+	day := 24 * time.Hour
+	ageTests := []struct {
+		age      time.Duration
+		expected *color.Color
+	}{
+		{age: time.Hour, expected: color.New(color.FgGreen)},
+		{age: 8 * day, expected: color.New(color.FgYellow)},
+		{age: 90 * day, expected: color.New(color.FgRed)},
+		{age: 400 * day, expected: color.New(color.FgRed)},
+	}
+	for _, tt := range ageTests {
+		style, ok := th.AgeStyle(tt.age)
+		if !ok || !style.Color().Equals(tt.expected) {
+			t.Errorf("AgeStyle(%v) = %v, %v", tt.age, style, ok)
+		}
+	}
+
+	if _, ok := th.SizeStyle(1000); ok {
+		t.Errorf("SizeStyle(1000) should not match any threshold")
+	}
+	if style, ok := th.SizeStyle(2048); !ok || !style.Color().Equals(color.New(color.FgYellow)) {
+		t.Errorf("SizeStyle(2048) = %v, %v; want yellow", style, ok)
+	}
+	if style, ok := th.SizeStyle(200 << 20); !ok || !style.Color().Equals(color.New(color.FgRed, color.Bold)) {
+		t.Errorf("SizeStyle(200MB) = %v, %v; want red bold", style, ok)
+	}
+}
+
+func TestLoadInvalidThreshold(t *testing.T) {
+	for _, content := range []string{
+		"[[age_thresholds]]\nafter = \"90 days\"\n",
+		"[[size_thresholds]]\nabove = \"10XB\"\n",
+	} {
+		path := filepath.Join(t.TempDir(), "bad.toml")
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil {
+			t.Errorf("Load(%q) expected error", content)
+		}
+	}
+}
+
+func TestLoadPresets(t *testing.T) {
+	for _, name := range Presets() {
+		if _, err := Load(name); err != nil {
+			t.Errorf("Load(%q) unexpected error: %v", name, err)
+		}
+	}
+
+	if _, err := Load("does-not-exist"); err == nil {
+		t.Errorf("expected error for unknown theme")
+	}
+}
+
+func TestConvertDircolors(t *testing.T) {
+	input := `# Configuration file for dircolors
+TERM xterm*
+COLOR tty
+NORMAL 00
+DIR 01;34 # directories
+LINK 01;36
+EXEC 01;32
+FIFO 40;33
+.tar 01;31
+*.JPG 01;35
+*README 01;33
+*.tar.gz 01;31
+*# 00;90
+`
+	var out strings.Builder
+	if err := ConvertDircolors(strings.NewReader(input), &out); err != nil {
+		t.Fatalf("ConvertDircolors() unexpected error: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "converted.toml")
+	if err := os.WriteFile(path, []byte(out.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	th, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() of converted theme failed: %v\n%s", err, out.String())
+	}
+
+	if !th.Names.Directory.Color().Equals(color.New(color.Bold, color.FgBlue)) {
+		t.Errorf("directory style = %v", th.Names.Directory)
+	}
+	if !th.Extension(".jpg").Color().Equals(color.New(color.Bold, color.FgMagenta)) {
+		t.Errorf(".jpg style = %v", th.Extension(".jpg"))
+	}
+	if _, ok := th.Match("README"); !ok {
+		t.Errorf("README rule missing")
+	}
+	if style, ok := th.Match("x.tar.gz"); !ok || !style.Color().Equals(color.New(color.Bold, color.FgRed)) {
+		t.Errorf("*.tar.gz rule = %v, %v", style, ok)
+	}
+	if th.Gradient {
+		t.Errorf("converted theme should disable gradients")
+	}
+	if _, ok := th.Extensions[".go"]; ok {
+		t.Errorf("converted theme should not keep default extension colors")
+	}
+	if !strings.Contains(out.String(), "# FIFO 40;33") {
+		t.Errorf("unsupported entries should be kept as comments:\n%s", out.String())
+	}
+
+	if err := ConvertDircolors(strings.NewReader("DIR purple\n"), &out); err == nil {
+		t.Errorf("expected error for invalid style")
+	}
+}
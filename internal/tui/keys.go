@@ -0,0 +1,76 @@
+package tui
+
+type key int
+
+const (
+	keyNone key = iota
+	keyUp
+	keyDown
+	keyPageUp
+	keyPageDown
+	keyHome
+	keyEnd
+	keyOpen
+	keyParent
+	keyMark
+	keyMarkAll
+	keyConfirm
+	keyCancel
+	keyInterrupt
+	keyHelp
+	keyExport
+)
+
+// bindings describe the keys for the help overlay, in the order shown.
+var bindings = []struct{ keys, action string }{
+	{"↑ ↓  k j", "move"},
+	{"PgUp PgDn", "move a page"},
+	{"Home End  g G", "first or last entry"},
+	{"→  l", "open directory"},
+	{"←  h  Backspace", "go up"},
+	{"Space", "mark or unmark"},
+	{"a", "mark or unmark all"},
+	{"e", "export the view to a file"},
+	{"Enter", "confirm"},
+	{"q  Esc  Ctrl-C", "cancel"},
+	{"?", "show or hide this help"},
+}
+
+// parseKey maps one read from the terminal to a key. Escape sequences
+// arrive in a single read on every terminal worth supporting, so a lone
+// ESC byte is the Escape key itself.
+func parseKey(input []byte) key {
+	switch string(input) {
+	case "\x1b[A", "\x1bOA", "k":
+		return keyUp
+	case "\x1b[B", "\x1bOB", "j":
+		return keyDown
+	case "\x1b[5~":
+		return keyPageUp
+	case "\x1b[6~":
+		return keyPageDown
+	case "\x1b[H", "\x1bOH", "\x1b[1~", "g":
+		return keyHome
+	case "\x1b[F", "\x1bOF", "\x1b[4~", "G":
+		return keyEnd
+	case "\x1b[C", "\x1bOC", "l":
+		return keyOpen
+	case "\x1b[D", "\x1bOD", "h", "\x7f", "\b":
+		return keyParent
+	case " ":
+		return keyMark
+	case "a":
+		return keyMarkAll
+	case "\r", "\n":
+		return keyConfirm
+	case "\x1b", "q":
+		return keyCancel
+	case "\x03":
+		return keyInterrupt
+	case "?":
+		return keyHelp
+	case "e":
+		return keyExport
+	}
+	return keyNone
+}
@@ -0,0 +1,542 @@
+// Package tui implements lu's interactive mode: a full-screen file list
+// drawn on the terminal that lets the user browse directories and mark
+// entries.
+package tui
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"github.com/ipanardian/lu-hut/internal/model"
+	"golang.org/x/term"
+	"golang.org/x/text/width"
+)
+
+// ErrCancelled is returned when the user leaves the picker without
+// confirming a selection.
+var ErrCancelled = errors.New("selection cancelled")
+
+// Loader lists one directory through the same filters and sorting as a
+// normal listing. A large directory can be handed over a page at a time,
+// unsorted, through page while the rest is read; the returned entries then
+// replace those pages. Loading stops early when ctx is cancelled.
+type Loader func(ctx context.Context, dir string, page func([]model.FileEntry)) ([]model.FileEntry, error)
+
+// loadEvent carries a page, or with done the final listing, from the
+// background load of the directory numbered gen.
+type loadEvent struct {
+	gen   int
+	files []model.FileEntry
+	done  bool
+	err   error
+}
+
+// Namer styles a name for display in at most maxWidth columns.
+type Namer func(file model.FileEntry, maxWidth int) string
+
+// Exporter writes the entries of the current view to the file at path, in
+// a format chosen from its extension.
+type Exporter func(path string, files []model.FileEntry) error
+
+// defaultExport is the file name the export prompt starts with.
+const defaultExport = "lu-view.txt"
+
+// reservedLines are the header and footer rows around the list.
+const reservedLines = 3
+
+// Picker is the state of one interactive selection.
+type Picker struct {
+	load   Loader
+	name   Namer
+	dir    string
+	files  []model.FileEntry
+	cursor int
+	offset int
+	err    error
+	log    *SessionLog
+
+	// Directories load in the background: events delivers their pages,
+	// gen tells the current load from abandoned ones, and focus is the
+	// path to put the cursor on once it shows up.
+	events  chan loadEvent
+	gen     int
+	cancel  context.CancelFunc
+	loading bool
+	focus   string
+
+	// help shows the key bindings and settings instead of the list.
+	help     bool
+	settings []Setting
+
+	// export writes the view to a file. While prompt is set the footer
+	// reads the file name into input; replace is set once the user was
+	// told the file exists, so a second Enter overwrites it. status
+	// reports how it went.
+	export  Exporter
+	prompt  bool
+	replace bool
+	input   []rune
+	status  string
+
+	// marked keeps selection order so paths print in the order chosen.
+	marked []string
+	isMark map[string]bool
+}
+
+// Setting is one line of the listing options shown in the help overlay,
+// such as the sort order or the active filters.
+type Setting struct {
+	Name  string
+	Value string
+}
+
+// NewPicker starts a picker in dir.
+func NewPicker(dir string, load Loader, name Namer) *Picker {
+	return &Picker{load: load, name: name, dir: dir, isMark: make(map[string]bool), events: make(chan loadEvent)}
+}
+
+// SetSettings sets the listing options the help overlay reports.
+func (p *Picker) SetSettings(settings []Setting) {
+	p.settings = settings
+}
+
+// SetExporter enables the export key, which writes the current view
+// through export.
+func (p *Picker) SetExporter(export Exporter) {
+	p.export = export
+}
+
+// SetSessionLog records the session's events to log.
+func (p *Picker) SetSessionLog(log *SessionLog) {
+	p.log = log
+}
+
+// Run takes over the terminal until the user confirms or cancels, and
+// returns the marked paths, or the entry under the cursor when nothing was
+// marked.
+func (p *Picker) Run() ([]string, error) {
+	in, out, err := openTTY()
+	if err != nil {
+		return nil, fmt.Errorf("interactive mode needs a terminal: %w", err)
+	}
+	defer in.Close()
+	if out != in {
+		defer out.Close()
+	}
+
+	state, err := term.MakeRaw(descriptor(in))
+	if err != nil {
+		return nil, fmt.Errorf("interactive mode needs a terminal: %w", err)
+	}
+	defer term.Restore(descriptor(in), state)
+
+	w := bufio.NewWriter(out)
+	// Alternate screen and hidden cursor, undone in reverse on the way out.
+	fmt.Fprint(w, "\x1b[?1049h\x1b[?25l")
+	defer func() {
+		fmt.Fprint(w, "\x1b[?25h\x1b[?1049l")
+		w.Flush()
+	}()
+
+	// Keys are read on their own goroutine so pages of a large directory
+	// can be drawn while the picker waits for the next key. On the way out
+	// its pending read is interrupted and waited for, so it neither eats
+	// the next key typed at the shell nor competes with a later picker.
+	type input struct {
+		data []byte
+		err  error
+	}
+	keys := make(chan input)
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	defer func() {
+		close(stop)
+		if interruptRead(in) {
+			<-stopped
+		}
+	}()
+	go func() {
+		defer close(stopped)
+		for {
+			buf := make([]byte, 16)
+			n, err := in.Read(buf)
+			select {
+			case keys <- input{buf[:n], err}:
+			case <-stop:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	p.log.start(p.dir)
+	p.enter(p.dir, "")
+	defer p.stopLoad()
+	for {
+		cols, rows, err := term.GetSize(descriptor(out))
+		if err != nil || rows <= reservedLines {
+			cols, rows = 80, 24
+		}
+		rows -= reservedLines
+		p.draw(w, cols, rows)
+		if err := w.Flush(); err != nil {
+			return nil, err
+		}
+
+		select {
+		case ev := <-p.events:
+			p.receive(ev)
+		case k := <-keys:
+			if k.err != nil {
+				return nil, k.err
+			}
+			if p.prompt {
+				p.edit(k.data)
+				continue
+			}
+			if done, err := p.handle(parseKey(k.data), rows); done {
+				return p.marked, err
+			}
+		}
+	}
+}
+
+// descriptor returns f's file descriptor. Unlike File.Fd it leaves the file
+// in non-blocking mode, which interruptRead needs on Unix.
+func descriptor(f *os.File) int {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return int(f.Fd())
+	}
+	fd := -1
+	rc.Control(func(d uintptr) {
+		fd = int(d)
+	})
+	return fd
+}
+
+// handle applies one key press and reports whether the picker is finished.
+func (p *Picker) handle(k key, page int) (bool, error) {
+	// While the overlay is up, any key but Ctrl-C only closes it, so a
+	// key pressed to dismiss it never acts on the list underneath.
+	if p.help && k != keyInterrupt {
+		p.help = false
+		return false, nil
+	}
+	p.status = ""
+
+	switch k {
+	case keyHelp:
+		p.help = true
+	case keyExport:
+		// A directory still loading is only partly read and not yet
+		// sorted, which is not what the export should hold.
+		if p.export != nil && p.loading {
+			p.status = color.YellowString("still loading, export once the listing is complete")
+		} else if p.export != nil {
+			p.prompt, p.replace, p.input = true, false, []rune(defaultExport)
+		}
+	case keyUp:
+		p.move(-1)
+	case keyDown:
+		p.move(1)
+	case keyPageUp:
+		p.move(-page)
+	case keyPageDown:
+		p.move(page)
+	case keyHome:
+		p.move(-len(p.files))
+	case keyEnd:
+		p.move(len(p.files))
+	case keyOpen:
+		if file, ok := p.current(); ok && file.IsDir {
+			p.enter(file.Path, "")
+		}
+	case keyParent:
+		if parent := filepath.Dir(p.dir); parent != p.dir {
+			p.enter(parent, p.dir)
+		}
+	case keyMark:
+		if file, ok := p.current(); ok {
+			p.toggle(file.Path)
+			p.move(1)
+		}
+	case keyMarkAll:
+		for _, file := range p.files {
+			p.toggle(file.Path)
+		}
+	case keyConfirm:
+		if len(p.marked) == 0 {
+			if file, ok := p.current(); ok {
+				p.toggle(file.Path)
+			}
+		}
+		p.log.record("confirm", "", p.marked)
+		return true, nil
+	case keyCancel, keyInterrupt:
+		p.marked = nil
+		p.log.record("cancel", "", nil)
+		return true, ErrCancelled
+	}
+	return false, nil
+}
+
+// edit applies one read from the terminal to the export prompt: Enter
+// writes the file, asking first when it already exists, Escape or Ctrl-C
+// closes the prompt, Backspace deletes and printable text is typed in.
+func (p *Picker) edit(data []byte) {
+	switch string(data) {
+	case "\r", "\n":
+		path := string(p.input)
+		if _, err := os.Lstat(path); path != "" && err == nil && !p.replace {
+			p.replace = true
+			return
+		}
+		p.prompt, p.replace = false, false
+		if path == "" {
+			return
+		}
+		if err := p.export(path, p.files); err != nil {
+			p.status = color.RedString("export failed: %v", err)
+			return
+		}
+		p.log.record("export", path, nil)
+		p.status = color.GreenString("wrote %d entries to %s", len(p.files), path)
+	case "\x1b", "\x03":
+		p.prompt, p.replace = false, false
+	case "\x7f", "\b":
+		p.replace = false
+		if len(p.input) > 0 {
+			p.input = p.input[:len(p.input)-1]
+		}
+	default:
+		p.replace = false
+		for len(data) > 0 {
+			r, size := utf8.DecodeRune(data)
+			data = data[size:]
+			if r != utf8.RuneError && unicode.IsPrint(r) {
+				p.input = append(p.input, r)
+			}
+		}
+	}
+}
+
+// enter starts loading dir and places the cursor on the entry at focus, if
+// given, so going up lands on the directory that was just left. It waits
+// for the first page, which for all but huge directories is the complete,
+// sorted listing; the rest arrives through receive.
+func (p *Picker) enter(dir, focus string) {
+	p.stopLoad()
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	p.gen++
+	gen := p.gen
+
+	p.dir, p.files, p.err, p.loading, p.focus = dir, nil, nil, true, focus
+	p.log.record("enter", dir, nil)
+	p.cursor, p.offset = 0, 0
+
+	send := func(ev loadEvent) {
+		select {
+		case p.events <- ev:
+		case <-ctx.Done():
+		}
+	}
+	go func() {
+		files, err := p.load(ctx, dir, func(page []model.FileEntry) {
+			send(loadEvent{gen: gen, files: page})
+		})
+		send(loadEvent{gen: gen, files: files, done: true, err: err})
+	}()
+
+	for p.loading && len(p.files) == 0 && p.err == nil {
+		p.receive(<-p.events)
+	}
+}
+
+// receive applies one event of the background load. Pages are appended as
+// they come; the final listing replaces them and keeps the cursor on the
+// entry it was on.
+func (p *Picker) receive(ev loadEvent) {
+	if ev.gen != p.gen {
+		return
+	}
+	if !ev.done {
+		p.files = append(p.files, ev.files...)
+		p.seekFocus()
+		return
+	}
+
+	if p.focus == "" {
+		if file, ok := p.current(); ok {
+			p.focus = file.Path
+		}
+	}
+	p.files, p.err, p.loading = ev.files, ev.err, false
+	p.cursor = 0
+	p.seekFocus()
+	p.focus = ""
+}
+
+// seekFocus moves the cursor to the focus entry once it has been loaded.
+func (p *Picker) seekFocus() {
+	if p.focus == "" {
+		return
+	}
+	for i, file := range p.files {
+		if file.Path == p.focus {
+			p.cursor = i
+			return
+		}
+	}
+}
+
+// stopLoad abandons the directory load in progress, if any.
+func (p *Picker) stopLoad() {
+	if p.cancel != nil {
+		p.cancel()
+		p.cancel = nil
+	}
+}
+
+func (p *Picker) move(delta int) {
+	p.cursor = max(0, min(len(p.files)-1, p.cursor+delta))
+	p.focus = ""
+}
+
+func (p *Picker) current() (model.FileEntry, bool) {
+	if p.cursor < 0 || p.cursor >= len(p.files) {
+		return model.FileEntry{}, false
+	}
+	return p.files[p.cursor], true
+}
+
+func (p *Picker) toggle(path string) {
+	if !p.isMark[path] {
+		p.isMark[path] = true
+		p.marked = append(p.marked, path)
+		p.log.record("mark", path, nil)
+		return
+	}
+	delete(p.isMark, path)
+	p.log.record("unmark", path, nil)
+	for i, marked := range p.marked {
+		if marked == path {
+			p.marked = append(p.marked[:i], p.marked[i+1:]...)
+			break
+		}
+	}
+}
+
+func (p *Picker) draw(w *bufio.Writer, cols, rows int) {
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+rows {
+		p.offset = p.cursor - rows + 1
+	}
+
+	fmt.Fprint(w, "\x1b[H\x1b[2J")
+	header := color.New(color.FgCyan, color.Bold).Sprint(p.dir)
+	if len(p.marked) > 0 {
+		header += color.New(color.FgYellow).Sprintf("  (%d selected)", len(p.marked))
+	}
+	if p.loading {
+		header += color.HiBlackString("  loading… %d entries so far", len(p.files))
+	}
+	fmt.Fprint(w, header, "\r\n")
+
+	if p.help {
+		p.drawHelp(w, cols, rows)
+		return
+	}
+
+	switch {
+	case p.err != nil:
+		fmt.Fprint(w, color.RedString("  %v", p.err), "\r\n")
+	case len(p.files) == 0 && !p.loading:
+		fmt.Fprint(w, color.HiBlackString("  (empty)"), "\r\n")
+	}
+
+	for i := p.offset; i < len(p.files) && i < p.offset+rows; i++ {
+		file := p.files[i]
+		mark := "[ ] "
+		if p.isMark[file.Path] {
+			mark = color.GreenString("[x] ")
+		}
+		name := p.name(file, max(1, cols-len(mark)-2))
+		if file.IsDir {
+			name += "/"
+		}
+		if i == p.cursor {
+			fmt.Fprint(w, "\x1b[7m>\x1b[27m ", mark, name, "\r\n")
+		} else {
+			fmt.Fprint(w, "  ", mark, name, "\r\n")
+		}
+	}
+
+	fmt.Fprintf(w, "\x1b[%d;1H", rows+reservedLines)
+	switch {
+	case p.prompt && p.replace:
+		fmt.Fprint(w, fit("export to: "+string(p.input)+"█  "+color.YellowString("file exists · enter overwrite · esc cancel"), cols))
+	case p.prompt:
+		fmt.Fprint(w, fit("export to: "+string(p.input)+"█  "+color.HiBlackString("enter write · esc cancel · .json for JSON"), cols))
+	case p.status != "":
+		fmt.Fprint(w, fit(p.status, cols))
+	default:
+		fmt.Fprint(w, color.HiBlackString(fit("space mark · a mark all · enter confirm · → open · ← up · q cancel · ? help", cols)))
+	}
+}
+
+// drawHelp fills the list area with the key bindings and the options the
+// listing was loaded with.
+func (p *Picker) drawHelp(w *bufio.Writer, cols, rows int) {
+	var lines []string
+	title := color.New(color.FgWhite, color.Bold)
+	lines = append(lines, title.Sprint("  Keys"))
+	for _, b := range bindings {
+		lines = append(lines, "  "+color.CyanString("%-18s", b.keys)+" "+b.action)
+	}
+	if len(p.settings) > 0 {
+		lines = append(lines, "", title.Sprint("  Listing"))
+		for _, s := range p.settings {
+			lines = append(lines, "  "+color.CyanString("%-18s", s.Name)+" "+s.Value)
+		}
+	}
+
+	for i, line := range lines {
+		if i >= rows {
+			break
+		}
+		fmt.Fprint(w, line, "\r\n")
+	}
+
+	fmt.Fprintf(w, "\x1b[%d;1H", rows+reservedLines)
+	fmt.Fprint(w, color.HiBlackString(fit("press any key to return", cols)))
+}
+
+// fit cuts s to cols display columns.
+func fit(s string, cols int) string {
+	used := 0
+	for i, r := range s {
+		w := 1
+		switch width.LookupRune(r).Kind() {
+		case width.EastAsianWide, width.EastAsianFullwidth:
+			w = 2
+		}
+		if used+w > cols {
+			return s[:i]
+		}
+		used += w
+	}
+	return s
+}
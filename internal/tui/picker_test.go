@@ -0,0 +1,284 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ipanardian/lu-hut/internal/model"
+)
+
+func fakeLoader(tree map[string][]string) Loader {
+	return func(_ context.Context, dir string, _ func([]model.FileEntry)) ([]model.FileEntry, error) {
+		var files []model.FileEntry
+		for _, name := range tree[dir] {
+			_, isDir := tree[filepath.Join(dir, name)]
+			files = append(files, model.FileEntry{Name: name, Path: filepath.Join(dir, name), IsDir: isDir})
+		}
+		return files, nil
+	}
+}
+
+func TestPickerSelection(t *testing.T) {
+	root := filepath.FromSlash("/p")
+	load := fakeLoader(map[string][]string{
+		root:                       {"a.txt", "src", "z.txt"},
+		filepath.Join(root, "src"): {"main.go"},
+	})
+	p := NewPicker(root, load, nil)
+	p.enter(root, "")
+
+	steps := []key{keyDown, keyOpen, keyMark, keyParent, keyUp, keyMark}
+	for _, k := range steps {
+		if done, _ := p.handle(k, 10); done {
+			t.Fatalf("picker finished early on key %v", k)
+		}
+	}
+	if p.dir != root || p.cursor != 1 {
+		t.Errorf("after going up: dir %q cursor %d, want %q cursor 1", p.dir, p.cursor, root)
+	}
+
+	done, err := p.handle(keyConfirm, 10)
+	want := []string{filepath.Join(root, "src", "main.go"), filepath.Join(root, "a.txt")}
+	if !done || err != nil || !reflect.DeepEqual(p.marked, want) {
+		t.Errorf("confirm = %v, %v, %v; want %v", done, err, p.marked, want)
+	}
+}
+
+func TestPickerConfirmWithoutMarks(t *testing.T) {
+	root := filepath.FromSlash("/p")
+	p := NewPicker(root, fakeLoader(map[string][]string{root: {"a", "b"}}), nil)
+	p.enter(root, "")
+	p.handle(keyEnd, 10)
+
+	if _, err := p.handle(keyConfirm, 10); err != nil || !reflect.DeepEqual(p.marked, []string{filepath.Join(root, "b")}) {
+		t.Errorf("confirm = %v, %v; want the entry under the cursor", p.marked, err)
+	}
+}
+
+func TestPickerCancel(t *testing.T) {
+	root := filepath.FromSlash("/p")
+	p := NewPicker(root, fakeLoader(map[string][]string{root: {"a"}}), nil)
+	p.enter(root, "")
+	p.handle(keyMark, 10)
+
+	done, err := p.handle(keyCancel, 10)
+	if !done || !errors.Is(err, ErrCancelled) || len(p.marked) != 0 {
+		t.Errorf("cancel = %v, %v, %v", done, err, p.marked)
+	}
+}
+
+func TestPickerSessionLog(t *testing.T) {
+	root := filepath.FromSlash("/p")
+	src := filepath.Join(root, "src")
+	p := NewPicker(root, fakeLoader(map[string][]string{root: {"src", "a"}, src: {"main.go"}}), nil)
+	var buf bytes.Buffer
+	p.SetSessionLog(NewSessionLog(&buf))
+
+	p.enter(root, "")
+	for _, k := range []key{keyOpen, keyMark, keyParent, keyConfirm} {
+		p.handle(k, 10)
+	}
+
+	var actions, paths []string
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var event sessionEvent
+		if err := dec.Decode(&event); err != nil {
+			t.Fatal(err)
+		}
+		actions = append(actions, event.Action)
+		paths = append(paths, event.Path+fmt.Sprint(event.Paths))
+	}
+
+	wantActions := []string{"enter", "enter", "mark", "enter", "confirm"}
+	main := filepath.Join(src, "main.go")
+	wantPaths := []string{root + "[]", src + "[]", main + "[]", root + "[]", "[" + main + "]"}
+	if !reflect.DeepEqual(actions, wantActions) || !reflect.DeepEqual(paths, wantPaths) {
+		t.Errorf("session log = %v %v, want %v %v", actions, paths, wantActions, wantPaths)
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected key
+	}{
+		{"\x1b[A", keyUp},
+		{"j", keyDown},
+		{"\x1b[6~", keyPageDown},
+		{"\x1b[C", keyOpen},
+		{"\x7f", keyParent},
+		{" ", keyMark},
+		{"\r", keyConfirm},
+		{"\x1b", keyCancel},
+		{"\x03", keyInterrupt},
+		{"?", keyHelp},
+		{"e", keyExport},
+		{"x", keyNone},
+	}
+
+	for _, tt := range tests {
+		if got := parseKey([]byte(tt.input)); got != tt.expected {
+			t.Errorf("parseKey(%q) = %v, want %v", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestPickerHelpOverlay(t *testing.T) {
+	root := filepath.FromSlash("/p")
+	p := NewPicker(root, fakeLoader(map[string][]string{root: {"a", "b"}}), nil)
+	p.enter(root, "")
+
+	p.handle(keyHelp, 10)
+	if !p.help {
+		t.Fatal("? did not open the help overlay")
+	}
+	// The key that closes the overlay must not also act on the list.
+	if done, _ := p.handle(keyConfirm, 10); done || p.help || p.cursor != 0 {
+		t.Errorf("closing the overlay: done %v, help %v, cursor %d", done, p.help, p.cursor)
+	}
+
+	p.handle(keyHelp, 10)
+	if done, err := p.handle(keyInterrupt, 10); !done || !errors.Is(err, ErrCancelled) {
+		t.Errorf("ctrl-c in the overlay = %v, %v; want the picker cancelled", done, err)
+	}
+}
+
+func TestPickerExport(t *testing.T) {
+	root := filepath.FromSlash("/p")
+	p := NewPicker(root, fakeLoader(map[string][]string{root: {"a", "b"}}), nil)
+	p.enter(root, "")
+
+	var gotPath string
+	var gotFiles []model.FileEntry
+	p.SetExporter(func(path string, files []model.FileEntry) error {
+		gotPath, gotFiles = path, files
+		return nil
+	})
+
+	p.handle(keyExport, 10)
+	if !p.prompt || string(p.input) != defaultExport {
+		t.Fatalf("export key: prompt %v, input %q", p.prompt, string(p.input))
+	}
+	for range len(".txt") {
+		p.edit([]byte{0x7f})
+	}
+	p.edit([]byte(".json"))
+	p.edit([]byte("\r"))
+
+	if p.prompt {
+		t.Error("prompt still open after enter")
+	}
+	if gotPath != "lu-view.json" || len(gotFiles) != 2 {
+		t.Errorf("exported %d entries to %q, want 2 to lu-view.json", len(gotFiles), gotPath)
+	}
+
+	// Escape closes the prompt without writing anything.
+	gotPath = ""
+	p.handle(keyExport, 10)
+	p.edit([]byte{0x1b})
+	if p.prompt || gotPath != "" {
+		t.Errorf("escape: prompt %v, exported to %q", p.prompt, gotPath)
+	}
+}
+
+func TestPickerExportExisting(t *testing.T) {
+	root := filepath.FromSlash("/p")
+	p := NewPicker(root, fakeLoader(map[string][]string{root: {"a"}}), nil)
+	p.enter(root, "")
+
+	exported := 0
+	p.SetExporter(func(string, []model.FileEntry) error {
+		exported++
+		return nil
+	})
+	existing := filepath.Join(t.TempDir(), "view.txt")
+	if err := os.WriteFile(existing, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first Enter only warns that the file exists; the second writes.
+	p.handle(keyExport, 10)
+	p.input = []rune(existing)
+	p.edit([]byte("\r"))
+	if !p.prompt || !p.replace || exported != 0 {
+		t.Fatalf("first enter: prompt %v, replace %v, exported %d", p.prompt, p.replace, exported)
+	}
+	p.edit([]byte("\r"))
+	if p.prompt || exported != 1 {
+		t.Errorf("second enter: prompt %v, exported %d", p.prompt, exported)
+	}
+
+	// Editing the name takes the warning back.
+	p.handle(keyExport, 10)
+	p.input = []rune(existing)
+	p.edit([]byte("\r"))
+	p.edit([]byte("x"))
+	if p.replace {
+		t.Error("typing after the warning kept the overwrite armed")
+	}
+}
+
+func TestPickerExportWhileLoading(t *testing.T) {
+	root := filepath.FromSlash("/p")
+	release := make(chan struct{})
+	load := func(_ context.Context, dir string, page func([]model.FileEntry)) ([]model.FileEntry, error) {
+		page([]model.FileEntry{{Name: "a", Path: filepath.Join(root, "a")}})
+		<-release
+		return []model.FileEntry{{Name: "a", Path: filepath.Join(root, "a")}}, nil
+	}
+	p := NewPicker(root, load, nil)
+	p.SetExporter(func(string, []model.FileEntry) error { return nil })
+	p.enter(root, "")
+
+	p.handle(keyExport, 10)
+	if p.prompt {
+		t.Error("export prompt opened while the directory was loading")
+	}
+	close(release)
+	for p.loading {
+		p.receive(<-p.events)
+	}
+	p.handle(keyExport, 10)
+	if !p.prompt {
+		t.Error("export prompt did not open after loading finished")
+	}
+}
+
+func TestPickerPagedLoad(t *testing.T) {
+	root := filepath.FromSlash("/p")
+	entry := func(name string) model.FileEntry {
+		return model.FileEntry{Name: name, Path: filepath.Join(root, name)}
+	}
+	release := make(chan struct{})
+	load := func(_ context.Context, dir string, page func([]model.FileEntry)) ([]model.FileEntry, error) {
+		page([]model.FileEntry{entry("c"), entry("a")})
+		<-release
+		page([]model.FileEntry{entry("b")})
+		return []model.FileEntry{entry("a"), entry("b"), entry("c")}, nil
+	}
+
+	p := NewPicker(root, load, nil)
+	p.enter(root, "")
+	if !p.loading || len(p.files) != 2 {
+		t.Fatalf("after the first page: loading %v, %d entries", p.loading, len(p.files))
+	}
+
+	// The cursor stays on the entry it was on when the sorted listing
+	// replaces the pages.
+	p.handle(keyDown, 10)
+	close(release)
+	for p.loading {
+		p.receive(<-p.events)
+	}
+	if file, _ := p.current(); len(p.files) != 3 || file.Name != "a" {
+		t.Errorf("after loading: %d entries, cursor on %q, want 3 entries and a", len(p.files), file.Name)
+	}
+}
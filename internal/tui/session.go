@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"os/user"
+	"time"
+)
+
+// SessionLog records a picker session as JSON Lines: one object per event,
+// written as it happens so the trail survives a killed terminal. It is
+// meant for audits such as incident response, where what was looked at
+// matters as much as what was picked.
+type SessionLog struct {
+	enc *json.Encoder
+}
+
+// sessionEvent is one line of the log. Start events also say who ran lu
+// and where; confirm events carry the paths that were printed.
+type sessionEvent struct {
+	Time   time.Time `json:"time"`
+	Action string    `json:"action"`
+	Path   string    `json:"path,omitempty"`
+	Paths  []string  `json:"paths,omitempty"`
+	User   string    `json:"user,omitempty"`
+	Host   string    `json:"host,omitempty"`
+	PID    int       `json:"pid,omitempty"`
+}
+
+// NewSessionLog returns a log writing to w. A nil *SessionLog records
+// nothing, so the picker can call it unconditionally.
+func NewSessionLog(w io.Writer) *SessionLog {
+	return &SessionLog{enc: json.NewEncoder(w)}
+}
+
+func (l *SessionLog) start(root string) {
+	if l == nil {
+		return
+	}
+	event := sessionEvent{Action: "start", Path: root, PID: os.Getpid()}
+	if u, err := user.Current(); err == nil {
+		event.User = u.Username
+	}
+	event.Host, _ = os.Hostname()
+	l.write(event)
+}
+
+func (l *SessionLog) record(action, path string, paths []string) {
+	if l == nil {
+		return
+	}
+	l.write(sessionEvent{Action: action, Path: path, Paths: paths})
+}
+
+// write stamps and encodes one event. Write errors are ignored: a full
+// disk should not take the picker down with it.
+func (l *SessionLog) write(event sessionEvent) {
+	event.Time = time.Now()
+	_ = l.enc.Encode(event)
+}
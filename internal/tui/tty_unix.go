@@ -0,0 +1,24 @@
+//go:build !windows
+
+package tui
+
+import (
+	"os"
+	"time"
+)
+
+// openTTY opens the controlling terminal, since stdout is usually captured
+// when lu runs as a picker for another command.
+func openTTY() (in, out *os.File, err error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tty, tty, nil
+}
+
+// interruptRead makes a Read blocked on in return, and reports whether it
+// could. That needs in to be served by the runtime poller, as /dev/tty is.
+func interruptRead(in *os.File) bool {
+	return in.SetReadDeadline(time.Now()) == nil
+}
@@ -0,0 +1,42 @@
+//go:build !windows
+
+package tui
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestInterruptRead(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	// Looking up the descriptor, as Run does for raw mode, must not take
+	// away the deadline that interrupts the read.
+	descriptor(r)
+	done := make(chan error, 1)
+	go func() {
+		_, err := r.Read(make([]byte, 1))
+		done <- err
+	}()
+	// Let the reader block in the kernel, where only a non-blocking
+	// descriptor can be woken.
+	time.Sleep(50 * time.Millisecond)
+	if !interruptRead(r) {
+		t.Fatal("interruptRead could not set a deadline")
+	}
+	select {
+	case err := <-done:
+		if !errors.Is(err, os.ErrDeadlineExceeded) {
+			t.Errorf("read returned %v, want a deadline error", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("read still blocked after interruptRead")
+	}
+}
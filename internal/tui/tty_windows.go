@@ -0,0 +1,28 @@
+package tui
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// openTTY opens the console directly, since stdout is usually captured when
+// lu runs as a picker for another command.
+func openTTY() (in, out *os.File, err error) {
+	in, err = os.OpenFile("CONIN$", os.O_RDWR, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	out, err = os.OpenFile("CONOUT$", os.O_RDWR, 0)
+	if err != nil {
+		in.Close()
+		return nil, nil, err
+	}
+	return in, out, nil
+}
+
+// interruptRead cancels a ReadFile blocked on the console input, and
+// reports whether it could.
+func interruptRead(in *os.File) bool {
+	return windows.CancelIoEx(windows.Handle(in.Fd()), nil) == nil
+}
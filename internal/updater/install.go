@@ -0,0 +1,135 @@
+package updater
+
+import (
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// InstallMethod is how the running lu binary was installed, which decides
+// who is responsible for updating it.
+type InstallMethod string
+
+const (
+	// SelfManaged is a release binary that lu update replaces itself.
+	SelfManaged InstallMethod = "self-managed"
+	Homebrew    InstallMethod = "Homebrew"
+	Scoop       InstallMethod = "Scoop"
+	GoInstall   InstallMethod = "go install"
+)
+
+// modulePath is the package go install builds lu from.
+const modulePath = "github.com/ipanardian/lu-hut/cmd/lu"
+
+// DetectInstall works out how the running binary was installed from where
+// it lives and the build information Go embedded in it.
+func DetectInstall() InstallMethod {
+	execPath, err := os.Executable()
+	if err != nil {
+		return SelfManaged
+	}
+	if resolved, err := filepath.EvalSymlinks(execPath); err == nil {
+		execPath = resolved
+	}
+	info, _ := debug.ReadBuildInfo()
+	return detectInstall(execPath, info, goBinDirs())
+}
+
+func detectInstall(execPath string, info *debug.BuildInfo, goBins []string) InstallMethod {
+	slashed := filepath.ToSlash(execPath)
+	lower := strings.ToLower(slashed)
+	switch {
+	case strings.Contains(slashed, "/Cellar/") || strings.Contains(lower, "homebrew") || strings.Contains(lower, "linuxbrew"):
+		return Homebrew
+	case strings.Contains(lower, "/scoop/apps/"):
+		return Scoop
+	}
+	// A module checksum is only recorded when go install downloaded lu as
+	// a module (go install ...@version), never for a build from a checkout.
+	if info != nil && info.Main.Sum != "" {
+		return GoInstall
+	}
+	dir := filepath.Dir(execPath)
+	for _, bin := range goBins {
+		if bin != "" && sameDir(dir, bin) {
+			return GoInstall
+		}
+	}
+	return SelfManaged
+}
+
+// goBinDirs returns where go install puts binaries: $GOBIN, or the bin
+// directory of each $GOPATH entry, which defaults to ~/go.
+func goBinDirs() []string {
+	if gobin := os.Getenv("GOBIN"); gobin != "" {
+		return []string{gobin}
+	}
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil
+		}
+		gopath = filepath.Join(home, "go")
+	}
+	var dirs []string
+	for _, dir := range filepath.SplitList(gopath) {
+		dirs = append(dirs, filepath.Join(dir, "bin"))
+	}
+	return dirs
+}
+
+func sameDir(a, b string) bool {
+	if resolved, err := filepath.EvalSymlinks(b); err == nil {
+		b = resolved
+	}
+	return filepath.Clean(a) == filepath.Clean(b)
+}
+
+// SelfUpdates reports whether lu update and lu rollback may replace the
+// binary, rather than leave it to the package manager that owns it.
+func (m InstallMethod) SelfUpdates() bool {
+	return m == SelfManaged
+}
+
+// UpdateCommand is what the user runs to upgrade an installation of this
+// kind.
+func (m InstallMethod) UpdateCommand() string {
+	switch m {
+	case Homebrew:
+		return "brew upgrade lu-hut"
+	case Scoop:
+		return "scoop update lu-hut"
+	case GoInstall:
+		return "go install " + modulePath + "@latest"
+	}
+	return "lu update"
+}
+
+// pinCommand is what the user runs to install a specific version with the
+// package manager, for rolling back.
+func (m InstallMethod) pinCommand() string {
+	switch m {
+	case Homebrew:
+		return "brew install lu-hut@<version>"
+	case Scoop:
+		return "scoop install lu-hut@<version>"
+	case GoInstall:
+		return "go install " + modulePath + "@<version>"
+	}
+	return ""
+}
+
+// refuseUpdate tells the user that a package manager owns the binary, and
+// how to update it instead. It reports whether it did.
+func refuseUpdate(method InstallMethod) bool {
+	if method.SelfUpdates() {
+		return false
+	}
+	color.Yellow("⚠ lu-hut was installed via %s", method)
+	color.Cyan("→ Please use '%s' to update", method.UpdateCommand())
+	return true
+}
@@ -2,38 +2,48 @@ package updater
 
 import (
 	"archive/tar"
+	"bytes"
 	"compress/gzip"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/ipanardian/lu-hut/internal/state"
 )
 
 const downloadTimeout = 5 * time.Minute
 
-func IsHomebrewInstallation() bool {
-	execPath, err := os.Executable()
-	if err != nil {
-		return false
-	}
+// lockWait is how long update and rollback wait for another lu process
+// replacing the binary before giving up.
+const lockWait = 2 * time.Second
 
-	execPath, _ = filepath.EvalSymlinks(execPath)
-
-	return strings.Contains(execPath, "/Cellar/") ||
-		strings.Contains(execPath, "homebrew") ||
-		strings.Contains(execPath, "/opt/homebrew/") ||
-		strings.Contains(execPath, "/usr/local/Cellar/")
+// lockBinary keeps two lu processes from replacing the binary at once. It
+// also reports whether it had to wait for another process to let go.
+func lockBinary() (func(), bool, error) {
+	unlock, err := state.Lock("update", 0)
+	if !errors.Is(err, state.ErrLocked) {
+		return unlock, false, err
+	}
+	unlock, err = state.Lock("update", lockWait)
+	if errors.Is(err, state.ErrLocked) {
+		return nil, true, fmt.Errorf("another lu update or rollback is running")
+	}
+	return unlock, true, err
 }
 
-func PerformUpdate(release *GitHubRelease) error {
-	if IsHomebrewInstallation() {
-		color.Yellow("⚠ lu-hut was installed via Homebrew")
-		color.Cyan("→ Please use 'brew upgrade lu-hut' to update")
+// PerformUpdate downloads release and installs it over the running binary.
+// Unless force is set, it stops when another lu update installed a version
+// at least as new while this one waited for the lock.
+func PerformUpdate(release *GitHubRelease, force bool) error {
+	if refuseUpdate(DetectInstall()) {
 		return nil
 	}
 
@@ -42,14 +52,24 @@ func PerformUpdate(release *GitHubRelease) error {
 		return err
 	}
 
-	execPath, err := os.Executable()
+	execPath, current, err := executable()
 	if err != nil {
-		return fmt.Errorf("failed to get executable path: %w", err)
+		return err
 	}
 
-	execPath, err = filepath.EvalSymlinks(execPath)
+	unlock, waited, err := lockBinary()
 	if err != nil {
-		return fmt.Errorf("failed to resolve symlinks: %w", err)
+		return err
+	}
+	defer unlock()
+
+	// Another lu update may have finished while this one waited for the
+	// lock; replacing the binary again would only overwrite the backup.
+	if waited && !force {
+		if installed, err := binaryVersion(execPath); err == nil && !IsNewerVersion(installed, release.TagName) {
+			color.Green("✓ Already updated to %s", installed)
+			return nil
+		}
 	}
 
 	color.Cyan("Downloading %s...", release.TagName)
@@ -70,76 +90,217 @@ func PerformUpdate(release *GitHubRelease) error {
 
 	color.Cyan("Extracting binary...")
 
-	gzr, err := gzip.NewReader(resp.Body)
+	binaryData, err := extractBinary(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	version, err := install(execPath, current, binaryData, nil)
+	if err != nil {
+		return err
+	}
+
+	color.Green("✓ Successfully updated to %s", version)
+	color.Yellow("→ Previous version backed up (use 'lu rollback' to restore)")
+	return nil
+}
+
+// PerformUpdateFrom installs lu from a file instead of GitHub, for machines
+// without internet access. The file is either the lu binary itself or a
+// release archive (.tar.gz) containing it. Unless force is set, it has to
+// be newer than the installed version.
+func PerformUpdateFrom(path string, force bool) error {
+	if refuseUpdate(DetectInstall()) {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return fmt.Errorf("failed to create gzip reader: %w", err)
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if bytes.HasPrefix(data, gzipMagic) {
+		color.Cyan("Extracting binary...")
+		if data, err = extractBinary(bytes.NewReader(data)); err != nil {
+			return err
+		}
+	}
+
+	execPath, current, err := executable()
+	if err != nil {
+		return err
+	}
+
+	unlock, _, err := lockBinary()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	installed, _ := binaryVersion(execPath)
+	if installed == "" {
+		installed = GetCurrentVersion()
+	}
+	accept := func(version string) error {
+		if !force && !IsNewerVersion(installed, version) {
+			return fmt.Errorf("%s contains %s, which is not newer than the installed %s (use --force to install it anyway)", path, version, installed)
+		}
+		return nil
+	}
+
+	version, err := install(execPath, current, data, accept)
+	if err != nil {
+		return err
+	}
+
+	color.Green("✓ Successfully updated to %s from %s", version, path)
+	color.Yellow("→ Previous version backed up (use 'lu rollback' to restore)")
+	return nil
+}
+
+// gzipMagic starts every gzip stream, and so every release archive.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// extractBinary returns the lu binary from a release archive.
+func extractBinary(r io.Reader) ([]byte, error) {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gzip reader: %w", err)
 	}
 	defer gzr.Close()
 
 	tr := tar.NewReader(gzr)
-
-	var binaryData []byte
 	for {
 		header, err := tr.Next()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
-			return fmt.Errorf("failed to read tar: %w", err)
+			return nil, fmt.Errorf("failed to read tar: %w", err)
 		}
 
 		if header.Name == "lu" {
-			binaryData, err = io.ReadAll(tr)
+			binaryData, err := io.ReadAll(tr)
 			if err != nil {
-				return fmt.Errorf("failed to read binary from archive: %w", err)
+				return nil, fmt.Errorf("failed to read binary from archive: %w", err)
+			}
+			if len(binaryData) > 0 {
+				return binaryData, nil
 			}
 			break
 		}
 	}
+	return nil, fmt.Errorf("binary 'lu' not found in archive")
+}
 
-	if len(binaryData) == 0 {
-		return fmt.Errorf("binary 'lu' not found in archive")
+// executable returns the path of the running lu binary, with symlinks
+// resolved, and what it looks like on disk.
+func executable() (string, os.FileInfo, error) {
+	execPath, err := os.Executable()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get executable path: %w", err)
 	}
 
-	tmpFile, err := os.CreateTemp("", "lu-update-*")
+	execPath, err = filepath.EvalSymlinks(execPath)
 	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
+		return "", nil, fmt.Errorf("failed to resolve symlinks: %w", err)
+	}
+
+	current, err := os.Stat(execPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to stat current binary: %w", err)
+	}
+	return execPath, current, nil
+}
+
+// install replaces the binary at execPath with binary, keeping the old one
+// as execPath.backup, and returns the version the new binary reports. The
+// new binary has to run with --version both before and after the swap; if
+// it fails after, the old binary is put back. When accept is set, it sees
+// the new version first and can refuse it. The caller holds the lock.
+func install(execPath string, current os.FileInfo, binary []byte, accept func(version string) error) (string, error) {
+	// The new binary is written next to the old one so the final rename
+	// stays on one filesystem.
+	tmpFile, err := os.CreateTemp(filepath.Dir(execPath), ".lu-update-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
 	}
 	tmpPath := tmpFile.Name()
 	defer os.Remove(tmpPath)
 
-	if _, err := tmpFile.Write(binaryData); err != nil {
+	if _, err := tmpFile.Write(binary); err != nil {
 		tmpFile.Close()
-		return fmt.Errorf("failed to write binary: %w", err)
+		return "", fmt.Errorf("failed to write binary: %w", err)
 	}
 	tmpFile.Close()
 
-	if err := os.Chmod(tmpPath, 0755); err != nil {
-		return fmt.Errorf("failed to set executable permissions: %w", err)
+	// The new binary takes over the mode and owner of the one it replaces,
+	// which may be 0700 in a home directory or root's under /usr/local.
+	if err := os.Chmod(tmpPath, current.Mode().Perm()|0o100); err != nil {
+		return "", fmt.Errorf("failed to set executable permissions: %w", err)
+	}
+	if err := state.MatchOwner(tmpPath, current); err != nil {
+		return "", fmt.Errorf("failed to set the binary's owner: %w", err)
+	}
+
+	// A binary for another platform or a truncated download fails here,
+	// before anything in place was touched.
+	staged, err := binaryVersion(tmpPath)
+	if err != nil {
+		return "", fmt.Errorf("new binary does not run: %w", err)
+	}
+	if accept != nil {
+		if err := accept(staged); err != nil {
+			return "", err
+		}
 	}
 
 	backupPath := execPath + ".backup"
 	if err := os.Rename(execPath, backupPath); err != nil {
-		return fmt.Errorf("failed to backup current binary: %w", err)
+		return "", fmt.Errorf("failed to backup current binary: %w", err)
 	}
 
 	if err := os.Rename(tmpPath, execPath); err != nil {
 		if err := os.Rename(backupPath, execPath); err != nil {
-			return fmt.Errorf("failed to replace binary: %w", err)
+			return "", fmt.Errorf("failed to replace binary: %w", err)
 		}
-		return fmt.Errorf("failed to replace binary: %w", err)
+		return "", fmt.Errorf("failed to replace binary: %w", err)
 	}
 
-	color.Green("✓ Successfully updated to %s", release.TagName)
-	color.Yellow("→ Previous version backed up (use 'lu rollback' to restore)")
-	return nil
+	version, err := binaryVersion(execPath)
+	if err != nil {
+		if restoreErr := os.Rename(backupPath, execPath); restoreErr != nil {
+			return "", fmt.Errorf("new binary does not run (%v) and restoring the previous one failed: %w", err, restoreErr)
+		}
+		return "", fmt.Errorf("new binary does not run, the previous version was restored: %w", err)
+	}
+	return version, nil
+}
+
+// verifyTimeout bounds how long a new binary may take to print its version.
+const verifyTimeout = 10 * time.Second
+
+// binaryVersion runs the lu binary at path with --version and returns the
+// version it reports.
+func binaryVersion(path string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), verifyTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, "--version").Output()
+	if err != nil {
+		return "", err
+	}
+	line := strings.TrimSpace(string(out))
+	version, ok := strings.CutPrefix(line, "lu version ")
+	if !ok || version == "" {
+		return "", fmt.Errorf("unexpected --version output %q", line)
+	}
+	return version, nil
 }
 
 func PerformRollback() error {
-	if IsHomebrewInstallation() {
-		color.Yellow("⚠ lu-hut was installed via Homebrew")
-		color.Cyan("→ Rollback is not supported for Homebrew installations")
-		color.Cyan("→ Use 'brew install lu-hut@<version>' to install a specific version")
+	if method := DetectInstall(); !method.SelfUpdates() {
+		color.Yellow("⚠ lu-hut was installed via %s", method)
+		color.Cyan("→ Rollback is not supported for %s installations", method)
+		color.Cyan("→ Use '%s' to install a specific version", method.pinCommand())
 		return nil
 	}
 
@@ -153,6 +314,12 @@ func PerformRollback() error {
 		return fmt.Errorf("failed to resolve symlinks: %w", err)
 	}
 
+	unlock, _, err := lockBinary()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	backupPath := execPath + ".backup"
 
 	if _, err := os.Stat(backupPath); os.IsNotExist(err) {
@@ -184,6 +351,14 @@ func CheckAndNotify() {
 		return
 	}
 
+	// Several shells starting lu at once would all ask GitHub; one is
+	// enough, and the others skip the check.
+	unlock, err := state.Lock("update-check", 0)
+	if err != nil {
+		return
+	}
+	defer unlock()
+
 	release, err := GetLatestVersion()
 	if err != nil {
 		return
@@ -200,28 +375,18 @@ func CheckAndNotify() {
 			cyan(release.TagName),
 			currentVersion)
 
-		if IsHomebrewInstallation() {
-			fmt.Fprintf(os.Stderr, "%s Run %s to upgrade\n\n",
-				yellow("→"),
-				cyan("brew upgrade lu-hut"))
-		} else {
-			fmt.Fprintf(os.Stderr, "%s Run %s to upgrade\n\n",
-				yellow("→"),
-				cyan("lu update"))
-		}
+		fmt.Fprintf(os.Stderr, "%s Run %s to upgrade\n\n",
+			yellow("→"),
+			cyan(DetectInstall().UpdateCommand()))
 	}
 }
 
 func getCacheFilePath() string {
-	homeDir, err := os.UserHomeDir()
+	path, err := state.CachePath("last_check")
 	if err != nil {
 		return ""
 	}
-
-	cacheDir := filepath.Join(homeDir, ".lu-hut")
-	_ = os.MkdirAll(cacheDir, 0755)
-
-	return filepath.Join(cacheDir, "last_check")
+	return path
 }
 
 func shouldSkipCheck(cacheFile string) bool {
@@ -242,5 +407,5 @@ func updateCacheFile(cacheFile string) {
 		return
 	}
 
-	_ = os.WriteFile(cacheFile, []byte(time.Now().Format(time.RFC3339)), 0644)
+	_ = state.WriteFile(cacheFile, []byte(time.Now().Format(time.RFC3339)), 0o644)
 }
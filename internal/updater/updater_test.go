@@ -0,0 +1,154 @@
+package updater
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"testing"
+	"time"
+)
+
+func TestInstall(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts as stand-in binaries")
+	}
+	script := func(version string) []byte {
+		return []byte("#!/bin/sh\necho 'lu version " + version + "'\n")
+	}
+	execPath := filepath.Join(t.TempDir(), "lu")
+	if err := os.WriteFile(execPath, script("v1.0.0"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	current, err := os.Stat(execPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	version, err := install(execPath, current, script("v1.1.0"), nil)
+	if err != nil || version != "v1.1.0" {
+		t.Fatalf("install() = %q, %v; want v1.1.0", version, err)
+	}
+	if backup, err := binaryVersion(execPath + ".backup"); err != nil || backup != "v1.0.0" {
+		t.Errorf("backup reports %q, %v; want v1.0.0", backup, err)
+	}
+	if info, err := os.Stat(execPath); err != nil || info.Mode().Perm() != 0o750 {
+		t.Errorf("new binary mode = %v, %v; want the old 0750", info.Mode().Perm(), err)
+	}
+
+	// A binary that does not run is refused before the old one is touched.
+	if _, err := install(execPath, current, []byte("#!/bin/sh\nexit 1\n"), nil); err == nil {
+		t.Fatal("install() accepted a binary that fails --version")
+	}
+	if installed, err := binaryVersion(execPath); err != nil || installed != "v1.1.0" {
+		t.Errorf("after a failed install the binary reports %q, %v; want v1.1.0", installed, err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(execPath))
+	if len(entries) != 2 {
+		t.Errorf("%d files next to the binary, want it and its backup", len(entries))
+	}
+}
+
+func TestExtractBinary(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range map[string]string{"README.md": "docs", "lu": "binary"} {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tw.Close()
+	gz.Close()
+
+	if !bytes.HasPrefix(buf.Bytes(), gzipMagic) {
+		t.Fatal("archive does not start with the gzip magic")
+	}
+	data, err := extractBinary(&buf)
+	if err != nil || string(data) != "binary" {
+		t.Errorf("extractBinary() = %q, %v; want the lu entry", data, err)
+	}
+}
+
+func TestInstallRefused(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts as stand-in binaries")
+	}
+	execPath := filepath.Join(t.TempDir(), "lu")
+	old := []byte("#!/bin/sh\necho 'lu version v2.0.0'\n")
+	if err := os.WriteFile(execPath, old, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	current, err := os.Stat(execPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refuse := func(version string) error {
+		if version != "v1.0.0" {
+			t.Errorf("accept saw %q, want v1.0.0", version)
+		}
+		return errors.New("not newer")
+	}
+	if _, err := install(execPath, current, []byte("#!/bin/sh\necho 'lu version v1.0.0'\n"), refuse); err == nil {
+		t.Fatal("install() went ahead after accept refused")
+	}
+	if data, _ := os.ReadFile(execPath); !bytes.Equal(data, old) {
+		t.Error("refused install changed the binary")
+	}
+	if _, err := os.Stat(execPath + ".backup"); !os.IsNotExist(err) {
+		t.Errorf("refused install left a backup: %v", err)
+	}
+}
+
+func TestDetectInstall(t *testing.T) {
+	module := &debug.BuildInfo{Main: debug.Module{Path: modulePath, Version: "v1.2.0", Sum: "h1:abc="}}
+	checkout := &debug.BuildInfo{Main: debug.Module{Path: modulePath, Version: "(devel)"}}
+	gobin := filepath.Join(string(filepath.Separator), "home", "dev", "go", "bin")
+
+	tests := []struct {
+		name string
+		path string
+		info *debug.BuildInfo
+		want InstallMethod
+	}{
+		{"cellar", "/usr/local/Cellar/lu-hut/1.2.0/bin/lu", checkout, Homebrew},
+		{"linuxbrew", "/home/linuxbrew/.linuxbrew/bin/lu", nil, Homebrew},
+		{"scoop", "C:/Users/dev/scoop/apps/lu-hut/current/lu.exe", nil, Scoop},
+		{"module build", "/home/dev/bin/lu", module, GoInstall},
+		{"go bin dir", filepath.Join(gobin, "lu"), checkout, GoInstall},
+		{"release binary", "/usr/local/bin/lu", checkout, SelfManaged},
+		{"no build info", "/opt/lu/lu", nil, SelfManaged},
+	}
+	for _, tt := range tests {
+		if got := detectInstall(tt.path, tt.info, []string{gobin}); got != tt.want {
+			t.Errorf("%s: detectInstall(%q) = %q, want %q", tt.name, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLockBinaryWaited(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	unlock, waited, err := lockBinary()
+	if err != nil || waited {
+		t.Fatalf("free lock: waited %v, error %v", waited, err)
+	}
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		unlock()
+	}()
+
+	unlock, waited, err = lockBinary()
+	if err != nil || !waited {
+		t.Fatalf("held lock: waited %v, error %v; want a wait", waited, err)
+	}
+	unlock()
+}